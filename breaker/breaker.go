@@ -0,0 +1,337 @@
+// Package breaker provides a circuit breaker, which stops calling a failing dependency for a while,
+// so that callers fail fast and the dependency can recover.
+//
+//	b := breaker.New(breaker.Config{Name: "payments"})
+//	resp, err := breaker.Do(ctx, b, func(ctx context.Context) (*Response, error) {
+//		return client.Charge(ctx, req)
+//	})
+//
+// The breaker is closed at first, and calls pass through. It opens when the failure rate in the sliding window exceeds
+// the threshold, and calls fail with ErrOpen. After the open timeout, it is half-open and lets trial calls through:
+// it closes if they succeed, and opens again if any of them fails.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aqyuki/util/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultWindow and defaultBuckets are defaults of the sliding window.
+	defaultWindow  = 10 * time.Second
+	defaultBuckets = 10
+
+	// defaultMinRequests is a default minimum number of calls in the window to open the breaker.
+	defaultMinRequests = 20
+
+	// defaultFailureRate is a default failure rate to open the breaker.
+	defaultFailureRate = 0.5
+
+	// defaultOpenTimeout is a default duration of the open state.
+	defaultOpenTimeout = 30 * time.Second
+)
+
+var (
+	// ErrOpen is returned when the breaker is open.
+	ErrOpen = errors.New("breaker: circuit is open")
+
+	// ErrTooManyRequests is returned when the breaker is half-open and trial calls are in flight.
+	ErrTooManyRequests = errors.New("breaker: too many requests while half-open")
+)
+
+// State is a state of a breaker.
+type State int
+
+const (
+	// Closed lets every call through.
+	Closed State = iota
+
+	// Open rejects every call.
+	Open
+
+	// HalfOpen lets a limited number of trial calls through.
+	HalfOpen
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Metrics receives results of calls of breakers. Use it to export metrics.
+type Metrics interface {
+	// ObserveCall is called with the result of each call let through, and its duration.
+	ObserveCall(name string, state State, err error, duration time.Duration)
+
+	// ObserveRejection is called when a call is rejected.
+	ObserveRejection(name string, state State)
+}
+
+// Config is a configuration of a breaker.
+type Config struct {
+	// Name is a name of the breaker used in logs and metrics.
+	Name string
+
+	// Window is a duration of the sliding window of failure rates. If zero, 10 seconds is used.
+	Window time.Duration
+
+	// Buckets is a number of buckets of the sliding window. If zero, 10 is used.
+	Buckets int
+
+	// MinRequests is a minimum number of calls in the window to open the breaker. If zero, 20 is used.
+	MinRequests int
+
+	// FailureRate is a rate of failed calls in the window to open the breaker. If zero, 0.5 is used.
+	FailureRate float64
+
+	// OpenTimeout is a duration of the open state before trial calls. If zero, 30 seconds is used.
+	OpenTimeout time.Duration
+
+	// HalfOpenRequests is a number of successful trial calls to close the breaker, and calls in flight while half-open.
+	// If zero, one is used.
+	HalfOpenRequests int
+
+	// IsFailure reports whether an error returned by a call is a failure of the dependency.
+	// If nil, every error except context.Canceled is a failure.
+	// Other errors count as neither successes nor failures.
+	IsFailure func(error) bool
+
+	// OnStateChange is called when the state changes, in addition to logging. It is called while the breaker is locked,
+	// so it must not call the breaker.
+	OnStateChange func(name string, from, to State)
+
+	// Metrics receives results of calls. If nil, they are not observed.
+	Metrics Metrics
+}
+
+// Breaker is a circuit breaker. It is safe for concurrent use.
+type Breaker struct {
+	config Config
+
+	// mu guards fields below.
+	mu    sync.Mutex
+	state State
+
+	// generation is incremented on each state change, so that results of calls started before it are ignored.
+	generation uint64
+
+	// window counts results while closed.
+	window *window
+
+	// openedAt is when the breaker opened.
+	openedAt time.Time
+
+	// inFlight and successes count trial calls while half-open.
+	inFlight  int
+	successes int
+
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+// New creates a closed breaker with given configuration.
+func New(config Config) *Breaker {
+	if config.Window <= 0 {
+		config.Window = defaultWindow
+	}
+	if config.Buckets <= 0 {
+		config.Buckets = defaultBuckets
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = defaultMinRequests
+	}
+	if config.FailureRate <= 0 {
+		config.FailureRate = defaultFailureRate
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = defaultOpenTimeout
+	}
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = 1
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = func(err error) bool { return !errors.Is(err, context.Canceled) }
+	}
+	return &Breaker{config: config, window: newWindow(config.Window, config.Buckets), now: time.Now}
+}
+
+// State returns the current state. It is half-open once the open timeout has passed,
+// although the change is logged on the next call.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openExpired() {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// Execute calls given function unless the breaker rejects it with ErrOpen or ErrTooManyRequests,
+// and records its result.
+func (b *Breaker) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	_, err := Do(ctx, b, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// Do calls given function via given breaker, as same as (*Breaker).Execute, and returns its result.
+// Errors which Config.IsFailure does not classify as failures, such as context.Canceled, count as neither
+// successes nor failures. A panic of the function counts as a failure and is propagated.
+func Do[T any](ctx context.Context, b *Breaker, fn func(ctx context.Context) (T, error)) (v T, err error) {
+	generation, state, err := b.before(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	start := time.Now()
+	completed := false
+	defer func() {
+		result := outcomeOf(b.config.IsFailure, err)
+		if !completed {
+			result = failure
+		}
+		if b.config.Metrics != nil {
+			b.config.Metrics.ObserveCall(b.config.Name, state, err, time.Since(start))
+		}
+		b.after(ctx, generation, result)
+	}()
+	v, err = fn(ctx)
+	completed = true
+	return v, err
+}
+
+// outcome is a result of a call recorded by a breaker.
+type outcome int
+
+const (
+	success outcome = iota
+	failure
+
+	// ignored is an error which is not a failure. It only releases the slot of a half-open trial.
+	ignored
+)
+
+// outcomeOf returns the outcome of given error.
+func outcomeOf(isFailure func(error) bool, err error) outcome {
+	switch {
+	case err == nil:
+		return success
+	case isFailure(err):
+		return failure
+	default:
+		return ignored
+	}
+}
+
+// before checks whether a call is allowed, and returns the generation and state it is started in.
+func (b *Breaker) before(ctx context.Context) (uint64, State, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.expireOpen(ctx)
+	switch b.state {
+	case Open:
+		b.reject()
+		return 0, b.state, ErrOpen
+	case HalfOpen:
+		if b.inFlight >= b.config.HalfOpenRequests {
+			b.reject()
+			return 0, b.state, ErrTooManyRequests
+		}
+		b.inFlight++
+	}
+	return b.generation, b.state, nil
+}
+
+// after records the outcome of a call started in given generation.
+func (b *Breaker) after(ctx context.Context, generation uint64, result outcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+	switch b.state {
+	case Closed:
+		if result == ignored {
+			return
+		}
+		b.window.add(b.now(), result == success)
+		total, failures := b.window.counts(b.now())
+		if total >= b.config.MinRequests && float64(failures)/float64(total) >= b.config.FailureRate {
+			b.setState(ctx, Open)
+		}
+	case HalfOpen:
+		b.inFlight--
+		switch result {
+		case ignored:
+			return
+		case failure:
+			b.setState(ctx, Open)
+			return
+		}
+		if b.successes++; b.successes >= b.config.HalfOpenRequests {
+			b.setState(ctx, Closed)
+		}
+	}
+}
+
+// reject observes a rejected call. b.mu must be held.
+func (b *Breaker) reject() {
+	if b.config.Metrics != nil {
+		b.config.Metrics.ObserveRejection(b.config.Name, b.state)
+	}
+}
+
+// expireOpen changes the state to half-open if the open timeout has passed. b.mu must be held.
+func (b *Breaker) expireOpen(ctx context.Context) {
+	if b.openExpired() {
+		b.setState(ctx, HalfOpen)
+	}
+}
+
+// openExpired reports whether the breaker is open and the open timeout has passed. b.mu must be held.
+func (b *Breaker) openExpired() bool {
+	return b.state == Open && !b.now().Before(b.openedAt.Add(b.config.OpenTimeout))
+}
+
+// setState changes the state, logs it with the logger of given context, and calls the callback. b.mu must be held.
+func (b *Breaker) setState(ctx context.Context, state State) {
+	from := b.state
+	b.state = state
+	b.generation++
+	b.inFlight, b.successes = 0, 0
+	switch state {
+	case Open:
+		b.openedAt = b.now()
+	case Closed:
+		b.window.reset()
+	}
+
+	logger := logging.StructuredFromContext(ctx)
+	fields := []zap.Field{zap.String("breaker", b.config.Name), zap.Stringer("from", from), zap.Stringer("to", state)}
+	if state == Open {
+		logger.Warn("breaker: state changed", append(fields, zap.Duration("open_timeout", b.config.OpenTimeout))...)
+	} else {
+		logger.Info("breaker: state changed", fields...)
+	}
+	if b.config.OnStateChange != nil {
+		b.config.OnStateChange(b.config.Name, from, state)
+	}
+}
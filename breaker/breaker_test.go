@@ -0,0 +1,321 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aqyuki/util/logging"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakeClock is a clock advanced manually.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestBreaker creates a breaker using a fake clock.
+func newTestBreaker(config Config) (*Breaker, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	b := New(config)
+	b.now = clock.Now
+	return b, clock
+}
+
+// fail calls given breaker with a function which fails.
+func fail(ctx context.Context, b *Breaker) error {
+	return b.Execute(ctx, func(context.Context) error { return errors.New("unavailable") })
+}
+
+// succeed calls given breaker with a function which succeeds.
+func succeed(ctx context.Context, b *Breaker) error {
+	return b.Execute(ctx, func(context.Context) error { return nil })
+}
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	b := New(Config{Name: "test"})
+	got, err := Do(context.Background(), b, func(context.Context) (string, error) {
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff("ok", got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(Closed, b.State()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestBreakerLifecycle(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := logging.WithStructuredLogger(context.Background(), zap.New(core))
+
+	var changes [][2]State
+	b, clock := newTestBreaker(Config{
+		Name:             "test",
+		MinRequests:      4,
+		FailureRate:      0.5,
+		OpenTimeout:      time.Minute,
+		HalfOpenRequests: 2,
+		OnStateChange: func(name string, from, to State) {
+			changes = append(changes, [2]State{from, to})
+		},
+	})
+
+	// One failure in four calls does not reach the rate.
+	_ = succeed(ctx, b)
+	_ = succeed(ctx, b)
+	_ = succeed(ctx, b)
+	_ = fail(ctx, b)
+	if diff := cmp.Diff(Closed, b.State()); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+	_ = fail(ctx, b)
+	_ = fail(ctx, b)
+	if diff := cmp.Diff(Open, b.State()); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+
+	called := false
+	err := b.Execute(ctx, func(context.Context) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrOpen) {
+		t.Errorf("expect %v, but received %v", ErrOpen, err)
+	}
+	if called {
+		t.Error("expect the function not to be called while open")
+	}
+
+	// A failed trial opens the breaker again.
+	clock.Advance(time.Minute)
+	if diff := cmp.Diff(HalfOpen, b.State()); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+	_ = fail(ctx, b)
+	if diff := cmp.Diff(Open, b.State()); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+
+	// Enough successful trials close the breaker.
+	clock.Advance(time.Minute)
+	_ = succeed(ctx, b)
+	if diff := cmp.Diff(HalfOpen, b.State()); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+	_ = succeed(ctx, b)
+	if diff := cmp.Diff(Closed, b.State()); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+
+	want := [][2]State{{Closed, Open}, {Open, HalfOpen}, {HalfOpen, Open}, {Open, HalfOpen}, {HalfOpen, Closed}}
+	if diff := cmp.Diff(want, changes); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	entries := logs.FilterMessage("breaker: state changed").AllUntimed()
+	if diff := cmp.Diff(len(want), len(entries)); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+	fields := entries[0].ContextMap()
+	if diff := cmp.Diff(map[string]any{
+		"breaker":      "test",
+		"from":         "closed",
+		"to":           "open",
+		"open_timeout": time.Minute,
+	}, fields); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(zap.WarnLevel, entries[0].Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestBreakerWindow(t *testing.T) {
+	t.Parallel()
+
+	b, clock := newTestBreaker(Config{MinRequests: 2, Window: 10 * time.Second, Buckets: 10})
+	ctx := context.Background()
+
+	// A failure out of the window is forgotten.
+	_ = fail(ctx, b)
+	clock.Advance(10 * time.Second)
+	_ = succeed(ctx, b)
+	_ = succeed(ctx, b)
+	_ = fail(ctx, b)
+	if diff := cmp.Diff(Closed, b.State()); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+
+	clock.Advance(5 * time.Second)
+	_ = fail(ctx, b)
+	if diff := cmp.Diff(Open, b.State()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestBreakerHalfOpenLimit(t *testing.T) {
+	t.Parallel()
+
+	b, clock := newTestBreaker(Config{MinRequests: 1, OpenTimeout: time.Second})
+	ctx := context.Background()
+	_ = fail(ctx, b)
+	clock.Advance(time.Second)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- b.Execute(ctx, func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	if err := succeed(ctx, b); !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("expect %v, but received %v", ErrTooManyRequests, err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff(Closed, b.State()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestBreakerIsFailure(t *testing.T) {
+	t.Parallel()
+
+	b := New(Config{MinRequests: 1})
+	err := b.Execute(context.Background(), func(context.Context) error { return context.Canceled })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expect %v, but received %v", context.Canceled, err)
+	}
+	if diff := cmp.Diff(Closed, b.State()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestBreakerIgnoredHalfOpenTrial(t *testing.T) {
+	t.Parallel()
+
+	b, clock := newTestBreaker(Config{MinRequests: 1, OpenTimeout: time.Second})
+	ctx := context.Background()
+	_ = fail(ctx, b)
+	clock.Advance(time.Second)
+
+	_ = b.Execute(ctx, func(context.Context) error { return context.Canceled })
+	if diff := cmp.Diff(HalfOpen, b.State()); diff != "" {
+		t.Fatalf("expect a canceled trial not to close the breaker (-want, +got)\n%s", diff)
+	}
+	if err := succeed(ctx, b); err != nil {
+		t.Errorf("expect the slot of the canceled trial to be released, but received %v", err)
+	}
+	if diff := cmp.Diff(Closed, b.State()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestBreakerIgnoredInWindow(t *testing.T) {
+	t.Parallel()
+
+	b, _ := newTestBreaker(Config{MinRequests: 2, FailureRate: 0.5})
+	ctx := context.Background()
+	_ = fail(ctx, b)
+	for range 5 {
+		_ = b.Execute(ctx, func(context.Context) error { return context.Canceled })
+	}
+	_ = fail(ctx, b)
+	if diff := cmp.Diff(Open, b.State()); diff != "" {
+		t.Errorf("expect cancellations not to dilute failures (-want, +got)\n%s", diff)
+	}
+}
+
+func TestBreakerPanic(t *testing.T) {
+	t.Parallel()
+
+	b, clock := newTestBreaker(Config{MinRequests: 1, OpenTimeout: time.Second})
+	ctx := context.Background()
+	_ = fail(ctx, b)
+	clock.Advance(time.Second)
+
+	func() {
+		defer func() {
+			if v := recover(); v != "boom" {
+				t.Errorf("expect the panic to be propagated, but received %v", v)
+			}
+		}()
+		_ = b.Execute(ctx, func(context.Context) error { panic("boom") })
+	}()
+	if diff := cmp.Diff(Open, b.State()); diff != "" {
+		t.Fatalf("expect a panicked trial to open the breaker (-want, +got)\n%s", diff)
+	}
+
+	clock.Advance(time.Second)
+	if err := succeed(ctx, b); err != nil {
+		t.Errorf("expect the slot of the panicked trial to be released, but received %v", err)
+	}
+}
+
+// recordingMetrics records observations of a breaker.
+type recordingMetrics struct {
+	calls      []string
+	rejections []State
+}
+
+func (m *recordingMetrics) ObserveCall(name string, state State, err error, duration time.Duration) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	m.calls = append(m.calls, name+":"+state.String()+":"+result)
+}
+
+func (m *recordingMetrics) ObserveRejection(name string, state State) {
+	m.rejections = append(m.rejections, state)
+}
+
+func TestBreakerMetrics(t *testing.T) {
+	t.Parallel()
+
+	metrics := &recordingMetrics{}
+	b := New(Config{Name: "test", MinRequests: 1, Metrics: metrics})
+	ctx := context.Background()
+	_ = succeed(ctx, b)
+	_ = fail(ctx, b)
+	_ = succeed(ctx, b)
+
+	if diff := cmp.Diff([]string{"test:closed:success", "test:closed:failure"}, metrics.calls); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]State{Open}, metrics.rejections); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
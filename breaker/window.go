@@ -0,0 +1,52 @@
+package breaker
+
+import "time"
+
+// window counts results of calls in a sliding window of time, divided into buckets.
+type window struct {
+	width   time.Duration
+	buckets []bucket
+}
+
+// bucket counts results in a span of time.
+type bucket struct {
+	// span is an index of the span of time counted by the bucket.
+	span     int64
+	total    int
+	failures int
+}
+
+// newWindow creates a window of given duration divided into given number of buckets.
+func newWindow(duration time.Duration, buckets int) *window {
+	return &window{width: max(duration/time.Duration(buckets), 1), buckets: make([]bucket, buckets)}
+}
+
+// add counts a result at given time.
+func (w *window) add(now time.Time, success bool) {
+	span := now.UnixNano() / int64(w.width)
+	b := &w.buckets[span%int64(len(w.buckets))]
+	if b.span != span {
+		*b = bucket{span: span}
+	}
+	b.total++
+	if !success {
+		b.failures++
+	}
+}
+
+// counts returns the number of results and failures in the window ending at given time.
+func (w *window) counts(now time.Time) (total, failures int) {
+	span := now.UnixNano() / int64(w.width)
+	for _, b := range w.buckets {
+		if span-b.span < int64(len(w.buckets)) {
+			total += b.total
+			failures += b.failures
+		}
+	}
+	return total, failures
+}
+
+// reset clears every bucket.
+func (w *window) reset() {
+	clear(w.buckets)
+}
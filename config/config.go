@@ -0,0 +1,252 @@
+// Package config provides layered configuration merged from sources such as defaults, files, environment variables,
+// and flags.
+//
+// Sources are given in order of increasing precedence, typically
+//
+//	config.New(config.Defaults(defaults), config.File("app.yaml"), config.Env("APP_"), config.Flags(flag.CommandLine))
+//
+// so that flags override environment variables, which override files, which override defaults.
+// Nested maps are merged key by key, and other values are replaced. Keys are case-insensitive,
+// and nested keys are addressed with dots such as "log.level".
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config is a configuration merged from sources. It is safe for concurrent use.
+type Config struct {
+	sources []Source
+
+	// mu guards fields below.
+	mu sync.RWMutex
+
+	// values is a merged tree of values.
+	values map[string]any
+
+	// origins maps dotted keys of leaf values to names of sources which supplied them.
+	origins map[string]string
+
+	// subscriptions are functions called by Reload when values of their keys are changed.
+	subscriptions []*subscription
+
+	// reloadMu serializes Reload, so that subscribers are notified in order of changes.
+	reloadMu sync.Mutex
+}
+
+// New loads given sources in order of increasing precedence, and merges them.
+func New(sources ...Source) (*Config, error) {
+	c := &Config{sources: sources}
+	values, origins, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	c.values, c.origins = values, origins
+	return c, nil
+}
+
+// load loads and merges every source.
+func (c *Config) load() (map[string]any, map[string]string, error) {
+	values := make(map[string]any)
+	origins := make(map[string]string)
+	for _, s := range c.sources {
+		tree, err := s.Load()
+		if err != nil {
+			return nil, nil, fmt.Errorf("config: failed to load %s: %w", s.Name(), err)
+		}
+		merge(values, tree, "", s.Name(), origins)
+	}
+	return values, origins, nil
+}
+
+// Get returns the value of given key, which may be a nested map. It reports false if the key is not set.
+func (c *Config) Get(key string) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return lookupPath(c.values, splitKey(key))
+}
+
+// IsSet reports whether given key is set.
+func (c *Config) IsSet(key string) bool {
+	_, ok := c.Get(key)
+	return ok
+}
+
+// Keys returns sorted dotted keys of every leaf value.
+func (c *Config) Keys() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := make([]string, 0, len(c.origins))
+	for key := range c.origins {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Origin returns the name of the source which supplied the value of given key, such as "env" or "file:app.yaml".
+func (c *Config) Origin(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	origin, ok := c.origins[strings.ToLower(key)]
+	return origin, ok
+}
+
+// String returns the value of given key as a string, or an empty string if it is not set or not convertible.
+func (c *Config) String(key string) string {
+	var v string
+	_ = c.Unmarshal(key, &v)
+	return v
+}
+
+// Int returns the value of given key as int, or zero if it is not set or not convertible.
+func (c *Config) Int(key string) int {
+	var v int
+	_ = c.Unmarshal(key, &v)
+	return v
+}
+
+// Bool returns the value of given key as bool, or false if it is not set or not convertible.
+func (c *Config) Bool(key string) bool {
+	var v bool
+	_ = c.Unmarshal(key, &v)
+	return v
+}
+
+// Float returns the value of given key as float64, or zero if it is not set or not convertible.
+func (c *Config) Float(key string) float64 {
+	var v float64
+	_ = c.Unmarshal(key, &v)
+	return v
+}
+
+// Duration returns the value of given key as time.Duration, or zero if it is not set or not convertible.
+// Strings such as "1m30s" and numbers of nanoseconds are convertible.
+func (c *Config) Duration(key string) time.Duration {
+	var v time.Duration
+	_ = c.Unmarshal(key, &v)
+	return v
+}
+
+// StringSlice returns the value of given key as a slice of strings, or nil if it is not set or not convertible.
+// A string is split as a comma separated list.
+func (c *Config) StringSlice(key string) []string {
+	var v []string
+	_ = c.Unmarshal(key, &v)
+	return v
+}
+
+// Unmarshal decodes the value of given key into given pointer. An empty key decodes the whole configuration.
+// Fields of structs are matched case-insensitively by `config` tags, `json` tags, or their names,
+// and strings are converted to numbers, booleans, durations, and slices as needed.
+// Decoded values are validated by Validate. If the key is not set, v is not modified or validated, and nil is returned.
+func (c *Config) Unmarshal(key string, v any) error {
+	value, ok := c.Get(key)
+	if !ok {
+		return nil
+	}
+	if err := decode(value, v); err != nil {
+		return fmt.Errorf("config: failed to unmarshal %q: %w", key, err)
+	}
+	return Validate(v)
+}
+
+// splitKey splits given dotted key into lowercased parts. An empty key has no parts.
+func splitKey(key string) []string {
+	if key == "" {
+		return nil
+	}
+	return strings.Split(strings.ToLower(key), ".")
+}
+
+// lookupPath returns the value at given path of given tree.
+func lookupPath(tree map[string]any, path []string) (any, bool) {
+	var value any = tree
+	for _, part := range path {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// setPath sets given value at given path of given tree, creating intermediate maps.
+func setPath(tree map[string]any, path []string, value any) {
+	for _, part := range path[:len(path)-1] {
+		next, ok := tree[part].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			tree[part] = next
+		}
+		tree = next
+	}
+	tree[path[len(path)-1]] = value
+}
+
+// merge merges given source tree into given destination tree, recording the origin of each leaf value.
+// Nested maps are merged, and other values are replaced.
+func merge(dst, src map[string]any, prefix, origin string, origins map[string]string) {
+	for key, value := range src {
+		full := key
+		if prefix != "" {
+			full = prefix + "." + key
+		}
+		if m, ok := value.(map[string]any); ok {
+			existing, ok := dst[key].(map[string]any)
+			if !ok {
+				dropOrigins(origins, full)
+				existing = make(map[string]any)
+				dst[key] = existing
+			}
+			merge(existing, m, full, origin, origins)
+			continue
+		}
+		dropOrigins(origins, full)
+		dst[key] = value
+		origins[full] = origin
+	}
+}
+
+// dropOrigins removes origins of given key and keys nested in it, which are about to be replaced.
+func dropOrigins(origins map[string]string, key string) {
+	delete(origins, key)
+	for k := range origins {
+		if strings.HasPrefix(k, key+".") {
+			delete(origins, k)
+		}
+	}
+}
+
+// normalize converts maps in given value to map[string]any with lowercased keys, recursively.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for key, elem := range v {
+			m[strings.ToLower(key)] = normalize(elem)
+		}
+		return m
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, elem := range v {
+			m[strings.ToLower(fmt.Sprint(key))] = normalize(elem)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, elem := range v {
+			s[i] = normalize(elem)
+		}
+		return s
+	default:
+		return value
+	}
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// writeFile writes given content to a file with given name in a temporary directory, and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	return path
+}
+
+func TestNewPrecedence(t *testing.T) {
+	t.Setenv("TEST_APP_LOG__LEVEL", "warn")
+	t.Setenv("TEST_APP_HTTP__READ_TIMEOUT", "3s")
+
+	file := writeFile(t, "app.yaml", "log:\n  level: info\n  encoding: json\nhttp:\n  port: 8080\n  read_timeout: 1s\n")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("http-port", 0, "")
+	fs.String("log-encoding", "console", "")
+	if err := fs.Parse([]string{"-http-port", "9090"}); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	c, err := New(
+		Defaults(map[string]any{"log.level": "debug", "log.color": true, "http": map[string]any{"Host": "localhost"}}),
+		File(file),
+		Env("TEST_APP_"),
+		Flags(fs),
+	)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	if diff := cmp.Diff("warn", c.String("log.level")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("json", c.String("LOG.Encoding")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if !c.Bool("log.color") {
+		t.Error("expect log.color from defaults, but not found")
+	}
+	if diff := cmp.Diff(9090, c.Int("http.port")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(3*time.Second, c.Duration("http.read_timeout")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	origins := make(map[string]string)
+	for _, key := range c.Keys() {
+		origins[key], _ = c.Origin(key)
+	}
+	want := map[string]string{
+		"log.level":         "env",
+		"log.encoding":      "file:" + file,
+		"log.color":         "default",
+		"http.host":         "default",
+		"http.port":         "flag",
+		"http.read_timeout": "env",
+	}
+	if diff := cmp.Diff(want, origins); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestFileFormats(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"app.yaml": "server:\n  port: 8080\n  tags: [a, b]\n  ratio: 0.5\n",
+		"app.json": `{"server": {"port": 8080, "tags": ["a", "b"], "ratio": 0.5}}`,
+		"app.toml": "[server]\nport = 8080\ntags = [\"a\", \"b\"]\nratio = 0.5\n",
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := New(File(writeFile(t, name, content)))
+			if err != nil {
+				t.Fatalf("expect no error, but received %v", err)
+			}
+			var got struct {
+				Port  int
+				Tags  []string
+				Ratio float64
+			}
+			if err := c.Unmarshal("server", &got); err != nil {
+				t.Fatalf("expect no error, but received %v", err)
+			}
+			if diff := cmp.Diff(8080, got.Port); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			if diff := cmp.Diff([]string{"a", "b"}, got.Tags); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(0.5, got.Ratio); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFileErrors(t *testing.T) {
+	t.Parallel()
+
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+	if _, err := New(File(missing)); err == nil {
+		t.Error("expect an error for missing file, but received nil")
+	}
+	if _, err := New(OptionalFile(missing)); err != nil {
+		t.Errorf("expect no error for optional file, but received %v", err)
+	}
+	if _, err := New(File(writeFile(t, "broken.json", "{"))); err == nil {
+		t.Error("expect an error for broken file, but received nil")
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	type Embedded struct {
+		Region string
+	}
+	type Server struct {
+		Embedded
+		Addr    string            `config:"address"`
+		Port    uint16            `json:"port"`
+		Timeout time.Duration     `json:"timeout"`
+		Debug   *bool             `json:"debug"`
+		Hosts   []string          `json:"hosts"`
+		Limits  map[string]int    `json:"limits"`
+		Labels  map[string]string `json:"-"`
+	}
+
+	c, err := New(Defaults(map[string]any{
+		"server": map[string]any{
+			"region":  "tokyo",
+			"address": "0.0.0.0",
+			"port":    "8080",
+			"timeout": "2s",
+			"debug":   "true",
+			"hosts":   "a, b",
+			"limits":  map[string]any{"read": 10, "write": "20"},
+			"labels":  map[string]any{"ignored": "x"},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	var got Server
+	if err := c.Unmarshal("server", &got); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	debug := true
+	want := Server{
+		Embedded: Embedded{Region: "tokyo"},
+		Addr:     "0.0.0.0",
+		Port:     8080,
+		Timeout:  2 * time.Second,
+		Debug:    &debug,
+		Hosts:    []string{"a", "b"},
+		Limits:   map[string]int{"read": 10, "write": 20},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	var port int
+	if err := c.Unmarshal("server.address", &port); err == nil {
+		t.Error("expect an error, but received nil")
+	}
+	if err := c.Unmarshal("missing", &port); err != nil || port != 0 {
+		t.Errorf("expect no error and zero value, but received %v and %d", err, port)
+	}
+}
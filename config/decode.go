@@ -0,0 +1,266 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// decode decodes given value of the tree into given pointer.
+func decode(value any, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("requires a non-nil pointer, but received %T", v)
+	}
+	return decodeValue(value, rv.Elem())
+}
+
+// decodeValue decodes given value into given settable value, converting types as needed.
+func decodeValue(value any, v reflect.Value) error {
+	if value == nil {
+		return nil
+	}
+
+	if v.Kind() == reflect.Pointer {
+		ptr := reflect.New(v.Type().Elem())
+		if !v.IsNil() {
+			ptr.Elem().Set(v.Elem())
+		}
+		if err := decodeValue(value, ptr.Elem()); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+		v.Set(reflect.ValueOf(value))
+		return nil
+	}
+	if v.Type() == timeType {
+		if t, ok := value.(time.Time); ok {
+			v.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+	if s, ok := scalarString(value); ok && v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if v.Type() == durationType {
+		return decodeDuration(value, v)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		s, ok := scalarString(value)
+		if !ok {
+			return fmt.Errorf("can not convert %T to string", value)
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		switch b := value.(type) {
+		case bool:
+			v.SetBool(b)
+		case string:
+			parsed, err := strconv.ParseBool(strings.TrimSpace(b))
+			if err != nil {
+				return err
+			}
+			v.SetBool(parsed)
+		default:
+			return fmt.Errorf("can not convert %T to bool", value)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s, ok := scalarString(value)
+		if !ok {
+			return fmt.Errorf("can not convert %T to %s", value, v.Type())
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s, ok := scalarString(value)
+		if !ok {
+			return fmt.Errorf("can not convert %T to %s", value, v.Type())
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		s, ok := scalarString(value)
+		if !ok {
+			return fmt.Errorf("can not convert %T to %s", value, v.Type())
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		return decodeSlice(value, v)
+	case reflect.Map:
+		return decodeMap(value, v)
+	case reflect.Struct:
+		return decodeStruct(value, v)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// decodeDuration decodes a string such as "1m30s", or a number of nanoseconds, into given duration.
+func decodeDuration(value any, v reflect.Value) error {
+	s, ok := scalarString(value)
+	if !ok {
+		return fmt.Errorf("can not convert %T to duration", value)
+	}
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		v.SetInt(n)
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	v.SetInt(int64(d))
+	return nil
+}
+
+// decodeSlice decodes a list, or a comma separated string, into given slice.
+func decodeSlice(value any, v reflect.Value) error {
+	var items []any
+	switch list := value.(type) {
+	case []any:
+		items = list
+	case string:
+		for _, item := range strings.Split(list, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	default:
+		return fmt.Errorf("can not convert %T to %s", value, v.Type())
+	}
+
+	s := reflect.MakeSlice(v.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := decodeValue(item, s.Index(i)); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	v.Set(s)
+	return nil
+}
+
+// decodeMap decodes a map into given map.
+func decodeMap(value any, v reflect.Value) error {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("can not convert %T to %s", value, v.Type())
+	}
+	if v.IsNil() {
+		v.Set(reflect.MakeMapWithSize(v.Type(), len(m)))
+	}
+	for key, elem := range m {
+		k := reflect.New(v.Type().Key()).Elem()
+		if err := decodeValue(key, k); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		e := reflect.New(v.Type().Elem()).Elem()
+		if err := decodeValue(elem, e); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		v.SetMapIndex(k, e)
+	}
+	return nil
+}
+
+// decodeStruct decodes a map into given struct, matching fields by their keys.
+// Embedded structs without a key are decoded from the same map.
+func decodeStruct(value any, v reflect.Value) error {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("can not convert %T to %s", value, v.Type())
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, ok := fieldKey(field)
+		if !ok {
+			continue
+		}
+		if field.Anonymous && key == "" {
+			if err := decodeValue(m, v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		elem, ok := m[key]
+		if !ok {
+			continue
+		}
+		if err := decodeValue(elem, v.Field(i)); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// fieldKey returns the lowercased key of given struct field: the name of the `config` tag, the `json` tag,
+// or the field name. It reports false if the field is excluded by a "-" tag.
+// It returns an empty key for embedded structs without a tag, whose fields are keyed in the same map.
+func fieldKey(field reflect.StructField) (string, bool) {
+	for _, tag := range []string{"config", "json"} {
+		if value, ok := field.Tag.Lookup(tag); ok {
+			name, _, _ := strings.Cut(value, ",")
+			if name == "-" {
+				return "", false
+			}
+			if name != "" {
+				return strings.ToLower(name), true
+			}
+		}
+	}
+	if field.Anonymous && field.Type.Kind() == reflect.Struct {
+		return "", true
+	}
+	return strings.ToLower(field.Name), true
+}
+
+// scalarString converts given scalar value to a string.
+func scalarString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case Secret:
+		return v.Reveal(), true
+	case json.Number:
+		return v.String(), true
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true
+	default:
+		return "", false
+	}
+}
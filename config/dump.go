@@ -0,0 +1,138 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DumpOption is an option of Dump.
+type DumpOption func(*dumpOptions)
+
+// dumpOptions is a set of options of Dump.
+type dumpOptions struct {
+	// redactKeys are lowercased substrings of keys whose values are masked.
+	redactKeys []string
+
+	// json reports whether the dump is written as JSON.
+	json bool
+}
+
+// RedactKeys masks values of keys containing any of given substrings, matched case-insensitively,
+// such as "password" matching "db.password" and "token" matching "api.refresh_token".
+// Secret values are masked regardless of it.
+func RedactKeys(keys ...string) DumpOption {
+	return func(o *dumpOptions) {
+		for _, key := range keys {
+			if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+				o.redactKeys = append(o.redactKeys, key)
+			}
+		}
+	}
+}
+
+// DumpJSON writes the dump as a JSON array of DumpEntry, for debugging endpoints.
+func DumpJSON() DumpOption {
+	return func(o *dumpOptions) {
+		o.json = true
+	}
+}
+
+// DumpEntry is an entry of Dump written by DumpJSON.
+type DumpEntry struct {
+	// Key is a dotted key of the value.
+	Key string `json:"key"`
+
+	// Value is the value, or "[REDACTED]" if it is masked.
+	Value any `json:"value"`
+
+	// Source is the name of the source which supplied the value, such as "env" or "file:app.yaml".
+	Source string `json:"source"`
+}
+
+// Dump writes the effective configuration to given writer, one leaf value per line sorted by key,
+// annotated with the source which supplied it:
+//
+//	db.password = [REDACTED]  # env
+//	http.port = 8080  # flag
+//	log.level = "warn"  # file:app.yaml
+//
+// It is intended for logging at startup and debugging endpoints, so secret values and values of keys given
+// to RedactKeys are masked.
+func (c *Config) Dump(w io.Writer, opts ...DumpOption) error {
+	o := &dumpOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	entries := c.dumpEntries(o)
+	if o.json {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	for _, entry := range entries {
+		value := redactedSecret
+		if entry.Value != redactedSecret {
+			b, err := json.Marshal(entry.Value)
+			if err != nil {
+				return fmt.Errorf("config: failed to encode %s: %w", entry.Key, err)
+			}
+			value = string(b)
+		}
+		if _, err := fmt.Fprintf(w, "%s = %s  # %s\n", entry.Key, value, entry.Source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dumpEntries returns entries of every leaf value, masked by given options.
+func (c *Config) dumpEntries(o *dumpOptions) []DumpEntry {
+	keys := c.Keys()
+	entries := make([]DumpEntry, 0, len(keys))
+	for _, key := range keys {
+		value, ok := c.Get(key)
+		if !ok {
+			continue
+		}
+		source, _ := c.Origin(key)
+		if o.redacted(key) || containsSecret(value) {
+			value = redactedSecret
+		}
+		entries = append(entries, DumpEntry{Key: key, Value: value, Source: source})
+	}
+	return entries
+}
+
+// redacted reports whether the value of given key is masked.
+func (o *dumpOptions) redacted(key string) bool {
+	for _, k := range o.redactKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsSecret reports whether given value is, or contains, a Secret.
+func containsSecret(value any) bool {
+	switch v := value.(type) {
+	case Secret:
+		return true
+	case []any:
+		for _, elem := range v {
+			if containsSecret(elem) {
+				return true
+			}
+		}
+	case map[string]any:
+		for _, elem := range v {
+			if containsSecret(elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
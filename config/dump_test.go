@@ -0,0 +1,70 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newDumpConfig creates a configuration with values from defaults, environment variables, and a secret.
+func newDumpConfig(t *testing.T) *Config {
+	t.Helper()
+
+	t.Setenv("TEST_DUMP_DB__PASSWORD", "p@ss")
+	t.Setenv("TEST_DUMP_LOG__LEVEL", "warn")
+	secrets := NewSecrets(0, map[string]SecretResolver{
+		"test": SecretResolverFunc(func(context.Context, string) (string, error) { return "secret", nil }),
+	})
+	c, err := New(
+		WithSecrets(Defaults(map[string]any{"api.key": "test:key", "http.port": 8080, "http.hosts": []any{"a", "test:host"}}), secrets),
+		Env("TEST_DUMP_"),
+	)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	return c
+}
+
+func TestDump(t *testing.T) {
+	c := newDumpConfig(t)
+
+	var buf bytes.Buffer
+	if err := c.Dump(&buf, RedactKeys("Password")); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	want := `api.key = [REDACTED]  # default
+db.password = [REDACTED]  # env
+http.hosts = [REDACTED]  # default
+http.port = 8080  # default
+log.level = "warn"  # env
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestDumpJSON(t *testing.T) {
+	c := newDumpConfig(t)
+
+	var buf bytes.Buffer
+	if err := c.Dump(&buf, DumpJSON()); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	var got []DumpEntry
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	want := []DumpEntry{
+		{Key: "api.key", Value: "[REDACTED]", Source: "default"},
+		{Key: "db.password", Value: "p@ss", Source: "env"},
+		{Key: "http.hosts", Value: "[REDACTED]", Source: "default"},
+		{Key: "http.port", Value: float64(8080), Source: "default"},
+		{Key: "log.level", Value: "warn", Source: "env"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// boundFlags is a Source of flags registered by BindFlags.
+type boundFlags struct {
+	fs *flag.FlagSet
+
+	// keys maps names of registered flags to dotted keys.
+	keys map[string]string
+}
+
+// BindFlags registers a flag to given flag set for each field of given pointer to struct, and returns a source of them.
+// Names of flags are keys of fields joined by "-", with "_" replaced by "-", so that the key "http.read_timeout"
+// is registered as -http-read-timeout (or --http-read-timeout). A `flag` tag overrides the name, and `flag:"-"` skips the field.
+// A `usage` tag is the description shown by -help, and current values of fields are shown as defaults.
+//
+// Only flags set explicitly on the command line are loaded by the source, so that it does not hide lower sources.
+// Give the source to New after parsing the flag set, at the position of the desired precedence:
+//
+//	flags := config.BindFlags(flag.CommandLine, &cfg)
+//	flag.Parse()
+//	c, err := config.New(config.Defaults(defaults), config.File(path), config.Env("APP_"), flags)
+//
+// Fields of structs are registered recursively. Fields of maps and slices of structs are not registered.
+// It panics if a flag is registered twice, as same as the flag package.
+func BindFlags(fs *flag.FlagSet, v any) Source {
+	s := &boundFlags{fs: fs, keys: make(map[string]string)}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("config: BindFlags requires a non-nil pointer to a struct, but received %T", v))
+	}
+	s.bind(rv.Elem(), nil, nil)
+	return s
+}
+
+// bind registers flags of fields of given struct, with given path of keys and names.
+func (s *boundFlags) bind(v reflect.Value, keys, names []string) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, ok := fieldKey(field)
+		name, named := field.Tag.Lookup("flag")
+		if !ok || name == "-" {
+			continue
+		}
+
+		fieldKeys, fieldNames := keys, names
+		if key != "" {
+			fieldKeys = append(append([]string(nil), keys...), key)
+			fieldNames = append(append([]string(nil), names...), strings.ReplaceAll(key, "_", "-"))
+		}
+
+		fv := v.Field(i)
+		t := field.Type
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(textUnmarshalerType) {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			s.bind(fv, fieldKeys, fieldNames)
+			continue
+		}
+		if !flaggable(t) {
+			continue
+		}
+
+		if !named {
+			name = strings.Join(fieldNames, "-")
+		}
+		s.keys[name] = strings.Join(fieldKeys, ".")
+		s.fs.Var(&boundFlag{value: formatFlagValue(fv), bool: t.Kind() == reflect.Bool}, name, field.Tag.Get("usage"))
+	}
+}
+
+// Name returns "flag".
+func (s *boundFlags) Name() string {
+	return "flag"
+}
+
+// Load returns values of registered flags set explicitly.
+func (s *boundFlags) Load() (map[string]any, error) {
+	tree := make(map[string]any)
+	s.fs.Visit(func(f *flag.Flag) {
+		key, ok := s.keys[f.Name]
+		if !ok {
+			return
+		}
+		setPath(tree, splitKey(key), f.Value.String())
+	})
+	return tree, nil
+}
+
+// boundFlag is a flag.Value holding the raw value of a flag, decoded later by Unmarshal.
+type boundFlag struct {
+	value string
+	bool  bool
+}
+
+// String returns the value.
+func (f *boundFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+// Set sets the value.
+func (f *boundFlag) Set(value string) error {
+	f.value = value
+	return nil
+}
+
+// IsBoolFlag reports whether the flag is a boolean, which can be given without a value such as -debug.
+func (f *boundFlag) IsBoolFlag() bool {
+	return f.bool
+}
+
+// flaggable reports whether a field of given type can be given as a flag.
+func flaggable(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) || t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Struct && flaggable(t.Elem())
+	default:
+		return false
+	}
+}
+
+// formatFlagValue formats the current value of given field as the default of its flag.
+func formatFlagValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.IsZero() {
+		return ""
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		if err == nil {
+			return string(b)
+		}
+	}
+	if v.Kind() == reflect.Slice {
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatFlagValue(v.Index(i))
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testFlagConfig struct {
+	Log struct {
+		Level string `json:"level" usage:"minimum level of logs"`
+		Color bool   `json:"color" usage:"color levels"`
+	} `json:"log"`
+	HTTP struct {
+		Port        int           `json:"port" usage:"port to listen"`
+		ReadTimeout time.Duration `json:"read_timeout" usage:"timeout of reading requests"`
+		Hosts       []string      `json:"hosts" flag:"host" usage:"allowed hosts"`
+	} `json:"http"`
+	Ignored string            `json:"ignored" flag:"-"`
+	Labels  map[string]string `json:"labels"`
+}
+
+func TestBindFlags(t *testing.T) {
+	t.Setenv("TEST_FLAGS_HTTP__PORT", "8080")
+	t.Setenv("TEST_FLAGS_LOG__LEVEL", "warn")
+
+	var cfg testFlagConfig
+	cfg.HTTP.ReadTimeout = 5 * time.Second
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := BindFlags(fs, &cfg)
+	if err := fs.Parse([]string{"--http-port", "9090", "-log-color", "-host", "a,b"}); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	c, err := New(Defaults(map[string]any{"http.read_timeout": "1s", "log.level": "info"}), Env("TEST_FLAGS_"), flags)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if err := c.Unmarshal("", &cfg); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	var want testFlagConfig
+	want.Log.Level = "warn"
+	want.Log.Color = true
+	want.HTTP.Port = 9090
+	want.HTTP.ReadTimeout = time.Second
+	want.HTTP.Hosts = []string{"a", "b"}
+	if diff := cmp.Diff(want, cfg); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if origin, _ := c.Origin("http.port"); origin != "flag" {
+		t.Errorf("expect flag, but received %s", origin)
+	}
+}
+
+func TestBindFlagsHelp(t *testing.T) {
+	t.Parallel()
+
+	var cfg testFlagConfig
+	cfg.HTTP.ReadTimeout = 5 * time.Second
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	BindFlags(fs, &cfg)
+
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, f.Name) })
+	if diff := cmp.Diff([]string{"host", "http-port", "http-read-timeout", "log-color", "log-level"}, names); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	for _, s := range []string{"timeout of reading requests (default 5s)", "minimum level of logs", "-log-color\n"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("expect help to contain %q, but received\n%s", s, buf.String())
+		}
+	}
+
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse([]string{"-help"}); err != flag.ErrHelp {
+		t.Errorf("expect %v, but received %v", flag.ErrHelp, err)
+	}
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// remoteTimeout is a timeout of reading keys from a remote store.
+const remoteTimeout = 10 * time.Second
+
+// KVPair is a key and value read from a remote store.
+type KVPair struct {
+	Key   string
+	Value []byte
+}
+
+// RemoteStore reads keys from a key-value store such as etcd or Consul.
+// It is implemented by stores of NewEtcdStore and NewConsulStore, or by thin adapters of other clients.
+type RemoteStore interface {
+	// List returns every key under given prefix, and a revision of the store which changes when any of them changes,
+	// such as the revision of etcd or the index of Consul.
+	List(ctx context.Context, prefix string) ([]KVPair, uint64, error)
+}
+
+// remoteSource is a Source of keys in a remote store.
+type remoteSource struct {
+	name   string
+	store  RemoteStore
+	prefix string
+
+	// mu guards revision.
+	mu sync.Mutex
+
+	// revision is the revision of the last Load.
+	revision uint64
+}
+
+var _ Watchable = (*remoteSource)(nil)
+
+// Remote returns a source of keys under given prefix of given store, such as "app/" of "app/log/level".
+// The prefix is removed, and the rest is lowercased with "/" separating nested keys, so that "app/log/level" is "log.level".
+// Keys ending with "/" are ignored as directories, and values are strings.
+// The source is Watchable, so that (*Config).Watch polls the store and reloads fleet-wide changes.
+func Remote(name string, store RemoteStore, prefix string) Source {
+	return &remoteSource{name: name, store: store, prefix: prefix}
+}
+
+// Name returns the name followed by the prefix, such as "etcd:app/".
+func (s *remoteSource) Name() string {
+	return s.name + ":" + s.prefix
+}
+
+// Load reads keys under the prefix.
+func (s *remoteSource) Load() (map[string]any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteTimeout)
+	defer cancel()
+	pairs, revision, err := s.store.List(ctx, s.prefix)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.revision = revision
+	s.mu.Unlock()
+
+	tree := make(map[string]any)
+	for _, pair := range pairs {
+		key := strings.Trim(strings.TrimPrefix(pair.Key, s.prefix), "/")
+		if key == "" || strings.HasSuffix(pair.Key, "/") {
+			continue
+		}
+		setPath(tree, strings.Split(strings.ToLower(key), "/"), string(pair.Value))
+	}
+	return tree, nil
+}
+
+// Changed reports whether the revision of the store is changed since the last Load.
+// Failures of reading the store are reported as a change, so that Reload reports them.
+func (s *remoteSource) Changed() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteTimeout)
+	defer cancel()
+	_, revision, err := s.store.List(ctx, s.prefix)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return err != nil || revision != s.revision
+}
+
+// RemoteConfig is a configuration of a remote store accessed over HTTP.
+type RemoteConfig struct {
+	// Address is a base URL of the store, such as "http://127.0.0.1:2379" of etcd or "http://127.0.0.1:8500" of Consul.
+	Address string
+
+	// Token is a token for authentication. It is sent as Authorization header to etcd, and X-Consul-Token header to Consul.
+	// If empty, no token is sent.
+	Token string
+
+	// Client is a client of HTTP requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+}
+
+// client returns the client of HTTP requests.
+func (c RemoteConfig) client() *http.Client {
+	if c.Client != nil {
+		return c.Client
+	}
+	return http.DefaultClient
+}
+
+// etcdStore is a RemoteStore of etcd accessed by the JSON gateway of its v3 API.
+type etcdStore struct {
+	config RemoteConfig
+}
+
+// NewEtcdStore creates a store reading keys from etcd via the JSON gateway of the v3 API.
+func NewEtcdStore(config RemoteConfig) RemoteStore {
+	return &etcdStore{config: config}
+}
+
+// List reads keys under given prefix by a range request.
+func (s *etcdStore) List(ctx context.Context, prefix string) ([]KVPair, uint64, error) {
+	body, err := json.Marshal(map[string]string{
+		"key":       base64.StdEncoding.EncodeToString([]byte(prefix)),
+		"range_end": base64.StdEncoding.EncodeToString(prefixEnd(prefix)),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(s.config.Address, "/")+"/v3/kv/range", bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, fmt.Errorf("config: failed to create etcd request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.config.Token != "" {
+		req.Header.Set("Authorization", s.config.Token)
+	}
+
+	var resp struct {
+		Header struct {
+			Revision json.Number `json:"revision"`
+		} `json:"header"`
+		KVs []struct {
+			Key   []byte `json:"key"`
+			Value []byte `json:"value"`
+		} `json:"kvs"`
+	}
+	if _, err := doJSON(s.config.client(), req, "etcd", &resp); err != nil {
+		return nil, 0, err
+	}
+	revision, _ := strconv.ParseUint(resp.Header.Revision.String(), 10, 64)
+	pairs := make([]KVPair, 0, len(resp.KVs))
+	for _, kv := range resp.KVs {
+		pairs = append(pairs, KVPair{Key: string(kv.Key), Value: kv.Value})
+	}
+	return pairs, revision, nil
+}
+
+// prefixEnd returns the end of the range of keys starting with given prefix, as same as clientv3.GetPrefixRangeEnd.
+func prefixEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// Every byte is 0xff, so the range is every key after the prefix.
+	return []byte{0}
+}
+
+// consulStore is a RemoteStore of the key-value store of Consul.
+type consulStore struct {
+	config RemoteConfig
+}
+
+// NewConsulStore creates a store reading keys from the key-value store of Consul via its HTTP API.
+func NewConsulStore(config RemoteConfig) RemoteStore {
+	return &consulStore{config: config}
+}
+
+// List reads keys under given prefix by a recursive request.
+func (s *consulStore) List(ctx context.Context, prefix string) ([]KVPair, uint64, error) {
+	u := strings.TrimRight(s.config.Address, "/") + "/v1/kv/" + strings.ReplaceAll(url.PathEscape(prefix), "%2F", "/") + "?recurse=true"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("config: failed to create consul request: %w", err)
+	}
+	if s.config.Token != "" {
+		req.Header.Set("X-Consul-Token", s.config.Token)
+	}
+
+	var entries []struct {
+		Key   string `json:"Key"`
+		Value []byte `json:"Value"`
+	}
+	header, err := doJSON(s.config.client(), req, "consul", &entries)
+	if err != nil {
+		return nil, 0, err
+	}
+	index, _ := strconv.ParseUint(header.Get("X-Consul-Index"), 10, 64)
+	pairs := make([]KVPair, 0, len(entries))
+	for _, entry := range entries {
+		pairs = append(pairs, KVPair{Key: entry.Key, Value: entry.Value})
+	}
+	return pairs, index, nil
+}
+
+// doJSON sends given request and decodes the JSON response into given value.
+// Not found responses are treated as empty, because there is no key under the prefix.
+func doJSON(client *http.Client, req *http.Request, store string, v any) (http.Header, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("config: failed to request %s: %w", store, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp.Header, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("config: %s responded %s: %s", store, resp.Status, bytes.TrimSpace(b))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, fmt.Errorf("config: failed to decode response of %s: %w", store, err)
+	}
+	return resp.Header, nil
+}
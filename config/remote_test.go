@@ -0,0 +1,134 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newFakeEtcd starts a server of the etcd JSON gateway serving given pairs, and returns a pointer to its revision.
+func newFakeEtcd(t *testing.T, pairs map[string]string) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	revision := &atomic.Int64{}
+	revision.Store(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/kv/range" || r.Header.Get("Authorization") != "token" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		var req struct {
+			Key      []byte `json:"key"`
+			RangeEnd []byte `json:"range_end"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var kvs []map[string]string
+		for key, value := range pairs {
+			if key >= string(req.Key) && key < string(req.RangeEnd) {
+				kvs = append(kvs, map[string]string{
+					"key":   base64.StdEncoding.EncodeToString([]byte(key)),
+					"value": base64.StdEncoding.EncodeToString([]byte(value)),
+				})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"header": map[string]string{"revision": strconv.FormatInt(revision.Load(), 10)},
+			"kvs":    kvs,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, revision
+}
+
+func TestEtcdStore(t *testing.T) {
+	t.Parallel()
+
+	pairs := map[string]string{"app/log/level": "warn", "app/http/port": "8080", "other/key": "ignored"}
+	server, revision := newFakeEtcd(t, pairs)
+
+	source := Remote("etcd", NewEtcdStore(RemoteConfig{Address: server.URL, Token: "token"}), "app/")
+	c, err := New(Defaults(map[string]any{"log.level": "info"}), source)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff("warn", c.String("log.level")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(8080, c.Int("http.port")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if c.IsSet("key") {
+		t.Error("expect keys out of the prefix to be ignored, but set")
+	}
+	if origin, _ := c.Origin("log.level"); origin != "etcd:app/" {
+		t.Errorf("expect etcd:app/, but received %s", origin)
+	}
+
+	if c.changed() {
+		t.Error("expect no change, but changed")
+	}
+	revision.Add(1)
+	if !c.changed() {
+		t.Error("expect change after revision, but not changed")
+	}
+}
+
+func TestConsulStore(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/app/" || r.URL.Query().Get("recurse") != "true" || r.Header.Get("X-Consul-Token") != "token" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-Consul-Index", "42")
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"Key": "app/", "Value": nil},
+			{"Key": "app/log/level", "Value": base64.StdEncoding.EncodeToString([]byte("debug"))},
+		})
+	}))
+	defer server.Close()
+
+	store := NewConsulStore(RemoteConfig{Address: server.URL, Token: "token"})
+	c, err := New(Remote("consul", store, "app/"))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff([]string{"log.level"}, c.Keys()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("debug", c.String("log.level")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	// A prefix without keys is empty.
+	empty, err := New(Remote("consul", NewConsulStore(RemoteConfig{Address: server.URL}), "missing/"))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if len(empty.Keys()) != 0 {
+		t.Errorf("expect no keys, but received %v", empty.Keys())
+	}
+}
+
+func TestRemoteStoreError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if _, err := New(Remote("etcd", NewEtcdStore(RemoteConfig{Address: server.URL}), "app/")); err == nil {
+		t.Error("expect an error, but received nil")
+	}
+}
@@ -0,0 +1,307 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+const (
+	// redactedSecret is written in place of secret values.
+	redactedSecret = "[REDACTED]"
+
+	// secretTimeout is a timeout of resolving a secret.
+	secretTimeout = 10 * time.Second
+)
+
+// Secret is a value resolved from a secret reference. It is redacted when formatted or encoded,
+// so that dumping or logging the configuration does not leak it. Use Reveal, Unmarshal, or getters to read it.
+type Secret string
+
+// Reveal returns the secret value.
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// String returns a redacted placeholder.
+func (s Secret) String() string {
+	return redactedSecret
+}
+
+// GoString returns a redacted placeholder.
+func (s Secret) GoString() string {
+	return redactedSecret
+}
+
+// MarshalText returns a redacted placeholder.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(redactedSecret), nil
+}
+
+// MarshalJSON returns a redacted placeholder.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactedSecret)
+}
+
+// SecretResolver fetches a secret from a secret store.
+type SecretResolver interface {
+	// ResolveSecret returns the secret referenced by given reference, without its scheme.
+	ResolveSecret(ctx context.Context, ref string) (string, error)
+}
+
+// SecretResolverFunc is a function implementing SecretResolver.
+type SecretResolverFunc func(ctx context.Context, ref string) (string, error)
+
+// ResolveSecret calls the function.
+func (f SecretResolverFunc) ResolveSecret(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+// Secrets resolves secret references with resolvers by scheme, and caches resolved secrets.
+type Secrets struct {
+	ttl       time.Duration
+	resolvers map[string]SecretResolver
+
+	// mu guards cache.
+	mu    sync.Mutex
+	cache map[string]cachedSecret
+}
+
+// cachedSecret is a resolved secret.
+type cachedSecret struct {
+	value   string
+	expires time.Time
+}
+
+// NewSecrets creates Secrets with given resolvers keyed by schemes such as "vault" or "awssm".
+// Resolved secrets are cached for given TTL, and fetched again on reload after it. Zero TTL caches them forever.
+func NewSecrets(ttl time.Duration, resolvers map[string]SecretResolver) *Secrets {
+	return &Secrets{ttl: ttl, resolvers: resolvers, cache: make(map[string]cachedSecret)}
+}
+
+// resolve returns the secret of given value if it is a reference with a known scheme.
+func (s *Secrets) resolve(value string) (Secret, bool, error) {
+	scheme, ref, ok := strings.Cut(value, ":")
+	if !ok {
+		return "", false, nil
+	}
+	resolver, ok := s.resolvers[scheme]
+	if !ok {
+		return "", false, nil
+	}
+
+	s.mu.Lock()
+	cached, ok := s.cache[value]
+	s.mu.Unlock()
+	if ok && (cached.expires.IsZero() || time.Now().Before(cached.expires)) {
+		return Secret(cached.value), true, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+	secret, err := resolver.ResolveSecret(ctx, ref)
+	if err != nil {
+		return "", true, fmt.Errorf("config: failed to resolve secret %s:%s: %w", scheme, ref, err)
+	}
+
+	cached = cachedSecret{value: secret}
+	if s.ttl > 0 {
+		cached.expires = time.Now().Add(s.ttl)
+	}
+	s.mu.Lock()
+	s.cache[value] = cached
+	s.mu.Unlock()
+	return Secret(secret), true, nil
+}
+
+// expired reports whether any of given references has expired.
+func (s *Secrets) expired(refs []string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for _, ref := range refs {
+		if cached, ok := s.cache[ref]; !ok || (!cached.expires.IsZero() && !now.Before(cached.expires)) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretSource is a Source resolving secret references in values of another source.
+type secretSource struct {
+	source  Source
+	secrets *Secrets
+
+	// mu guards refs.
+	mu sync.Mutex
+
+	// refs are references found in the last Load.
+	refs []string
+}
+
+var _ Watchable = (*secretSource)(nil)
+
+// WithSecrets returns a source which resolves secret references in string values of given source,
+// such as "vault:secret/data/db#password" or "awssm:my-secret", into Secret values.
+// Strings without a scheme of given Secrets are kept as they are.
+// The source is Watchable, so that (*Config).Watch fetches secrets again after their TTL.
+func WithSecrets(source Source, secrets *Secrets) Source {
+	return &secretSource{source: source, secrets: secrets}
+}
+
+// Name returns the name of the wrapped source.
+func (s *secretSource) Name() string {
+	return s.source.Name()
+}
+
+// Load loads the wrapped source and resolves secret references in it.
+func (s *secretSource) Load() (map[string]any, error) {
+	tree, err := s.source.Load()
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	resolved, err := s.resolveValue(tree, &refs)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.refs = refs
+	s.mu.Unlock()
+	return resolved.(map[string]any), nil
+}
+
+// Changed reports whether the wrapped source is changed, or any of secrets has expired.
+func (s *secretSource) Changed() bool {
+	if w, ok := s.source.(Watchable); ok && w.Changed() {
+		return true
+	}
+	s.mu.Lock()
+	refs := s.refs
+	s.mu.Unlock()
+	return s.secrets.expired(refs)
+}
+
+// resolveValue resolves secret references in given value recursively, and appends them to given references.
+func (s *secretSource) resolveValue(value any, refs *[]string) (any, error) {
+	switch v := value.(type) {
+	case string:
+		secret, ok, err := s.secrets.resolve(v)
+		if err != nil || !ok {
+			return v, err
+		}
+		*refs = append(*refs, v)
+		return secret, nil
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for key, elem := range v {
+			resolved, err := s.resolveValue(elem, refs)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = resolved
+		}
+		return m, nil
+	case []any:
+		list := make([]any, len(v))
+		for i, elem := range v {
+			resolved, err := s.resolveValue(elem, refs)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = resolved
+		}
+		return list, nil
+	default:
+		return value, nil
+	}
+}
+
+// IsSecret reports whether the value of given key is a Secret.
+func (c *Config) IsSecret(key string) bool {
+	value, ok := c.Get(key)
+	if !ok {
+		return false
+	}
+	_, ok = value.(Secret)
+	return ok
+}
+
+// VaultReader reads a secret at a path of Vault. It is implemented by a thin adapter of a Vault client,
+// such as a wrapper of (*vault.Logical).ReadWithContext returning Data of the secret.
+type VaultReader interface {
+	Read(ctx context.Context, path string) (map[string]any, error)
+}
+
+// NewVaultResolver creates a resolver of references such as "secret/data/db#password",
+// the path of the secret and the key of its data. Data of KV version 2, nested in "data", is unwrapped.
+func NewVaultResolver(reader VaultReader) SecretResolver {
+	return SecretResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		path, key, ok := strings.Cut(ref, "#")
+		if !ok || key == "" {
+			return "", fmt.Errorf("vault reference %q has no key such as path#key", ref)
+		}
+		data, err := reader.Read(ctx, path)
+		if err != nil {
+			return "", err
+		}
+		if nested, ok := data["data"].(map[string]any); ok {
+			data = nested
+		}
+		value, ok := data[key]
+		if !ok {
+			return "", fmt.Errorf("vault secret %s has no key %q", path, key)
+		}
+		return fmt.Sprint(value), nil
+	})
+}
+
+// SecretsManagerClient is a client of AWS Secrets Manager. It is implemented by *secretsmanager.Client.
+type SecretsManagerClient interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// NewSecretsManagerResolver creates a resolver of references such as "my-secret", the name or ARN of the secret,
+// optionally followed by "#key" to read a key of a JSON secret.
+func NewSecretsManagerResolver(client SecretsManagerClient) SecretResolver {
+	return SecretResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		id, key, _ := strings.Cut(ref, "#")
+		output, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(id)})
+		if err != nil {
+			return "", err
+		}
+		secret := aws.ToString(output.SecretString)
+		if key == "" {
+			return secret, nil
+		}
+
+		var data map[string]any
+		if err := json.Unmarshal([]byte(secret), &data); err != nil {
+			return "", fmt.Errorf("secret %s is not a JSON object: %w", id, err)
+		}
+		value, ok := data[key]
+		if !ok {
+			return "", fmt.Errorf("secret %s has no key %q", id, key)
+		}
+		return fmt.Sprint(value), nil
+	})
+}
+
+// NewFileResolver creates a resolver of references which are paths of files, such as "/run/secrets/db_password".
+// Trailing line endings of the files are removed.
+func NewFileResolver() SecretResolver {
+	return SecretResolverFunc(func(_ context.Context, ref string) (string, error) {
+		b, err := os.ReadFile(ref)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	})
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeVault is a VaultReader of fixed secrets.
+type fakeVault map[string]map[string]any
+
+func (v fakeVault) Read(_ context.Context, path string) (map[string]any, error) {
+	data, ok := v[path]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+// fakeSecretsManager is a SecretsManagerClient of fixed secrets.
+type fakeSecretsManager map[string]string
+
+func (m fakeSecretsManager) GetSecretValue(_ context.Context, params *secretsmanager.GetSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	secret, ok := m[aws.ToString(params.SecretId)]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(secret)}, nil
+}
+
+func TestWithSecrets(t *testing.T) {
+	t.Parallel()
+
+	secrets := NewSecrets(0, map[string]SecretResolver{
+		"vault": NewVaultResolver(fakeVault{"secret/data/db": {"data": map[string]any{"password": "p@ss"}}}),
+		"awssm": NewSecretsManagerResolver(fakeSecretsManager{"api": "token", "json": `{"key": "value"}`}),
+		"file":  NewFileResolver(),
+	})
+	path := writeFile(t, "secret", "from-file\n")
+	c, err := New(WithSecrets(Defaults(map[string]any{
+		"db.password": "vault:secret/data/db#password",
+		"api.token":   "awssm:api",
+		"api.key":     "awssm:json#key",
+		"api.file":    "file:" + path,
+		"api.list":    []any{"awssm:api", "plain"},
+		"api.url":     "https://example.com",
+	}), secrets))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	want := map[string]string{
+		"db.password": "p@ss",
+		"api.token":   "token",
+		"api.key":     "value",
+		"api.file":    "from-file",
+		"api.url":     "https://example.com",
+	}
+	for key, value := range want {
+		if diff := cmp.Diff(value, c.String(key)); diff != "" {
+			t.Errorf("%s: (-want, +got)\n%s", key, diff)
+		}
+	}
+	if diff := cmp.Diff([]string{"token", "plain"}, c.StringSlice("api.list")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if !c.IsSecret("db.password") || c.IsSecret("api.url") {
+		t.Error("expect only secret references to be secrets")
+	}
+
+	db, _ := c.Get("db")
+	for _, s := range []string{fmt.Sprint(db), fmt.Sprintf("%#v", db), mustMarshal(t, db)} {
+		if strings.Contains(s, "p@ss") {
+			t.Errorf("expect redacted, but received %s", s)
+		}
+	}
+}
+
+func TestWithSecretsErrors(t *testing.T) {
+	t.Parallel()
+
+	secrets := NewSecrets(0, map[string]SecretResolver{
+		"vault": NewVaultResolver(fakeVault{"secret/db": {"user": "app"}}),
+	})
+	for _, ref := range []string{"vault:secret/db", "vault:secret/db#password", "vault:secret/missing#key"} {
+		if _, err := New(WithSecrets(Defaults(map[string]any{"key": ref}), secrets)); err == nil {
+			t.Errorf("expect an error for %s, but received nil", ref)
+		}
+	}
+}
+
+func TestSecretsTTL(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	secrets := NewSecrets(50*time.Millisecond, map[string]SecretResolver{
+		"test": SecretResolverFunc(func(_ context.Context, ref string) (string, error) {
+			calls++
+			return fmt.Sprintf("%s-%d", ref, calls), nil
+		}),
+	})
+	c, err := New(WithSecrets(Defaults(map[string]any{"token": "test:token"}), secrets))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	var rotated []any
+	c.OnChange("token", func(_, cur any) { rotated = append(rotated, cur) })
+
+	// Cached secrets are not fetched again.
+	if err := c.Reload(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if c.changed() {
+		t.Error("expect no change before TTL, but changed")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if !c.changed() {
+		t.Error("expect change after TTL, but not changed")
+	}
+	if err := c.Reload(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff("token-2", c.String("token")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]any{Secret("token-2")}, rotated); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+// mustMarshal encodes given value as JSON.
+func mustMarshal(t *testing.T, v any) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	return string(b)
+}
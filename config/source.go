@@ -0,0 +1,205 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Source is a layer of configuration, such as defaults, a file, environment variables, or flags.
+type Source interface {
+	// Name returns a name of the source used in errors and annotations, such as "file:app.yaml".
+	Name() string
+
+	// Load returns values of the source as a tree of maps keyed by strings.
+	Load() (map[string]any, error)
+}
+
+// Watchable is a Source which can report whether it is changed since the last Load. It is checked by (*Config).Watch.
+// It is implemented by sources of File and OptionalFile.
+type Watchable interface {
+	Source
+
+	// Changed reports whether the source is changed since the last Load.
+	Changed() bool
+}
+
+// mapSource is a Source of fixed values.
+type mapSource struct {
+	values map[string]any
+}
+
+// Defaults returns a source of given default values. Keys may be nested maps, or dotted keys such as "log.level".
+func Defaults(values map[string]any) Source {
+	return &mapSource{values: values}
+}
+
+// Name returns "default".
+func (s *mapSource) Name() string {
+	return "default"
+}
+
+// Load returns the values.
+func (s *mapSource) Load() (map[string]any, error) {
+	tree := make(map[string]any)
+	for key, value := range s.values {
+		part := make(map[string]any)
+		setPath(part, splitKey(key), normalize(value))
+		merge(tree, part, "", s.Name(), make(map[string]string))
+	}
+	return tree, nil
+}
+
+// fileSource is a Source of a YAML, JSON, or TOML file.
+type fileSource struct {
+	path     string
+	optional bool
+
+	// mu guards fields below.
+	mu sync.Mutex
+
+	// modTime and size identify the last loaded content of the file.
+	modTime time.Time
+	size    int64
+}
+
+var _ Watchable = (*fileSource)(nil)
+
+// File returns a source of given file.
+// Files with ".json" extension are decoded as JSON, ".toml" as TOML, and others as YAML.
+func File(path string) Source {
+	return &fileSource{path: path}
+}
+
+// OptionalFile returns a source of given file, as same as File, but a missing file is treated as empty.
+func OptionalFile(path string) Source {
+	return &fileSource{path: path, optional: true}
+}
+
+// Name returns "file:" followed by the path.
+func (s *fileSource) Name() string {
+	return "file:" + s.path
+}
+
+// Changed reports whether the modification time or size of the file is changed since the last Load.
+func (s *fileSource) Changed() bool {
+	modTime, size := statFile(s.path)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !modTime.Equal(s.modTime) || size != s.size
+}
+
+// Load reads and decodes the file.
+func (s *fileSource) Load() (map[string]any, error) {
+	modTime, size := statFile(s.path)
+	s.mu.Lock()
+	s.modTime, s.size = modTime, size
+	s.mu.Unlock()
+
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if s.optional && os.IsNotExist(err) {
+			return map[string]any{}, nil
+		}
+		return nil, fmt.Errorf("config: failed to read %s: %w", s.path, err)
+	}
+
+	var tree map[string]any
+	switch strings.ToLower(filepath.Ext(s.path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		err = dec.Decode(&tree)
+	case ".toml":
+		err = toml.Unmarshal(b, &tree)
+	default:
+		if err = yaml.Unmarshal(b, &tree); err != nil && len(bytes.TrimSpace(b)) == 0 {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: failed to decode %s: %w", s.path, err)
+	}
+	if tree == nil {
+		return map[string]any{}, nil
+	}
+	return normalize(tree).(map[string]any), nil
+}
+
+// envSource is a Source of environment variables.
+type envSource struct {
+	prefix string
+}
+
+// Env returns a source of environment variables starting with given prefix, such as "APP_".
+// The prefix is removed, and the rest is lowercased with "__" separating nested keys,
+// so that APP_LOG__LEVEL is "log.level" and APP_HTTP__READ_TIMEOUT is "http.read_timeout".
+// Empty variables are ignored.
+func Env(prefix string) Source {
+	return &envSource{prefix: prefix}
+}
+
+// Name returns "env".
+func (s *envSource) Name() string {
+	return "env"
+}
+
+// Load returns environment variables with the prefix.
+func (s *envSource) Load() (map[string]any, error) {
+	tree := make(map[string]any)
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, s.prefix) || value == "" {
+			continue
+		}
+		key := strings.ToLower(strings.TrimPrefix(name, s.prefix))
+		if key == "" {
+			continue
+		}
+		setPath(tree, strings.Split(key, "__"), value)
+	}
+	return tree, nil
+}
+
+// flagSource is a Source of command-line flags.
+type flagSource struct {
+	fs *flag.FlagSet
+}
+
+// Flags returns a source of flags set explicitly in given parsed flag set.
+// Flag names are lowercased with "-" separating nested keys, so that -log-level is "log.level".
+func Flags(fs *flag.FlagSet) Source {
+	return &flagSource{fs: fs}
+}
+
+// Name returns "flag".
+func (s *flagSource) Name() string {
+	return "flag"
+}
+
+// Load returns values of flags set explicitly.
+func (s *flagSource) Load() (map[string]any, error) {
+	tree := make(map[string]any)
+	s.fs.Visit(func(f *flag.Flag) {
+		setPath(tree, strings.Split(strings.ToLower(f.Name), "-"), f.Value.String())
+	})
+	return tree, nil
+}
+
+// statFile returns the modification time and size of given file. If failed, it returns zero values.
+func statFile(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
@@ -0,0 +1,314 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ValidatorFunc validates a value of a field with the parameter of its rule, such as "1" of "min=1".
+// It returns an error describing the violation, such as "must be at least 1".
+type ValidatorFunc func(value any, param string) error
+
+// validators holds validators by rule names.
+var validators = struct {
+	mu    sync.RWMutex
+	funcs map[string]ValidatorFunc
+}{funcs: make(map[string]ValidatorFunc)}
+
+// RegisterValidator registers a validator of given rule name used in `validate` tags, replacing the existing one.
+// Built-in rules are required, omitempty, min, max, oneof, and url.
+func RegisterValidator(name string, fn ValidatorFunc) {
+	validators.mu.Lock()
+	defer validators.mu.Unlock()
+	validators.funcs[name] = fn
+}
+
+// Validatable is implemented by types validating themselves after their fields are validated by Validate.
+type Validatable interface {
+	Validate() error
+}
+
+// FieldError is a violation of a rule by a field.
+type FieldError struct {
+	// Field is a dotted key of the field, such as "http.port" or "servers[0].url".
+	Field string
+
+	// Rule is a name of the violated rule, such as "min". It is empty for errors of Validatable.
+	Rule string
+
+	// Param is a parameter of the rule, such as "1" of "min=1".
+	Param string
+
+	// Err describes the violation.
+	Err error
+}
+
+var _ zapcore.ObjectMarshaler = (*FieldError)(nil)
+
+// Error returns a message of the error.
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Err.Error()
+	}
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error describing the violation.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalLogObject encodes the field, rule, and parameter of the violation, so that loggers render them as fields.
+func (e *FieldError) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("field", e.Field)
+	if e.Rule != "" {
+		enc.AddString("rule", e.Rule)
+	}
+	if e.Param != "" {
+		enc.AddString("param", e.Param)
+	}
+	return nil
+}
+
+// ValidationError is returned by Validate with every violation found.
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+// Error returns messages of every violation, one per line.
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "config: invalid configuration:\n" + strings.Join(messages, "\n")
+}
+
+// Unwrap returns every violation.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Validate validates given struct, or pointer to struct, by `validate` tags of its fields such as
+// `validate:"required,min=1,url"`, recursing into nested structs, slices, and maps.
+// Then Validate methods of values implementing Validatable are called.
+// Every violation is returned at once as *ValidationError.
+//
+//   - required: the value must not be zero. Slices and maps must not be empty.
+//   - omitempty: the rest of the rules are skipped if the value is zero.
+//   - min=n, max=n: bounds of numbers and durations, or lengths of strings, slices, and maps.
+//   - oneof=a b c: the value must be one of the space separated values.
+//   - url: the value must be an absolute URL.
+func Validate(v any) error {
+	var errs []*FieldError
+	validateValue(reflect.ValueOf(v), "", &errs)
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// validateValue validates given value and values nested in it, and appends violations to given errors.
+// Nil values, including typed nil pointers, have nothing to validate.
+func validateValue(v reflect.Value, path string, errs *[]*FieldError) {
+	if !v.IsValid() {
+		return
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			key, ok := fieldKey(field)
+			if !ok {
+				continue
+			}
+			fieldPath := joinPath(path, key)
+			if tag := field.Tag.Get("validate"); tag != "" {
+				validateRules(v.Field(i), fieldPath, tag, errs)
+			}
+			validateValue(v.Field(i), fieldPath, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			validateValue(iter.Value(), joinPath(path, fmt.Sprint(iter.Key().Interface())), errs)
+		}
+	}
+
+	if v.CanInterface() {
+		validatable, ok := v.Interface().(Validatable)
+		if !ok && v.CanAddr() {
+			validatable, ok = v.Addr().Interface().(Validatable)
+		}
+		if ok {
+			if err := validatable.Validate(); err != nil {
+				*errs = append(*errs, &FieldError{Field: path, Err: err})
+			}
+		}
+	}
+}
+
+// validateRules applies rules of given tag to given value.
+func validateRules(v reflect.Value, path, tag string, errs *[]*FieldError) {
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "":
+			continue
+		case "omitempty":
+			if v.IsZero() {
+				return
+			}
+			continue
+		}
+
+		validators.mu.RLock()
+		fn, ok := validators.funcs[name]
+		validators.mu.RUnlock()
+		if !ok {
+			fn, ok = builtinValidators[name]
+		}
+		if !ok {
+			*errs = append(*errs, &FieldError{Field: path, Rule: name, Param: param, Err: fmt.Errorf("unknown rule %q", name)})
+			continue
+		}
+
+		value := v
+		for value.Kind() == reflect.Pointer && !value.IsNil() {
+			value = value.Elem()
+		}
+		if err := fn(value.Interface(), param); err != nil {
+			*errs = append(*errs, &FieldError{Field: path, Rule: name, Param: param, Err: err})
+		}
+	}
+}
+
+// builtinValidators are validators of built-in rules. They are overridden by RegisterValidator.
+var builtinValidators = map[string]ValidatorFunc{
+	"required": validateRequired,
+	"min": func(value any, param string) error {
+		return validateBound(value, param, func(n, bound float64) bool { return n >= bound }, "at least")
+	},
+	"max": func(value any, param string) error {
+		return validateBound(value, param, func(n, bound float64) bool { return n <= bound }, "at most")
+	},
+	"oneof": validateOneOf,
+	"url":   validateURL,
+}
+
+// validateRequired reports an error if given value is zero, nil, or empty.
+func validateRequired(value any, _ string) error {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || v.IsZero() || ((v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0) {
+		return errors.New("is required")
+	}
+	return nil
+}
+
+// validateBound reports an error if given value, or its length, does not satisfy given comparison with the parameter.
+func validateBound(value any, param string, ok func(n, bound float64) bool, relation string) error {
+	v := reflect.ValueOf(value)
+	if d, isDuration := value.(time.Duration); isDuration {
+		bound, err := time.ParseDuration(param)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", param)
+		}
+		if !ok(float64(d), float64(bound)) {
+			return fmt.Errorf("must be %s %s", relation, bound)
+		}
+		return nil
+	}
+
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return fmt.Errorf("invalid bound %q", param)
+	}
+	var n float64
+	length := false
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	case reflect.String:
+		n, length = float64(utf8.RuneCountInString(v.String())), true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		n, length = float64(v.Len()), true
+	default:
+		return fmt.Errorf("can not be compared with %s", param)
+	}
+	if ok(n, bound) {
+		return nil
+	}
+	if length {
+		return fmt.Errorf("length must be %s %s", relation, param)
+	}
+	return fmt.Errorf("must be %s %s", relation, param)
+}
+
+// validateOneOf reports an error if given value is not one of the space separated parameter.
+func validateOneOf(value any, param string) error {
+	s, ok := scalarString(value)
+	if !ok {
+		s = fmt.Sprint(value)
+	}
+	for _, allowed := range strings.Fields(param) {
+		if s == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(strings.Fields(param), ", "))
+}
+
+// validateURL reports an error if given value is not an absolute URL.
+func validateURL(value any, _ string) error {
+	s, ok := scalarString(value)
+	if !ok {
+		return errors.New("must be a URL")
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+		return errors.New("must be an absolute URL")
+	}
+	return nil
+}
+
+// joinPath joins given dotted path and key.
+func joinPath(path, key string) string {
+	if path == "" || key == "" {
+		return path + key
+	}
+	return path + "." + key
+}
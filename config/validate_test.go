@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+type testServer struct {
+	URL  string `json:"url" validate:"required,url"`
+	Name string `json:"name" validate:"omitempty,min=3"`
+}
+
+type testValidated struct {
+	Port    int           `json:"port" validate:"min=1,max=65535"`
+	Mode    string        `json:"mode" validate:"oneof=develop production"`
+	Timeout time.Duration `json:"timeout" validate:"min=1s"`
+	Tags    []string      `json:"tags" validate:"required"`
+	Servers []testServer  `json:"servers"`
+	Token   *string       `json:"token" validate:"required"`
+	Even    int           `json:"even" validate:"even"`
+}
+
+func (v testValidated) Validate() error {
+	if v.Mode == "production" && v.Port == 80 {
+		return errors.New("production must not use port 80")
+	}
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	// Not parallel, because validators are registered globally.
+	RegisterValidator("even", func(value any, _ string) error {
+		if value.(int)%2 != 0 {
+			return errors.New("must be even")
+		}
+		return nil
+	})
+
+	token := "token"
+	valid := testValidated{
+		Port:    8080,
+		Mode:    "develop",
+		Timeout: time.Second,
+		Tags:    []string{"a"},
+		Servers: []testServer{{URL: "https://example.com"}},
+		Token:   &token,
+	}
+	if err := Validate(&valid); err != nil {
+		t.Errorf("expect no error, but received %v", err)
+	}
+
+	invalid := testValidated{
+		Port:    80,
+		Mode:    "production",
+		Timeout: time.Millisecond,
+		Servers: []testServer{{URL: "https://example.com", Name: "ab"}, {URL: "example"}},
+		Even:    1,
+	}
+	err := Validate(invalid)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expect ValidationError, but received %v", err)
+	}
+	want := []*FieldError{
+		{Field: "timeout", Rule: "min", Param: "1s"},
+		{Field: "tags", Rule: "required"},
+		{Field: "servers[0].name", Rule: "min", Param: "3"},
+		{Field: "servers[1].url", Rule: "url"},
+		{Field: "token", Rule: "required"},
+		{Field: "even", Rule: "even"},
+		{Field: ""},
+	}
+	if diff := cmp.Diff(want, verr.Errors, cmpopts.IgnoreFields(FieldError{}, "Err")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if !strings.Contains(err.Error(), "servers[0].name: length must be at least 3") {
+		t.Errorf("expect a message of the field, but received %v", err)
+	}
+}
+
+func TestValidateNil(t *testing.T) {
+	t.Parallel()
+
+	if err := Validate(nil); err != nil {
+		t.Errorf("expect no error, but received %v", err)
+	}
+	if err := Validate((*testServer)(nil)); err != nil {
+		t.Errorf("expect no error, but received %v", err)
+	}
+}
+
+func TestValidateUnknownRule(t *testing.T) {
+	t.Parallel()
+
+	var v struct {
+		Name string `validate:"unknown"`
+	}
+	if err := Validate(v); err == nil {
+		t.Error("expect an error, but received nil")
+	}
+}
+
+func TestUnmarshalValidates(t *testing.T) {
+	t.Parallel()
+
+	c, err := New(Defaults(map[string]any{"server.url": "invalid"}))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	var server testServer
+	var verr *ValidationError
+	if err := c.Unmarshal("server", &server); !errors.As(err, &verr) {
+		t.Errorf("expect ValidationError, but received %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// subscription is a function subscribing changes of a key.
+type subscription struct {
+	path []string
+	fn   func(old, cur any)
+}
+
+// OnChange subscribes changes of the value of given key, which may be a nested map, and returns a function to unsubscribe.
+// An empty key subscribes the whole configuration. The function is called with the old and current values after Reload,
+// with nil for unset values. It is called from the goroutine calling Reload, one at a time.
+func (c *Config) OnChange(key string, fn func(old, cur any)) (unsubscribe func()) {
+	s := &subscription{path: splitKey(key), fn: fn}
+	c.mu.Lock()
+	c.subscriptions = append(c.subscriptions, s)
+	c.mu.Unlock()
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.subscriptions = slices.DeleteFunc(c.subscriptions, func(other *subscription) bool { return other == s })
+	}
+}
+
+// Reload loads every source again, and notifies subscribers of changed keys.
+// If a source fails, the previous values are kept and the error is returned.
+func (c *Config) Reload() error {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	values, origins, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	old := c.values
+	c.values, c.origins = values, origins
+	subscriptions := slices.Clone(c.subscriptions)
+	c.mu.Unlock()
+
+	for _, s := range subscriptions {
+		before, _ := lookupPath(old, s.path)
+		after, _ := lookupPath(values, s.path)
+		if !reflect.DeepEqual(before, after) {
+			s.fn(before, after)
+		}
+	}
+	return nil
+}
+
+// Watch checks sources implementing Watchable, such as files, at given interval until given context is done,
+// and reloads the configuration when any of them is changed. It returns immediately.
+// Failures of reloading are passed to given function if not nil, and the previous values are kept.
+func (c *Config) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if !c.changed() {
+					continue
+				}
+				if err := c.Reload(); err != nil && onError != nil {
+					onError(err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// changed reports whether any of watchable sources is changed.
+func (c *Config) changed() bool {
+	for _, s := range c.sources {
+		if w, ok := s.(Watchable); ok && w.Changed() {
+			return true
+		}
+	}
+	return false
+}
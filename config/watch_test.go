@@ -0,0 +1,100 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReload(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, "app.yaml", "log:\n  level: info\nhttp:\n  port: 8080\n")
+	c, err := New(File(path))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	type change struct{ Old, New any }
+	var levels, ports, removed []change
+	c.OnChange("log.level", func(old, cur any) { levels = append(levels, change{old, cur}) })
+	unsubscribe := c.OnChange("http", func(old, cur any) { ports = append(ports, change{old, cur}) })
+	c.OnChange("removed", func(old, cur any) { removed = append(removed, change{old, cur}) })
+
+	if err := os.WriteFile(path, []byte("log:\n  level: debug\nhttp:\n  port: 8080\nremoved: true\n"), 0o600); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if err := c.Reload(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	unsubscribe()
+	if err := os.WriteFile(path, []byte("log:\n  level: debug\nhttp:\n  port: 9090\n"), 0o600); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if err := c.Reload(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	if diff := cmp.Diff([]change{{"info", "debug"}}, levels); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if len(ports) != 0 {
+		t.Errorf("expect no change after unsubscribe, but received %v", ports)
+	}
+	if diff := cmp.Diff([]change{{nil, true}, {true, nil}}, removed); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(9090, c.Int("http.port")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestReloadFailure(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, "app.json", `{"level": "info"}`)
+	c, err := New(File(path))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if err := c.Reload(); err == nil {
+		t.Error("expect an error, but received nil")
+	}
+	if diff := cmp.Diff("info", c.String("level")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, "app.yaml", "level: info\n")
+	c, err := New(File(path))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	changed := make(chan any, 1)
+	c.OnChange("level", func(_, cur any) { changed <- cur })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Watch(ctx, 10*time.Millisecond, func(err error) { t.Errorf("expect no error, but received %v", err) })
+
+	if err := os.WriteFile(path, []byte("level: warning\n"), 0o600); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	select {
+	case got := <-changed:
+		if diff := cmp.Diff("warning", got); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expect change to be notified, but timed out")
+	}
+}
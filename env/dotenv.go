@@ -0,0 +1,187 @@
+package env
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// TB is a subset of testing.TB used by SetenvFromDotenv.
+type TB interface {
+	Helper()
+	Setenv(key, value string)
+	Fatalf(format string, args ...any)
+}
+
+// LoadDotenv sets environment variables from given .env files, or ".env" if no file is given.
+// Variables already set in the environment are kept, so that the environment can override the files.
+// If a variable appears in more than one file, the first one wins.
+// Values set by it are seen by Load, accessors of this package, and logging.NewLoggerFromEnv.
+// See ParseDotenv for the syntax.
+func LoadDotenv(paths ...string) error {
+	return loadDotenv(paths, false, os.Setenv)
+}
+
+// OverloadDotenv sets environment variables from given .env files, or ".env" if no file is given,
+// overriding variables already set in the environment. If a variable appears in more than one file, the last one wins.
+func OverloadDotenv(paths ...string) error {
+	return loadDotenv(paths, true, os.Setenv)
+}
+
+// SetenvFromDotenv sets environment variables from given .env files via tb.Setenv, overriding the environment,
+// so that they are restored after the test. It fails the test if a file can not be loaded.
+func SetenvFromDotenv(tb TB, paths ...string) {
+	tb.Helper()
+	if err := loadDotenv(paths, true, func(key, value string) error {
+		tb.Setenv(key, value)
+		return nil
+	}); err != nil {
+		tb.Fatalf("%v", err)
+	}
+}
+
+// loadDotenv reads given files and sets their variables via given function.
+func loadDotenv(paths []string, override bool, setenv func(key, value string) error) error {
+	if len(paths) == 0 {
+		paths = []string{".env"}
+	}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("env: failed to open dotenv file: %w", err)
+		}
+		vars, err := parseDotenv(f, func(key string) (string, bool) {
+			if override {
+				return "", false
+			}
+			return os.LookupEnv(key)
+		})
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("env: failed to parse %s: %w", path, err)
+		}
+
+		for _, v := range vars {
+			if _, ok := os.LookupEnv(v.key); ok && !override {
+				continue
+			}
+			if err := setenv(v.key, v.value); err != nil {
+				return fmt.Errorf("env: failed to set %s: %w", v.key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseDotenv parses a .env file into variables, without modifying the environment.
+//
+// Each line is KEY=value, optionally prefixed by "export". Blank lines and lines starting with # are ignored.
+// Values may be quoted: single quoted values are taken literally, and double quoted values may contain
+// escape sequences such as \n, \" and \$. Unquoted values end at " #", and are trimmed.
+// References such as ${VAR} or $VAR in unquoted and double quoted values are expanded
+// with variables defined earlier in the file, or environment variables.
+func ParseDotenv(r io.Reader) (map[string]string, error) {
+	vars, err := parseDotenv(r, func(string) (string, bool) { return "", false })
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]string, len(vars))
+	for _, v := range vars {
+		m[v.key] = v.value
+	}
+	return m, nil
+}
+
+// dotenvVar is a variable defined in a .env file.
+type dotenvVar struct {
+	key   string
+	value string
+}
+
+// parseDotenv parses a .env file into variables in order of definition.
+// References are expanded with given preferred lookup first, then variables defined earlier, and environment variables.
+func parseDotenv(r io.Reader, preferred func(string) (string, bool)) ([]dotenvVar, error) {
+	var vars []dotenvVar
+	defined := make(map[string]string)
+	expand := func(s string) string {
+		return os.Expand(s, func(key string) string {
+			if v, ok := preferred(key); ok {
+				return v
+			}
+			if v, ok := defined[key]; ok {
+				return v
+			}
+			return os.Getenv(key)
+		})
+	}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, raw, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: expect KEY=value, but received %q", n, line)
+		}
+		value, err := parseDotenvValue(strings.TrimSpace(raw), expand)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		defined[key] = value
+		vars = append(vars, dotenvVar{key: key, value: value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// parseDotenvValue parses a value of a .env file, expanding references via given function.
+func parseDotenvValue(raw string, expand func(string) string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, "'"):
+		end := strings.Index(raw[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated single quoted value %s", raw)
+		}
+		return raw[1 : end+1], nil
+	case strings.HasPrefix(raw, `"`):
+		// References are expanded while scanning, so that characters from escape sequences such as \$ are kept literally.
+		var b strings.Builder
+		start := 1
+		for i := 1; i < len(raw); i++ {
+			switch c := raw[i]; {
+			case c == '"':
+				b.WriteString(expand(raw[start:i]))
+				return b.String(), nil
+			case c == '\\' && i+1 < len(raw):
+				b.WriteString(expand(raw[start:i]))
+				i++
+				switch raw[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				case 'r':
+					b.WriteByte('\r')
+				default:
+					b.WriteByte(raw[i])
+				}
+				start = i + 1
+			}
+		}
+		return "", fmt.Errorf("unterminated double quoted value %s", raw)
+	default:
+		if i := strings.Index(raw, " #"); i >= 0 {
+			raw = raw[:i]
+		}
+		return expand(strings.TrimSpace(raw)), nil
+	}
+}
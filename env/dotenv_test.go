@@ -0,0 +1,131 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aqyuki/util/logging"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+// writeDotenv writes given content to a .env file in a temporary directory, and returns its path.
+func writeDotenv(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	return path
+}
+
+// unsetenv unsets given variables, and restores them after the test.
+func unsetenv(t *testing.T, keys ...string) {
+	t.Helper()
+
+	for _, key := range keys {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+}
+
+func TestParseDotenv(t *testing.T) {
+	t.Setenv("TEST_HOME", "/home/app")
+
+	got, err := ParseDotenv(strings.NewReader(`
+# comment
+PLAIN=value # trailing comment
+export EXPORTED = exported
+SINGLE='literal ${TEST_HOME} # not a comment'
+DOUBLE="line\nbreak \"quoted\""
+EXPANDED=${TEST_HOME}/data
+CHAINED="$PLAIN-${EXPANDED}"
+ESCAPED="\$PLAIN is ${PLAIN}\n"
+EMPTY=
+`))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	want := map[string]string{
+		"PLAIN":    "value",
+		"EXPORTED": "exported",
+		"SINGLE":   "literal ${TEST_HOME} # not a comment",
+		"DOUBLE":   "line\nbreak \"quoted\"",
+		"EXPANDED": "/home/app/data",
+		"CHAINED":  "value-/home/app/data",
+		"ESCAPED":  "$PLAIN is value\n",
+		"EMPTY":    "",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestParseDotenvInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, content := range []string{"NO_EQUAL", "=value", `QUOTE="unterminated`, "QUOTE='unterminated", "BAD KEY=value"} {
+		if _, err := ParseDotenv(strings.NewReader(content)); err == nil {
+			t.Errorf("expect an error for %q, but received nil", content)
+		}
+	}
+}
+
+func TestLoadDotenv(t *testing.T) {
+	t.Setenv("TEST_DOTENV_KEPT", "environment")
+	unsetenv(t, "TEST_DOTENV_NEW", "TEST_DOTENV_REF")
+	first := writeDotenv(t, "TEST_DOTENV_KEPT=file\nTEST_DOTENV_NEW=first\nTEST_DOTENV_REF=${TEST_DOTENV_KEPT}")
+	second := writeDotenv(t, "TEST_DOTENV_NEW=second")
+
+	if err := LoadDotenv(first, second); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	want := map[string]string{"TEST_DOTENV_KEPT": "environment", "TEST_DOTENV_NEW": "first", "TEST_DOTENV_REF": "environment"}
+	for key, value := range want {
+		if diff := cmp.Diff(value, os.Getenv(key)); diff != "" {
+			t.Errorf("%s: (-want, +got)\n%s", key, diff)
+		}
+	}
+}
+
+func TestOverloadDotenv(t *testing.T) {
+	t.Setenv("TEST_DOTENV_KEY", "environment")
+	first := writeDotenv(t, "TEST_DOTENV_KEY=first")
+	second := writeDotenv(t, "TEST_DOTENV_KEY=second")
+
+	if err := OverloadDotenv(first, second); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff("second", os.Getenv("TEST_DOTENV_KEY")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestLoadDotenvMissingFile(t *testing.T) {
+	t.Parallel()
+
+	if err := LoadDotenv(filepath.Join(t.TempDir(), ".env")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expect %v, but received %v", os.ErrNotExist, err)
+	}
+}
+
+func TestSetenvFromDotenv(t *testing.T) {
+	SetenvFromDotenv(t, writeDotenv(t, "LOG_LEVEL=warn\nTEST_DOTENV_PORT=8080"))
+
+	var cfg struct {
+		Port int `env:"TEST_DOTENV_PORT"`
+	}
+	if err := Load(&cfg); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff(8080, cfg.Port); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if logging.NewStructuredLoggerFromEnv().Core().Enabled(zap.InfoLevel) {
+		t.Error("expect info level disabled by LOG_LEVEL from dotenv, but enabled")
+	}
+}
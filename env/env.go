@@ -0,0 +1,193 @@
+// Package env provides typed accessors of environment variables.
+//
+// Each type has three forms: a form returning a default value if the variable is unset or invalid, such as Int,
+// a Must form panicking instead, such as MustInt, and a Lookup form returning an error, such as LookupInt.
+// Values are trimmed, and an empty variable is treated as unset.
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotSet is returned by Lookup functions when the variable is unset or empty.
+var ErrNotSet = errors.New("env: not set")
+
+// ParseError is returned by Lookup functions when the value of the variable is invalid.
+type ParseError struct {
+	// Key is a name of the variable.
+	Key string
+
+	// Value is the invalid value.
+	Value string
+
+	// Err is an error of parsing the value.
+	Err error
+}
+
+// Error returns a message of the error.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("env: invalid value %q of %s: %v", e.Value, e.Key, e.Err)
+}
+
+// Unwrap returns the error of parsing the value.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// String returns the value of given variable, or given default value if it is unset.
+func String(key, def string) string {
+	if v, err := LookupString(key); err == nil {
+		return v
+	}
+	return def
+}
+
+// MustString returns the value of given variable, or panics if it is unset.
+func MustString(key string) string {
+	return must(LookupString(key))
+}
+
+// LookupString returns the value of given variable, or ErrNotSet if it is unset.
+func LookupString(key string) (string, error) {
+	return lookup(key, func(value string) (string, error) { return value, nil })
+}
+
+// Int returns the value of given variable as int, or given default value if it is unset or invalid.
+func Int(key string, def int) int {
+	if v, err := LookupInt(key); err == nil {
+		return v
+	}
+	return def
+}
+
+// MustInt returns the value of given variable as int, or panics if it is unset or invalid.
+func MustInt(key string) int {
+	return must(LookupInt(key))
+}
+
+// LookupInt returns the value of given variable as int.
+// It returns ErrNotSet if the variable is unset, or *ParseError if it is invalid.
+func LookupInt(key string) (int, error) {
+	return lookup(key, strconv.Atoi)
+}
+
+// Bool returns the value of given variable as bool, or given default value if it is unset or invalid.
+// Values accepted by strconv.ParseBool, such as "1", "true", or "false", are valid.
+func Bool(key string, def bool) bool {
+	if v, err := LookupBool(key); err == nil {
+		return v
+	}
+	return def
+}
+
+// MustBool returns the value of given variable as bool, or panics if it is unset or invalid.
+func MustBool(key string) bool {
+	return must(LookupBool(key))
+}
+
+// LookupBool returns the value of given variable as bool.
+// It returns ErrNotSet if the variable is unset, or *ParseError if it is invalid.
+func LookupBool(key string) (bool, error) {
+	return lookup(key, strconv.ParseBool)
+}
+
+// Duration returns the value of given variable as time.Duration such as "1m30s",
+// or given default value if it is unset or invalid.
+func Duration(key string, def time.Duration) time.Duration {
+	if v, err := LookupDuration(key); err == nil {
+		return v
+	}
+	return def
+}
+
+// MustDuration returns the value of given variable as time.Duration, or panics if it is unset or invalid.
+func MustDuration(key string) time.Duration {
+	return must(LookupDuration(key))
+}
+
+// LookupDuration returns the value of given variable as time.Duration.
+// It returns ErrNotSet if the variable is unset, or *ParseError if it is invalid.
+func LookupDuration(key string) (time.Duration, error) {
+	return lookup(key, time.ParseDuration)
+}
+
+// Float returns the value of given variable as float64, or given default value if it is unset or invalid.
+func Float(key string, def float64) float64 {
+	if v, err := LookupFloat(key); err == nil {
+		return v
+	}
+	return def
+}
+
+// MustFloat returns the value of given variable as float64, or panics if it is unset or invalid.
+func MustFloat(key string) float64 {
+	return must(LookupFloat(key))
+}
+
+// LookupFloat returns the value of given variable as float64.
+// It returns ErrNotSet if the variable is unset, or *ParseError if it is invalid.
+func LookupFloat(key string) (float64, error) {
+	return lookup(key, func(value string) (float64, error) { return strconv.ParseFloat(value, 64) })
+}
+
+// StringSlice returns the value of given variable as a comma separated list, or given default value if it is unset.
+// Items are trimmed, and empty items are dropped.
+func StringSlice(key string, def []string) []string {
+	if v, err := LookupStringSlice(key); err == nil {
+		return v
+	}
+	return def
+}
+
+// MustStringSlice returns the value of given variable as a comma separated list, or panics if it is unset.
+func MustStringSlice(key string) []string {
+	return must(LookupStringSlice(key))
+}
+
+// LookupStringSlice returns the value of given variable as a comma separated list, or ErrNotSet if it is unset.
+// Items are trimmed, and empty items are dropped. A list without items is treated as unset.
+func LookupStringSlice(key string) ([]string, error) {
+	items, err := lookup(key, func(value string) ([]string, error) { return splitList(value), nil })
+	if err == nil && len(items) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotSet, key)
+	}
+	return items, err
+}
+
+// lookup returns the value of given variable parsed by given function.
+func lookup[T any](key string, parse func(string) (T, error)) (T, error) {
+	var zero T
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return zero, fmt.Errorf("%w: %s", ErrNotSet, key)
+	}
+	v, err := parse(value)
+	if err != nil {
+		return zero, &ParseError{Key: key, Value: value, Err: err}
+	}
+	return v, nil
+}
+
+// must returns given value, or panics if given error is not nil.
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// splitList splits given comma separated list, trimming items and dropping empty ones.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,95 @@
+package env
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestString(t *testing.T) {
+	t.Setenv("TEST_STRING", " value ")
+	t.Setenv("TEST_EMPTY", "")
+
+	if diff := cmp.Diff("value", String("TEST_STRING", "default")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("default", String("TEST_EMPTY", "default")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("default", String("TEST_UNSET", "default")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestTypedAccessors(t *testing.T) {
+	t.Setenv("TEST_INT", "42")
+	t.Setenv("TEST_BOOL", "true")
+	t.Setenv("TEST_DURATION", "1m30s")
+	t.Setenv("TEST_FLOAT", "0.5")
+	t.Setenv("TEST_SLICE", "a, b,,c ")
+	t.Setenv("TEST_INVALID", "invalid")
+
+	if diff := cmp.Diff(42, Int("TEST_INT", 1)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(1, Int("TEST_INVALID", 1)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(true, Bool("TEST_BOOL", false)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(90*time.Second, Duration("TEST_DURATION", time.Second)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(time.Second, Duration("TEST_INVALID", time.Second)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(0.5, Float("TEST_FLOAT", 1)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"a", "b", "c"}, StringSlice("TEST_SLICE", nil)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"d"}, StringSlice("TEST_UNSET", []string{"d"})); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	t.Setenv("TEST_INVALID", "invalid")
+
+	if _, err := LookupInt("TEST_UNSET"); !errors.Is(err, ErrNotSet) {
+		t.Errorf("expect %v, but received %v", ErrNotSet, err)
+	}
+
+	_, err := LookupBool("TEST_INVALID")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expect ParseError, but received %v", err)
+	}
+	if diff := cmp.Diff(ParseError{Key: "TEST_INVALID", Value: "invalid"}, *parseErr, cmpIgnoreErr); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestMust(t *testing.T) {
+	t.Setenv("TEST_INT", "7")
+
+	if diff := cmp.Diff(7, MustInt("TEST_INT")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expect panic, but not panicked")
+		}
+	}()
+	MustString("TEST_UNSET")
+}
+
+// cmpIgnoreErr ignores Err of ParseError.
+var cmpIgnoreErr = cmp.FilterPath(func(p cmp.Path) bool {
+	return p.Last().String() == ".Err"
+}, cmp.Ignore())
@@ -0,0 +1,244 @@
+package env
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// LoadOption is an option of Load.
+type LoadOption func(*loadOptions)
+
+// loadOptions is a set of options of Load.
+type loadOptions struct {
+	// prefix is prepended to every name of variables.
+	prefix string
+}
+
+// WithPrefix prepends given prefix, such as "APP_", to every name of variables.
+func WithPrefix(prefix string) LoadOption {
+	return func(o *loadOptions) {
+		o.prefix = prefix
+	}
+}
+
+// Load populates given pointer to a struct from environment variables, according to `env` tags of its fields.
+//
+// A tag consists of the name of the variable and options separated by commas:
+//
+//   - required: the variable must be set, unless a default value is given.
+//   - default=value: a value used if the variable is unset. It must be the last option, and may contain commas.
+//   - prefix=PREFIX_: on a field of struct type, prepended to names of variables of the struct's fields.
+//
+// Fields of struct type without a name are loaded recursively, and other fields without a name are ignored.
+//
+//	type Config struct {
+//		Addr    string        `env:"ADDR,default=:8080"`
+//		Timeout time.Duration `env:"TIMEOUT,required"`
+//		DB      DBConfig      `env:",prefix=DB_"`
+//	}
+//
+// Supported types are strings, booleans, integers, floats, time.Duration, types implementing encoding.TextUnmarshaler,
+// and pointers to them. Slices are comma separated lists such as "a,b", and maps are comma separated pairs such as "a=1,b=2".
+// Every missing or invalid variable is reported in the returned error, joined by errors.Join.
+// Missing variables are reported as ErrNotSet, and invalid ones as *ParseError.
+func Load(v any, opts ...LoadOption) error {
+	o := &loadOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("env: Load requires a non-nil pointer to a struct, but received %T", v)
+	}
+	return errors.Join(loadStruct(rv.Elem(), o.prefix)...)
+}
+
+// fieldTag is a parsed `env` tag.
+type fieldTag struct {
+	name       string
+	prefix     string
+	required   bool
+	def        string
+	hasDefault bool
+}
+
+// parseFieldTag parses given `env` tag.
+func parseFieldTag(tag string) (fieldTag, error) {
+	name, rest, _ := strings.Cut(tag, ",")
+	t := fieldTag{name: strings.TrimSpace(name)}
+	for rest != "" {
+		var opt string
+		if strings.HasPrefix(rest, "default=") {
+			opt, rest = rest, ""
+		} else {
+			opt, rest, _ = strings.Cut(rest, ",")
+		}
+		switch key, value, _ := strings.Cut(opt, "="); key {
+		case "required":
+			t.required = true
+		case "default":
+			t.def, t.hasDefault = value, true
+		case "prefix":
+			t.prefix = value
+		default:
+			return fieldTag{}, fmt.Errorf("env: unknown tag option %q", opt)
+		}
+	}
+	return t, nil
+}
+
+// loadStruct populates fields of given struct, and returns errors of every field.
+func loadStruct(v reflect.Value, prefix string) []error {
+	var errs []error
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		t, err := parseFieldTag(field.Tag.Get("env"))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w on %s", err, field.Name))
+			continue
+		}
+
+		fv := v.Field(i)
+		if t.name == "" {
+			if isNestedStruct(field.Type) {
+				errs = append(errs, loadNested(fv, prefix+t.prefix)...)
+			}
+			continue
+		}
+
+		key := prefix + t.name
+		value := strings.TrimSpace(os.Getenv(key))
+		if value == "" {
+			switch {
+			case t.hasDefault:
+				value = t.def
+			case t.required:
+				errs = append(errs, fmt.Errorf("%w: %s", ErrNotSet, key))
+				continue
+			default:
+				continue
+			}
+		}
+		if err := setValue(fv, value); err != nil {
+			errs = append(errs, &ParseError{Key: key, Value: value, Err: err})
+		}
+	}
+	return errs
+}
+
+// loadNested populates given field of struct type or pointer to struct type.
+// A nil pointer is allocated.
+func loadNested(v reflect.Value, prefix string) []error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return loadStruct(v, prefix)
+}
+
+// isNestedStruct reports whether given type is a struct or pointer to struct loaded recursively.
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// setValue parses given value into given settable value.
+func setValue(v reflect.Value, value string) error {
+	if v.Kind() == reflect.Pointer {
+		ptr := reflect.New(v.Type().Elem())
+		if err := setValue(ptr.Elem(), value); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		items := splitList(value)
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(s.Index(i), item); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for _, pair := range splitList(value) {
+			k, val, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("%q is not a key=value pair", pair)
+			}
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := setValue(key, strings.TrimSpace(k)); err != nil {
+				return err
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setValue(elem, strings.TrimSpace(val)); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, elem)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
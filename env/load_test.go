@@ -0,0 +1,117 @@
+package env
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testDBConfig struct {
+	Host string `env:"HOST,default=localhost"`
+	Port int    `env:"PORT,required"`
+}
+
+type testConfig struct {
+	Name     string         `env:"NAME"`
+	Debug    bool           `env:"DEBUG"`
+	Timeout  time.Duration  `env:"TIMEOUT,default=5s"`
+	Ratio    float32        `env:"RATIO"`
+	Workers  *uint          `env:"WORKERS"`
+	Tags     []string       `env:"TAGS,default=a,b"`
+	Ports    []int          `env:"PORTS"`
+	Limits   map[string]int `env:"LIMITS"`
+	Addr     netip.Addr     `env:"ADDR"`
+	DB       testDBConfig   `env:",prefix=DB_"`
+	Replica  *testDBConfig  `env:",prefix=REPLICA_"`
+	Ignored  string
+	internal string `env:"INTERNAL"`
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("APP_NAME", "api")
+	t.Setenv("APP_DEBUG", "true")
+	t.Setenv("APP_RATIO", "0.25")
+	t.Setenv("APP_WORKERS", "4")
+	t.Setenv("APP_PORTS", "80, 443")
+	t.Setenv("APP_LIMITS", "a=1,b=2")
+	t.Setenv("APP_ADDR", "127.0.0.1")
+	t.Setenv("APP_DB_PORT", "5432")
+	t.Setenv("APP_REPLICA_HOST", "replica")
+	t.Setenv("APP_REPLICA_PORT", "5433")
+	t.Setenv("APP_INTERNAL", "ignored")
+
+	var got testConfig
+	if err := Load(&got, WithPrefix("APP_")); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	workers := uint(4)
+	want := testConfig{
+		Name:    "api",
+		Debug:   true,
+		Timeout: 5 * time.Second,
+		Ratio:   0.25,
+		Workers: &workers,
+		Tags:    []string{"a", "b"},
+		Ports:   []int{80, 443},
+		Limits:  map[string]int{"a": 1, "b": 2},
+		Addr:    netip.MustParseAddr("127.0.0.1"),
+		DB:      testDBConfig{Host: "localhost", Port: 5432},
+		Replica: &testDBConfig{Host: "replica", Port: 5433},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(testConfig{}), cmp.Comparer(func(a, b netip.Addr) bool { return a == b })); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Setenv("DEBUG", "maybe")
+	t.Setenv("LIMITS", "a")
+	t.Setenv("REPLICA_PORT", "5433")
+
+	var cfg testConfig
+	err := Load(&cfg)
+	if !errors.Is(err, ErrNotSet) {
+		t.Errorf("expect %v, but received %v", ErrNotSet, err)
+	}
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("expect ParseError, but received %v", err)
+	}
+
+	// Every error is reported at once.
+	for _, key := range []string{"DEBUG", "LIMITS", "DB_PORT"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("expect error of %s, but received %v", key, err)
+		}
+	}
+	if strings.Contains(err.Error(), "REPLICA_PORT") {
+		t.Errorf("expect no error of REPLICA_PORT, but received %v", err)
+	}
+}
+
+func TestLoadInvalidArgument(t *testing.T) {
+	t.Parallel()
+
+	var cfg testConfig
+	for _, v := range []any{cfg, (*testConfig)(nil), new(int)} {
+		if err := Load(v); err == nil {
+			t.Errorf("expect an error for %T, but received nil", v)
+		}
+	}
+}
+
+func TestLoadUnknownOption(t *testing.T) {
+	t.Parallel()
+
+	var cfg struct {
+		Name string `env:"NAME,optional"`
+	}
+	if err := Load(&cfg); err == nil {
+		t.Error("expect an error, but received nil")
+	}
+}
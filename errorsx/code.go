@@ -0,0 +1,194 @@
+package errorsx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorCode is a category of errors, which decides responses of HTTP and gRPC servers.
+type ErrorCode int
+
+const (
+	// Unknown is a code of errors without a code. It is the zero value.
+	Unknown ErrorCode = iota
+
+	// OK is a code of nil errors.
+	OK
+
+	// Invalid is a code of errors caused by invalid input from clients.
+	Invalid
+
+	// NotFound is a code of errors caused by missing resources.
+	NotFound
+
+	// AlreadyExists is a code of errors caused by resources which already exist.
+	AlreadyExists
+
+	// FailedPrecondition is a code of errors caused by states which do not allow the operation.
+	FailedPrecondition
+
+	// Unauthenticated is a code of errors caused by missing or invalid credentials.
+	Unauthenticated
+
+	// PermissionDenied is a code of errors caused by callers without permission.
+	PermissionDenied
+
+	// ResourceExhausted is a code of errors caused by exhausted quotas or rate limits.
+	ResourceExhausted
+
+	// Canceled is a code of errors caused by callers canceling the operation.
+	Canceled
+
+	// DeadlineExceeded is a code of errors caused by expired deadlines.
+	DeadlineExceeded
+
+	// Unimplemented is a code of errors caused by unsupported operations.
+	Unimplemented
+
+	// Unavailable is a code of errors caused by temporarily unavailable dependencies. Clients may retry them.
+	Unavailable
+
+	// Internal is a code of errors caused by bugs or broken invariants of servers.
+	Internal
+)
+
+// statusClientClosedRequest is a status code used for canceled requests by convention, since net/http has none.
+const statusClientClosedRequest = 499
+
+// codeInfo is a name and mappings of a code.
+type codeInfo struct {
+	name       string
+	httpStatus int
+	grpcCode   codes.Code
+}
+
+// codeInfos holds information of each code.
+var codeInfos = map[ErrorCode]codeInfo{
+	Unknown:            {"unknown", http.StatusInternalServerError, codes.Unknown},
+	OK:                 {"ok", http.StatusOK, codes.OK},
+	Invalid:            {"invalid", http.StatusBadRequest, codes.InvalidArgument},
+	NotFound:           {"not_found", http.StatusNotFound, codes.NotFound},
+	AlreadyExists:      {"already_exists", http.StatusConflict, codes.AlreadyExists},
+	FailedPrecondition: {"failed_precondition", http.StatusBadRequest, codes.FailedPrecondition},
+	Unauthenticated:    {"unauthenticated", http.StatusUnauthorized, codes.Unauthenticated},
+	PermissionDenied:   {"permission_denied", http.StatusForbidden, codes.PermissionDenied},
+	ResourceExhausted:  {"resource_exhausted", http.StatusTooManyRequests, codes.ResourceExhausted},
+	Canceled:           {"canceled", statusClientClosedRequest, codes.Canceled},
+	DeadlineExceeded:   {"deadline_exceeded", http.StatusGatewayTimeout, codes.DeadlineExceeded},
+	Unimplemented:      {"unimplemented", http.StatusNotImplemented, codes.Unimplemented},
+	Unavailable:        {"unavailable", http.StatusServiceUnavailable, codes.Unavailable},
+	Internal:           {"internal", http.StatusInternalServerError, codes.Internal},
+}
+
+// info returns information of the code, or of Unknown for undefined codes.
+func (c ErrorCode) info() codeInfo {
+	if info, ok := codeInfos[c]; ok {
+		return info
+	}
+	return codeInfos[Unknown]
+}
+
+// String returns the name of the code in snake case, such as "not_found".
+func (c ErrorCode) String() string {
+	return c.info().name
+}
+
+// HTTPStatus returns the HTTP status code of the code, such as 404 for NotFound.
+// Canceled is mapped to 499 by the convention of nginx and grpc-gateway.
+func (c ErrorCode) HTTPStatus() int {
+	return c.info().httpStatus
+}
+
+// GRPCCode returns the gRPC code of the code.
+func (c ErrorCode) GRPCCode() codes.Code {
+	return c.info().grpcCode
+}
+
+// WithCode returns an error which attaches given code to given error. The message is not changed.
+// If err is nil, WithCode returns nil.
+func WithCode(err error, code ErrorCode) error {
+	if err == nil {
+		return nil
+	}
+	return annotate(err, func(e *Error) {
+		e.code = code
+	})
+}
+
+// Code returns the code of given error. It is OK for nil, and the outermost code attached by WithCode in the tree
+// of the error, including errors wrapped by errors.Join and Join. Otherwise, context errors are Canceled or
+// DeadlineExceeded, gRPC status errors are mapped from their codes, and other errors are Unknown.
+func Code(err error) ErrorCode {
+	if err == nil {
+		return OK
+	}
+	code := Unknown
+	walk(err, func(e error) bool {
+		if x, ok := e.(*Error); ok && x.code != Unknown {
+			code = x.code
+			return true
+		}
+		return false
+	})
+	if code != Unknown {
+		return code
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded
+	}
+	walk(err, func(e error) bool {
+		if _, ok := e.(*Error); ok {
+			// Skip GRPCStatus of this package, which is derived from Code.
+			return false
+		}
+		if s, ok := e.(interface{ GRPCStatus() *status.Status }); ok {
+			code = codeOfGRPC(s.GRPCStatus().Code())
+			return true
+		}
+		return false
+	})
+	return code
+}
+
+// HTTPStatus returns the HTTP status code of the code of given error, as same as Code(err).HTTPStatus().
+func HTTPStatus(err error) int {
+	return Code(err).HTTPStatus()
+}
+
+// GRPCStatus returns the gRPC status of the error, with the gRPC code of Code,
+// and the public message attached by WithPublicMessage, or the message if none is attached.
+// It makes status.Code and status.FromError of gRPC, and so gRPC servers, respect the code.
+// Note that status.FromError replaces the message with the whole message for errors wrapping it,
+// so use grpclog.UnaryServerInterceptor to respond with the public message.
+func (e *Error) GRPCStatus() *status.Status {
+	if msg, ok := PublicMessage(e); ok {
+		return status.New(Code(e).GRPCCode(), msg)
+	}
+	return status.New(Code(e).GRPCCode(), e.msg)
+}
+
+// codeOfGRPC returns the code mapped to given gRPC code. gRPC codes without a counterpart are mapped to the closest one.
+func codeOfGRPC(code codes.Code) ErrorCode {
+	switch code {
+	case codes.OutOfRange:
+		return Invalid
+	case codes.Aborted:
+		return FailedPrecondition
+	case codes.DataLoss:
+		return Internal
+	}
+	for c, info := range codeInfos {
+		if info.grpcCode == code {
+			return c
+		}
+	}
+	return Unknown
+}
@@ -0,0 +1,104 @@
+package errorsx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCode(t *testing.T) {
+	t.Parallel()
+
+	notFound := WithCode(New("user not found"), NotFound)
+	cases := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{name: "nil", err: nil, want: OK},
+		{name: "plain", err: errors.New("boom"), want: Unknown},
+		{name: "attached", err: notFound, want: NotFound},
+		{name: "wrapped", err: fmt.Errorf("lookup: %w", notFound), want: NotFound},
+		{name: "outermost wins", err: WithCode(Wrap(notFound, "lookup"), Internal), want: Internal},
+		{name: "canceled", err: Wrap(context.Canceled, "query"), want: Canceled},
+		{name: "deadline", err: fmt.Errorf("query: %w", context.DeadlineExceeded), want: DeadlineExceeded},
+		{name: "grpc status", err: Wrap(status.Error(codes.Unavailable, "connection refused"), "call"), want: Unavailable},
+		{name: "joined", err: Join(errors.New("boom"), notFound), want: NotFound},
+		{name: "multiple verbs", err: fmt.Errorf("%w and %w", errors.New("boom"), notFound), want: NotFound},
+		{name: "grpc aborted", err: status.Error(codes.Aborted, "aborted"), want: FailedPrecondition},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(cs.want, Code(cs.err)); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithCode(t *testing.T) {
+	t.Parallel()
+
+	if err := WithCode(nil, Internal); err != nil {
+		t.Errorf("expect nil, but received %v", err)
+	}
+
+	base := WithFields(New("boom"), "key", "value")
+	err := WithCode(base, Unavailable)
+	if diff := cmp.Diff("boom", err.Error()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"key": "value"}, Fields(err)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(Unknown, Code(base)); diff != "" {
+		t.Errorf("expect the original error not to change (-want, +got)\n%s", diff)
+	}
+}
+
+func TestWithCodeSentinel(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("not found")
+	err := fmt.Errorf("lookup: %w", WithCode(sentinel, NotFound))
+	if !errors.Is(err, sentinel) {
+		t.Errorf("expect %v, but received %v", sentinel, err)
+	}
+	if diff := cmp.Diff(NotFound, Code(err)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestErrorCodeMappings(t *testing.T) {
+	t.Parallel()
+
+	err := fmt.Errorf("lookup: %w", WithCode(New("user not found"), NotFound))
+	if diff := cmp.Diff(http.StatusNotFound, HTTPStatus(err)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(codes.NotFound, status.Code(err)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(http.StatusInternalServerError, HTTPStatus(errors.New("boom"))); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(499, Canceled.HTTPStatus()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("resource_exhausted", ResourceExhausted.String()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("unknown", ErrorCode(-1).String()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
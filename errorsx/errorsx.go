@@ -0,0 +1,164 @@
+// Package errorsx provides errors which carry stack traces and structured fields.
+//
+//	if err := db.QueryRow(ctx, q, id).Scan(&u); err != nil {
+//		return errorsx.WithFields(errorsx.Wrap(err, "failed to load user"), "user_id", id)
+//	}
+//
+// The errors work with errors.Is and errors.As of the standard library, and logging.Err renders their stack traces
+// and fields. Formatting with %+v prints the message followed by fields and the stack trace.
+//
+// WithCode categorizes errors, such as NotFound, which HTTP and gRPC servers map to their status codes,
+// and WithPublicMessage attaches a message which is safe to show users, while the error itself goes to logs.
+package errorsx
+
+import (
+	"fmt"
+	"io"
+)
+
+// Error is an error which carries a stack trace and fields. It is created by New, Errorf, Wrap, and WithFields.
+type Error struct {
+	// msg is the whole message, including the message of the cause.
+	msg   string
+	cause error
+
+	// stack is nil if an error in the chain already carries a stack trace.
+	stack  *stack
+	fields map[string]any
+
+	// code is Unknown unless a code is attached by WithCode.
+	code ErrorCode
+
+	// public is a message for users attached by WithPublicMessage, or empty.
+	public string
+}
+
+// New returns an error with given message and the stack trace of the caller.
+func New(msg string) error {
+	return &Error{msg: msg, stack: callers(1)}
+}
+
+// Errorf formats an error as same as fmt.Errorf, including %w verbs, with the stack trace of the caller.
+// The stack trace is not captured if a wrapped error already carries one.
+func Errorf(format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	e := &Error{msg: err.Error()}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		e.cause = u.Unwrap()
+	case interface{ Unwrap() []error }:
+		// Errors wrapping multiple errors are kept, so that they are still unwrapped as a tree.
+		e.cause = err
+	}
+	if !hasStack(e.cause) {
+		e.stack = callers(1)
+	}
+	return e
+}
+
+// Wrap returns an error which annotates given error with given message, as "msg: err".
+// The stack trace of the caller is captured unless the error already carries one. If err is nil, Wrap returns nil.
+func Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	e := &Error{msg: msg + ": " + err.Error(), cause: err}
+	if !hasStack(err) {
+		e.stack = callers(1)
+	}
+	return e
+}
+
+// Error returns the message of the error.
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the wrapped error, or nil if the error does not wrap any error.
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Stack returns the stack trace captured by the error, or an empty string if it is carried by a wrapped error.
+// It is used by logging.Err to render "stack_trace".
+func (e *Error) Stack() string {
+	if e.stack == nil {
+		return ""
+	}
+	return e.stack.String()
+}
+
+// Format formats the error. %+v prints the message followed by fields and the stack trace of the chain,
+// and other verbs print the message.
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		_, _ = io.WriteString(s, e.msg)
+		for _, field := range sortedFields(Fields(e)) {
+			_, _ = fmt.Fprintf(s, "\n%s=%v", field.key, field.value)
+		}
+		if stack := chainStack(e); stack != nil {
+			_, _ = io.WriteString(s, "\n"+stack.String())
+		}
+	case verb == 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.msg)
+	default:
+		_, _ = io.WriteString(s, e.msg)
+	}
+}
+
+// annotate returns an error which wraps given error without changing the message, changed by given function,
+// so that errors.Is still matches the original error, such as a sentinel error created by New.
+// It is called by functions annotating errors, and captures the stack trace of their callers
+// unless the error already carries one.
+func annotate(err error, change func(e *Error)) error {
+	e := &Error{msg: err.Error(), cause: err}
+	if !hasStack(err) {
+		e.stack = callers(2)
+	}
+	change(e)
+	return e
+}
+
+// walk calls given function with each error in the tree of given error in depth-first order, following both
+// Unwrap() error and Unwrap() []error, until the function returns true. It reports whether the function returned true.
+func walk(err error, fn func(error) bool) bool {
+	for err != nil {
+		if fn(err) {
+			return true
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, err := range u.Unwrap() {
+				if walk(err, fn) {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// hasStack reports whether an error in the tree of given error carries a stack trace captured by this package.
+func hasStack(err error) bool {
+	return chainStack(err) != nil
+}
+
+// chainStack returns the first stack trace captured by this package in depth-first order of the tree of given error.
+// A chain carries at most one stack trace, because it is captured only if the wrapped error carries none.
+func chainStack(err error) *stack {
+	var found *stack
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok && e.stack != nil {
+			found = e.stack
+			return true
+		}
+		return false
+	})
+	return found
+}
@@ -0,0 +1,108 @@
+package errorsx
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	err := New("boom")
+	if diff := cmp.Diff("boom", err.Error()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("expect *Error, but received %T", err)
+	}
+	if stack := e.Stack(); !strings.HasPrefix(stack, "github.com/aqyuki/util/errorsx.TestNew\n\t") {
+		t.Errorf("expect the stack trace to start with the caller, but received %q", stack)
+	}
+}
+
+func TestErrorf(t *testing.T) {
+	t.Parallel()
+
+	err := Errorf("failed to open %s: %w", "config.toml", fs.ErrNotExist)
+	if diff := cmp.Diff("failed to open config.toml: file does not exist", err.Error()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expect %v, but received %v", fs.ErrNotExist, err)
+	}
+
+	first, second := errors.New("first"), errors.New("second")
+	joined := Errorf("both failed: %w, %w", first, second)
+	if !errors.Is(joined, first) || !errors.Is(joined, second) {
+		t.Errorf("expect both errors, but received %v", joined)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	if err := Wrap(nil, "ignored"); err != nil {
+		t.Errorf("expect nil, but received %v", err)
+	}
+
+	cause := New("connection refused")
+	err := Wrap(Wrap(cause, "query failed"), "failed to load user")
+	if diff := cmp.Diff("failed to load user: query failed: connection refused", err.Error()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("expect %v, but received %v", cause, err)
+	}
+
+	// Errors wrapping an error with a stack trace do not capture another one.
+	if stack := err.(*Error).Stack(); stack != "" {
+		t.Errorf("expect no stack trace, but received %q", stack)
+	}
+	if stack := Wrap(errors.New("plain"), "wrapped").(*Error).Stack(); stack == "" {
+		t.Error("expect a stack trace, but received none")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	err := WithFields(Wrap(New("connection refused"), "query failed"), "user_id", 42, "table", "users")
+
+	if diff := cmp.Diff("query failed: connection refused", fmt.Sprintf("%v", err)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(`"query failed: connection refused"`, fmt.Sprintf("%q", err)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	verbose := fmt.Sprintf("%+v", err)
+	prefix := "query failed: connection refused\ntable=users\nuser_id=42\ngithub.com/aqyuki/util/errorsx.TestFormat\n\t"
+	if !strings.HasPrefix(verbose, prefix) {
+		t.Errorf("expect %q to start with %q", verbose, prefix)
+	}
+}
+
+func TestStackJoined(t *testing.T) {
+	t.Parallel()
+
+	cause := New("connection refused")
+	for _, joined := range []error{Join(errors.New("plain"), cause), errors.Join(errors.New("plain"), cause)} {
+		// Errors wrapping a joined error with a stack trace do not capture another one.
+		err := Wrap(joined, "batch failed")
+		if stack := err.(*Error).Stack(); stack != "" {
+			t.Errorf("expect no stack trace, but received %q", stack)
+		}
+
+		verbose := fmt.Sprintf("%+v", err)
+		if !strings.Contains(verbose, "\ngithub.com/aqyuki/util/errorsx.TestStackJoined\n\t") {
+			t.Errorf("expect the stack trace of the joined error, but received %q", verbose)
+		}
+	}
+}
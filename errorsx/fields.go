@@ -0,0 +1,72 @@
+package errorsx
+
+import (
+	"fmt"
+	"sort"
+)
+
+// WithFields returns an error which attaches given fields to given error, as alternating keys and values
+// such as "user_id", id. Non-string keys are formatted with fmt.Sprint, and a key without a value is ignored.
+// The message is not changed. If err is nil, WithFields returns nil.
+func WithFields(err error, keysAndValues ...any) error {
+	if err == nil {
+		return nil
+	}
+	fields := make(map[string]any, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		fields[key] = keysAndValues[i+1]
+	}
+
+	return annotate(err, func(e *Error) {
+		e.fields = fields
+	})
+}
+
+// Fields returns fields attached to every error in the tree of given error, following errors joined by Join
+// and errors.Join. The first field found in depth-first order wins over others with the same key,
+// so that fields of outer errors win over fields of inner errors. It returns nil if there is no field.
+func Fields(err error) map[string]any {
+	var fields map[string]any
+	walk(err, func(err error) bool {
+		e, ok := err.(*Error)
+		if !ok {
+			return false
+		}
+		for k, v := range e.fields {
+			if fields == nil {
+				fields = make(map[string]any)
+			}
+			if _, ok := fields[k]; !ok {
+				fields[k] = v
+			}
+		}
+		return false
+	})
+	return fields
+}
+
+// Fields returns fields attached to the chain of the error, as same as Fields.
+// It is used by logging.Err to render "fields".
+func (e *Error) Fields() map[string]any {
+	return Fields(e)
+}
+
+// field is a key and value of a field.
+type field struct {
+	key   string
+	value any
+}
+
+// sortedFields returns given fields sorted by key.
+func sortedFields(fields map[string]any) []field {
+	sorted := make([]field, 0, len(fields))
+	for k, v := range fields {
+		sorted = append(sorted, field{key: k, value: v})
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].key < sorted[j].key })
+	return sorted
+}
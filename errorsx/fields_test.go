@@ -0,0 +1,79 @@
+package errorsx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithFields(t *testing.T) {
+	t.Parallel()
+
+	if err := WithFields(nil, "key", "value"); err != nil {
+		t.Errorf("expect nil, but received %v", err)
+	}
+
+	cause := WithFields(errors.New("not found"), "user_id", 1, "table", "users")
+	err := WithFields(fmt.Errorf("lookup: %w", cause), "user_id", 2, 3, "three", "dangling")
+	if diff := cmp.Diff("lookup: not found", err.Error()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	want := map[string]any{"user_id": 2, "table": "users", "3": "three"}
+	if diff := cmp.Diff(want, Fields(err)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"user_id": 1, "table": "users"}, Fields(cause)); diff != "" {
+		t.Errorf("expect fields of the cause not to change (-want, +got)\n%s", diff)
+	}
+	if fields := Fields(errors.New("plain")); fields != nil {
+		t.Errorf("expect no fields, but received %v", fields)
+	}
+}
+
+func TestWithFieldsMerges(t *testing.T) {
+	t.Parallel()
+
+	base := New("boom")
+	err := WithFields(WithFields(base, "a", 1), "b", 2)
+
+	if diff := cmp.Diff(map[string]any{"a": 1, "b": 2}, Fields(err)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if fields := Fields(base); fields != nil {
+		t.Errorf("expect the original error not to change, but received %v", fields)
+	}
+}
+
+func TestWithFieldsSentinel(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("not found")
+	err := WithFields(sentinel, "user_id", 1)
+	if !errors.Is(err, sentinel) {
+		t.Errorf("expect %v, but received %v", sentinel, err)
+	}
+	if diff := cmp.Diff("not found", err.Error()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if stack := err.(*Error).Stack(); stack != "" {
+		t.Errorf("expect the stack trace of the sentinel only, but received %q", stack)
+	}
+}
+
+func TestFieldsJoined(t *testing.T) {
+	t.Parallel()
+
+	first := WithFields(errors.New("first"), "id", 1, "table", "users")
+	second := WithFields(New("second"), "id", 2, "region", "us-east-1")
+	for _, joined := range []error{Join(first, second), errors.Join(first, second)} {
+		err := WithFields(fmt.Errorf("batch failed: %w", joined), "table", "orders")
+
+		want := map[string]any{"id": 1, "table": "orders", "region": "us-east-1"}
+		if diff := cmp.Diff(want, Fields(err)); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package errorsx
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MultiError is an error which collects multiple errors up to a limit, counting errors beyond it,
+// so that errors of large batches do not grow without bound. The collected errors keep their stack traces and fields,
+// and logging.Err renders each of them as an element of "errors" array with "errors_omitted" count.
+// It is compatible with errors.Join: errors.Is and errors.As find each collected error. It is safe for concurrent use.
+//
+//	errs := errorsx.NewMultiError(10)
+//	for _, item := range items {
+//		errs.Append(process(item))
+//	}
+//	return errs.Err()
+type MultiError struct {
+	limit int
+
+	// mu guards fields below.
+	mu      sync.Mutex
+	errs    []error
+	omitted int
+}
+
+// NewMultiError creates an empty MultiError which collects up to given number of errors.
+// Zero or a negative number means no limit.
+func NewMultiError(limit int) *MultiError {
+	return &MultiError{limit: limit}
+}
+
+// Join returns an error which collects given non-nil errors without limit, as same as errors.Join,
+// or nil if every error is nil.
+func Join(errs ...error) error {
+	m := &MultiError{}
+	m.Append(errs...)
+	return m.Err()
+}
+
+// Append collects given errors. Nil errors and the MultiError itself are ignored,
+// and errors of another MultiError are collected one by one.
+// Errors beyond the limit are only counted.
+func (m *MultiError) Append(errs ...error) {
+	// Errors of nested MultiErrors are copied before m.mu is held,
+	// so that appending two MultiErrors to each other concurrently does not deadlock.
+	collected := make([]error, 0, len(errs))
+	var omitted int
+	for _, err := range errs {
+		if err == nil || err == error(m) {
+			continue
+		}
+		if nested, ok := err.(*MultiError); ok {
+			inner, n := nested.snapshot()
+			collected = append(collected, inner...)
+			omitted += n
+			continue
+		}
+		collected = append(collected, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, err := range collected {
+		m.add(err)
+	}
+	m.omitted += omitted
+}
+
+// add collects given error, or counts it beyond the limit. m.mu must be held.
+func (m *MultiError) add(err error) {
+	if m.limit > 0 && len(m.errs) >= m.limit {
+		m.omitted++
+		return
+	}
+	m.errs = append(m.errs, err)
+}
+
+// Err returns the MultiError if it has any error, or nil otherwise.
+func (m *MultiError) Err() error {
+	if m.Len() == 0 {
+		return nil
+	}
+	return m
+}
+
+// Len returns the number of errors appended, including omitted ones.
+func (m *MultiError) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.errs) + m.omitted
+}
+
+// Omitted returns the number of errors counted beyond the limit.
+// It is used by logging.Err to render "errors_omitted".
+func (m *MultiError) Omitted() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.omitted
+}
+
+// Error returns messages of collected errors on each line, followed by "and N more" if errors are omitted.
+func (m *MultiError) Error() string {
+	errs, omitted := m.snapshot()
+	messages := make([]string, 0, len(errs)+1)
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	if omitted > 0 {
+		messages = append(messages, "and "+strconv.Itoa(omitted)+" more")
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns collected errors, so that errors.Is and errors.As find them.
+func (m *MultiError) Unwrap() []error {
+	errs, _ := m.snapshot()
+	return errs
+}
+
+// Format formats the error. %+v formats each collected error with %+v separated by blank lines,
+// and other verbs print the message.
+func (m *MultiError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		errs, omitted := m.snapshot()
+		for i, err := range errs {
+			if i > 0 {
+				_, _ = io.WriteString(s, "\n\n")
+			}
+			_, _ = fmt.Fprintf(s, "%+v", err)
+		}
+		if omitted > 0 {
+			_, _ = fmt.Fprintf(s, "\n\nand %d more", omitted)
+		}
+	case verb == 'q':
+		_, _ = fmt.Fprintf(s, "%q", m.Error())
+	default:
+		_, _ = io.WriteString(s, m.Error())
+	}
+}
+
+// snapshot returns a copy of collected errors and the number of omitted errors.
+func (m *MultiError) snapshot() ([]error, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]error(nil), m.errs...), m.omitted
+}
@@ -0,0 +1,136 @@
+package errorsx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	if err := Join(nil, nil); err != nil {
+		t.Errorf("expect nil, but received %v", err)
+	}
+
+	first := WithFields(New("first"), "id", 1)
+	second := errors.New("second")
+	err := Join(first, nil, second)
+	if diff := cmp.Diff("first\nsecond", err.Error()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if !errors.Is(err, first) || !errors.Is(err, second) {
+		t.Errorf("expect both errors, but received %v", err)
+	}
+
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("expect *Error, but received %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"id": 1}, Fields(e)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestMultiErrorLimit(t *testing.T) {
+	t.Parallel()
+
+	errs := NewMultiError(2)
+	if err := errs.Err(); err != nil {
+		t.Errorf("expect nil, but received %v", err)
+	}
+	for i := range 3 {
+		errs.Append(fmt.Errorf("item %d failed", i))
+	}
+	errs.Append(Join(errors.New("item 3 failed"), errors.New("item 4 failed")))
+
+	err := errs.Err()
+	if diff := cmp.Diff("item 0 failed\nitem 1 failed\nand 3 more", err.Error()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(5, errs.Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(3, errs.Omitted()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(2, len(errs.Unwrap())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestMultiErrorFormat(t *testing.T) {
+	t.Parallel()
+
+	errs := NewMultiError(1)
+	errs.Append(New("first"), New("second"))
+
+	verbose := fmt.Sprintf("%+v", errs)
+	if !strings.HasPrefix(verbose, "first\ngithub.com/aqyuki/util/errorsx.TestMultiErrorFormat\n\t") {
+		t.Errorf("expect the stack trace of the first error, but received %q", verbose)
+	}
+	if !strings.HasSuffix(verbose, "\n\nand 1 more") {
+		t.Errorf("expect the omitted count, but received %q", verbose)
+	}
+}
+
+func TestMultiErrorConcurrent(t *testing.T) {
+	t.Parallel()
+
+	errs := NewMultiError(0)
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs.Append(fmt.Errorf("worker %d failed", i))
+		}()
+	}
+	wg.Wait()
+
+	if diff := cmp.Diff(10, errs.Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestMultiErrorAppendSelf(t *testing.T) {
+	t.Parallel()
+
+	errs := NewMultiError(0)
+	errs.Append(errors.New("first"))
+	errs.Append(errs, errs.Err())
+
+	if diff := cmp.Diff("first", errs.Error()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestMultiErrorAppendEachOther(t *testing.T) {
+	t.Parallel()
+
+	a, b := NewMultiError(10), NewMultiError(10)
+	a.Append(errors.New("a"))
+	b.Append(errors.New("b"))
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			a.Append(b)
+		}()
+		go func() {
+			defer wg.Done()
+			b.Append(a)
+		}()
+	}
+	wg.Wait()
+
+	if a.Len() < 2 || b.Len() < 2 {
+		t.Errorf("expect errors of each other, but received %d and %d", a.Len(), b.Len())
+	}
+}
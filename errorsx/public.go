@@ -0,0 +1,27 @@
+package errorsx
+
+// WithPublicMessage returns an error which attaches given message for users to given error,
+// such as "try again later", so that HTTP and gRPC servers can respond with it while details of the error go to logs.
+// The message of the error is not changed. If err is nil, WithPublicMessage returns nil.
+func WithPublicMessage(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return annotate(err, func(e *Error) {
+		e.public = msg
+	})
+}
+
+// PublicMessage returns the outermost public message attached by WithPublicMessage in the tree of given error,
+// including errors wrapped by errors.Join and Join, and reports whether it is found.
+func PublicMessage(err error) (string, bool) {
+	var msg string
+	found := walk(err, func(e error) bool {
+		if x, ok := e.(*Error); ok && x.public != "" {
+			msg = x.public
+			return true
+		}
+		return false
+	})
+	return msg, found
+}
@@ -0,0 +1,66 @@
+package errorsx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWithPublicMessage(t *testing.T) {
+	t.Parallel()
+
+	if err := WithPublicMessage(nil, "ignored"); err != nil {
+		t.Errorf("expect nil, but received %v", err)
+	}
+
+	cause := WithPublicMessage(errors.New("dial tcp 10.0.0.1:5432: connection refused"), "try again later")
+	err := fmt.Errorf("query failed: %w", cause)
+	if diff := cmp.Diff("query failed: dial tcp 10.0.0.1:5432: connection refused", err.Error()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	msg, ok := PublicMessage(err)
+	if !ok {
+		t.Fatal("expect a public message, but not found")
+	}
+	if diff := cmp.Diff("try again later", msg); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	outer := WithPublicMessage(Wrap(cause, "load dashboard"), "dashboard is unavailable")
+	if msg, _ := PublicMessage(outer); msg != "dashboard is unavailable" {
+		t.Errorf("expect the outermost public message, but received %q", msg)
+	}
+	if msg, _ := PublicMessage(Join(errors.New("boom"), cause)); msg != "try again later" {
+		t.Errorf("expect the public message of the joined error, but received %q", msg)
+	}
+	if _, ok := PublicMessage(errors.New("plain")); ok {
+		t.Error("expect no public message, but found")
+	}
+}
+
+func TestWithPublicMessageSentinel(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("unavailable")
+	err := WithPublicMessage(sentinel, "try again later")
+	if !errors.Is(err, sentinel) {
+		t.Errorf("expect %v, but received %v", sentinel, err)
+	}
+}
+
+func TestGRPCStatusWithPublicMessage(t *testing.T) {
+	t.Parallel()
+
+	err := WithCode(WithPublicMessage(New("dial tcp 10.0.0.1:5432: connection refused"), "try again later"), Unavailable)
+	s := status.Convert(err)
+	if diff := cmp.Diff(codes.Unavailable, s.Code()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("try again later", s.Message()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,43 @@
+package errorsx
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// maxStackDepth is a maximum number of frames captured in a stack trace.
+const maxStackDepth = 32
+
+// stack is a captured stack trace.
+type stack []uintptr
+
+// callers captures the stack trace of the function calling it, skipping given number of callers above it,
+// such as one for the constructor of errors.
+func callers(skip int) *stack {
+	pcs := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers and callers itself.
+	n := runtime.Callers(2+skip, pcs)
+	s := stack(pcs[:n])
+	return &s
+}
+
+// String formats the stack trace as same as stack traces of zap, a function and its location on each frame.
+func (s *stack) String() string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(*s)
+	for {
+		frame, more := frames.Next()
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+		if !more {
+			return b.String()
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package logging
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultAsyncQueueSize is a default number of entries queued by an async core.
+const defaultAsyncQueueSize = 4096
+
+// AsyncConfig is a configuration of asynchronous logging.
+type AsyncConfig struct {
+	// QueueSize is a maximum number of entries waiting to be written. If zero, 4096 is used.
+	QueueSize int
+
+	// Block reports whether logging blocks while the queue is full.
+	// If false, entries are dropped and counted as log_entries_dropped_total{sink="async"}.
+	Block bool
+}
+
+// WithAsync makes the logger write entries from a background goroutine, so that logging does not wait for sinks.
+// Entries higher than error level are written synchronously after queued entries,
+// because the process may exit right after them. Use Flush to wait for queued entries.
+// Values referenced by fields must not be modified after logging, because they are encoded later.
+func WithAsync(config AsyncConfig) Option {
+	return func(o *options) {
+		o.async = &config
+	}
+}
+
+// asyncQueues holds queues of async cores created via WithAsync until they are closed, so that Flush can drain them.
+var asyncQueues struct {
+	mu     sync.Mutex
+	queues []*asyncQueue
+}
+
+// Flush waits until entries queued by loggers created with WithAsync are written, or given context is done.
+func Flush(ctx context.Context) error {
+	asyncQueues.mu.Lock()
+	queues := append([]*asyncQueue(nil), asyncQueues.queues...)
+	asyncQueues.mu.Unlock()
+
+	var err error
+	for _, q := range queues {
+		err = multierr.Append(err, q.flush(ctx))
+	}
+	return err
+}
+
+// newTrackedAsyncCore creates an async core which is drained by Flush.
+func newTrackedAsyncCore(core zapcore.Core, config AsyncConfig) *AsyncCore {
+	c := NewAsyncCore(core, config)
+	asyncQueues.mu.Lock()
+	asyncQueues.queues = append(asyncQueues.queues, c.q)
+	asyncQueues.mu.Unlock()
+	return c
+}
+
+// untrackAsyncQueue unregisters given queue from Flush.
+func untrackAsyncQueue(q *asyncQueue) {
+	asyncQueues.mu.Lock()
+	defer asyncQueues.mu.Unlock()
+	for i, tracked := range asyncQueues.queues {
+		if tracked == q {
+			asyncQueues.queues = append(asyncQueues.queues[:i], asyncQueues.queues[i+1:]...)
+			return
+		}
+	}
+}
+
+// AsyncCore is a zapcore.Core which queues entries and writes them to the wrapped core from a background goroutine.
+// The wrapped core still decides which entries are written, when they are logged.
+type AsyncCore struct {
+	zapcore.Core
+
+	q *asyncQueue
+}
+
+// NewAsyncCore wraps given core, so that entries are written asynchronously, and starts its goroutine.
+// Close the returned core to stop the goroutine.
+func NewAsyncCore(core zapcore.Core, config AsyncConfig) *AsyncCore {
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultAsyncQueueSize
+	}
+	q := &asyncQueue{
+		block:   config.Block,
+		items:   make(chan asyncItem, config.QueueSize),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go q.run()
+	return &AsyncCore{Core: core, q: q}
+}
+
+// With returns a child core with given fields.
+func (c *AsyncCore) With(fields []zapcore.Field) zapcore.Core {
+	return &AsyncCore{Core: c.Core.With(fields), q: c.q}
+}
+
+// Check asks the wrapped core whether given entry should be written,
+// and if so, adds a writer which queues the entry.
+func (c *AsyncCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	downstream := c.Core.Check(ent, nil)
+	if downstream == nil {
+		return ce
+	}
+	w := &asyncWriter{Core: c.Core, q: c.q, downstream: downstream}
+	w.outer = ce.AddCore(ent, w)
+	return w.outer
+}
+
+// Sync waits for queued entries and syncs the wrapped core.
+func (c *AsyncCore) Sync() error {
+	return multierr.Append(c.q.flush(context.Background()), c.Core.Sync())
+}
+
+// Flush waits until queued entries are written, or given context is done.
+func (c *AsyncCore) Flush(ctx context.Context) error {
+	return c.q.flush(ctx)
+}
+
+// Close writes queued entries and stops the goroutine, or gives up when given context is done.
+// Entries logged after Close are written synchronously.
+func (c *AsyncCore) Close(ctx context.Context) error {
+	return c.q.close(ctx)
+}
+
+// asyncWriter is a zapcore.Core added to a checked entry by AsyncCore.
+type asyncWriter struct {
+	zapcore.Core
+
+	q *asyncQueue
+
+	// downstream is a checked entry of the wrapped core.
+	downstream *zapcore.CheckedEntry
+
+	// outer is a checked entry of the logger which this writer is added to.
+	outer *zapcore.CheckedEntry
+}
+
+// Write queues given entry and fields. Entries higher than error level are written synchronously after queued entries.
+func (w *asyncWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	w.downstream.Entry = ent
+	w.downstream.ErrorOutput = w.outer.ErrorOutput
+
+	if ent.Level > zapcore.ErrorLevel {
+		_ = w.q.flush(context.Background())
+		w.downstream.Write(fields...)
+		return nil
+	}
+	w.q.add(asyncItem{ce: w.downstream, fields: append([]zapcore.Field(nil), fields...)})
+	return nil
+}
+
+// asyncItem is a queued entry, or a flush request if done is not nil.
+type asyncItem struct {
+	ce     *zapcore.CheckedEntry
+	fields []zapcore.Field
+	done   chan struct{}
+}
+
+// asyncQueue is a queue of entries written by a background goroutine.
+type asyncQueue struct {
+	block bool
+	items chan asyncItem
+
+	// closeOnce guards closing done.
+	closeOnce sync.Once
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// add queues given item. If the queue is closed, the item is written synchronously.
+func (q *asyncQueue) add(item asyncItem) {
+	select {
+	case <-q.done:
+		item.ce.Write(item.fields...)
+		return
+	default:
+	}
+
+	if q.block {
+		select {
+		case q.items <- item:
+		case <-q.done:
+			item.ce.Write(item.fields...)
+		}
+		return
+	}
+	select {
+	case q.items <- item:
+	default:
+		droppedEntries.WithLabelValues("async").Inc()
+	}
+}
+
+// flush waits until items queued before the call are written, or given context is done.
+func (q *asyncQueue) flush(ctx context.Context) error {
+	done := make(chan struct{})
+	select {
+	case q.items <- asyncItem{done: done}:
+	case <-q.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-q.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close writes queued items and stops the goroutine, or gives up when given context is done.
+// The queue is no longer drained by Flush.
+func (q *asyncQueue) close(ctx context.Context) error {
+	err := q.flush(ctx)
+	q.closeOnce.Do(func() {
+		close(q.done)
+		untrackAsyncQueue(q)
+	})
+	if err != nil {
+		return err
+	}
+	select {
+	case <-q.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run writes queued items until the queue is closed. Remaining items are written before it returns.
+func (q *asyncQueue) run() {
+	defer close(q.stopped)
+	for {
+		select {
+		case item := <-q.items:
+			q.write(item)
+		case <-q.done:
+			for {
+				select {
+				case item := <-q.items:
+					q.write(item)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// write writes given entry, or notifies the flush request.
+func (q *asyncQueue) write(item asyncItem) {
+	if item.done != nil {
+		close(item.done)
+		return
+	}
+	item.ce.Write(item.fields...)
+}
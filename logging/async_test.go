@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// blockingSyncer is a zapcore.WriteSyncer which blocks writes until released.
+type blockingSyncer struct {
+	zaptest.Buffer
+	release chan struct{}
+	once    sync.Once
+}
+
+func (s *blockingSyncer) Write(p []byte) (int, error) {
+	<-s.release
+	return s.Buffer.Write(p)
+}
+
+func (s *blockingSyncer) unblock() {
+	s.once.Do(func() { close(s.release) })
+}
+
+func TestWithAsync(t *testing.T) {
+	t.Parallel()
+
+	ws := &blockingSyncer{release: make(chan struct{})}
+	defer ws.unblock()
+	logger := NewLogger(WithWriteSyncer(ws), WithLevel("info"), WithAsync(AsyncConfig{QueueSize: 16}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Debug("ignored")
+		logger.Info("first")
+		logger.Info("second")
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expect logging not to wait for the writer")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := Flush(ctx); err == nil {
+		t.Error("expect an error while the writer is blocked, but received nil")
+	}
+
+	ws.unblock()
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff(2, len(ws.Lines())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestAsyncCoreDrop(t *testing.T) {
+	t.Parallel()
+
+	counter := droppedEntries.WithLabelValues("async")
+	before := testutil.ToFloat64(counter)
+
+	ws := &blockingSyncer{release: make(chan struct{})}
+	inner := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), ws, zapcore.DebugLevel)
+	core := NewAsyncCore(inner, AsyncConfig{QueueSize: 1})
+	logger := zap.New(core)
+
+	// The first entry is being written, the second one is queued, and the third one is dropped.
+	logger.Info("first")
+	time.Sleep(10 * time.Millisecond)
+	logger.Info("second")
+	logger.Info("third")
+
+	ws.unblock()
+	if err := core.Close(context.Background()); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff(before+1, testutil.ToFloat64(counter)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	logger.Info("after close")
+	if diff := cmp.Diff(3, len(ws.Lines())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestAsyncCoreSynchronousPanic(t *testing.T) {
+	t.Parallel()
+
+	inner, logs := observer.New(zapcore.DebugLevel)
+	core := NewAsyncCore(inner, AsyncConfig{})
+	defer core.Close(context.Background())
+	logger := zap.New(core)
+
+	logger.Info("queued")
+	func() {
+		defer func() { _ = recover() }()
+		logger.Panic("panic")
+	}()
+
+	// The panic entry is written after the queued entry, before Panic returns.
+	var messages []string
+	for _, entry := range logs.All() {
+		messages = append(messages, entry.Message)
+	}
+	if diff := cmp.Diff([]string{"queued", "panic"}, messages); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,243 @@
+// Package audit provides a logger of audit events with a fixed schema, written to sinks dedicated to audit events.
+//
+// Events are never sampled or dropped: each event is written and synced before Log returns, and failures are returned.
+// Each event has a sequence number and a SHA-256 hash chained to the previous event, so that Verify detects
+// modified, removed, or reordered events.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/aqyuki/util/logging"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Outcome is a result of an audited action.
+type Outcome string
+
+const (
+	// OutcomeSuccess means the action succeeded.
+	OutcomeSuccess Outcome = "success"
+
+	// OutcomeFailure means the action failed.
+	OutcomeFailure Outcome = "failure"
+
+	// OutcomeDenied means the action was denied by authorization.
+	OutcomeDenied Outcome = "denied"
+)
+
+// Event is an audit event.
+type Event struct {
+	// Actor is an identity who performed the action, such as a user ID. It is required.
+	Actor string
+
+	// Action is a name of the action, such as "user.delete". It is required.
+	Action string
+
+	// Resource is an identity of the resource which the action targeted, such as "users/42".
+	Resource string
+
+	// Outcome is a result of the action. If empty, OutcomeSuccess is used.
+	Outcome Outcome
+
+	// Timestamp is a time when the action was performed. If zero, the time of Log is used.
+	Timestamp time.Time
+
+	// Details are additional attributes of the event. They must be encodable as JSON.
+	Details map[string]any
+}
+
+// Checkpoint is a position of a chain of events, used to continue the chain across restarts.
+type Checkpoint struct {
+	// Sequence is a sequence number of the last event. Zero means the chain is empty.
+	Sequence uint64
+
+	// Hash is a hash of the last event. It is empty if the chain is empty.
+	Hash string
+}
+
+// Config is a configuration of the audit logger.
+type Config struct {
+	// OutputPaths are paths to write events, accepted by zap.Open such as file paths and "stdout".
+	// They should be separate from outputs of application logs.
+	OutputPaths []string
+
+	// Writers are writers to write events in addition to output paths.
+	Writers []zapcore.WriteSyncer
+
+	// Checkpoint is the last position of the chain written before, such as the one returned by Verify.
+	// If zero, a new chain is started.
+	Checkpoint Checkpoint
+
+	// Clock is a source of timestamps of events. If nil, the system clock is used.
+	Clock logging.Clock
+}
+
+// record is an encoded form of an event.
+type record struct {
+	Sequence  uint64         `json:"seq"`
+	Timestamp time.Time      `json:"timestamp"`
+	Actor     string         `json:"actor"`
+	Action    string         `json:"action"`
+	Resource  string         `json:"resource,omitempty"`
+	Outcome   Outcome        `json:"outcome"`
+	RequestID string         `json:"request_id,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+	PrevHash  string         `json:"prev_hash"`
+
+	// Hash is a hash of the event chained to PrevHash. It is appended after encoding the other fields.
+	Hash string `json:"hash,omitempty"`
+}
+
+// Logger writes audit events. It is safe for concurrent use.
+type Logger struct {
+	ws    zapcore.WriteSyncer
+	close func()
+	clock logging.Clock
+
+	// mu guards fields below, and serializes writes to keep the chain in order.
+	mu     sync.Mutex
+	last   Checkpoint
+	closed bool
+}
+
+// New creates an audit logger which writes events to given outputs as JSON lines.
+// It returns an error if no output is given or outputs can not be opened.
+func New(config Config) (*Logger, error) {
+	if len(config.OutputPaths) == 0 && len(config.Writers) == 0 {
+		return nil, errors.New("audit: no output")
+	}
+
+	writers := append([]zapcore.WriteSyncer(nil), config.Writers...)
+	closeSink := func() {}
+	if len(config.OutputPaths) > 0 {
+		sink, closePaths, err := zap.Open(config.OutputPaths...)
+		if err != nil {
+			return nil, fmt.Errorf("audit: failed to open outputs: %w", err)
+		}
+		writers = append(writers, sink)
+		closeSink = closePaths
+	}
+
+	clock := config.Clock
+	if clock == nil {
+		clock = zapcore.DefaultClock
+	}
+	return &Logger{
+		ws:    zap.CombineWriteSyncers(writers...),
+		close: closeSink,
+		clock: clock,
+		last:  config.Checkpoint,
+	}, nil
+}
+
+// Log writes given event and syncs the outputs. The request ID of given context is recorded if any.
+// If the event is invalid or writing fails, it returns an error, and the chain is not advanced.
+func (l *Logger) Log(ctx context.Context, event Event) error {
+	if event.Actor == "" || event.Action == "" {
+		return errors.New("audit: actor and action are required")
+	}
+	if event.Outcome == "" {
+		event.Outcome = OutcomeSuccess
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = l.clock.Now()
+	}
+	requestID, _ := logging.RequestIDFromContext(ctx)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return errors.New("audit: logger is closed")
+	}
+
+	r := record{
+		Sequence:  l.last.Sequence + 1,
+		Timestamp: event.Timestamp.UTC(),
+		Actor:     event.Actor,
+		Action:    event.Action,
+		Resource:  event.Resource,
+		Outcome:   event.Outcome,
+		RequestID: requestID,
+		Details:   event.Details,
+		PrevHash:  l.last.Hash,
+	}
+	content, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("audit: failed to encode event: %w", err)
+	}
+	hash := hashContent(content)
+	if _, err := l.ws.Write(appendHash(content, hash)); err != nil {
+		return fmt.Errorf("audit: failed to write event: %w", err)
+	}
+	if err := l.ws.Sync(); err != nil && !ignorableSyncError(err) {
+		return fmt.Errorf("audit: failed to sync event: %w", err)
+	}
+	l.last = Checkpoint{Sequence: r.Sequence, Hash: hash}
+	return nil
+}
+
+// Checkpoint returns the position of the last written event.
+func (l *Logger) Checkpoint() Checkpoint {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.last
+}
+
+// Close syncs and closes the outputs opened from output paths. Events logged after Close fail.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	err := l.ws.Sync()
+	l.close()
+	if err != nil && !ignorableSyncError(err) {
+		return fmt.Errorf("audit: failed to sync outputs: %w", err)
+	}
+	return nil
+}
+
+// hashField is a prefix of the hash field, which is always the last field of encoded events.
+const hashField = `,"hash":"`
+
+// hashContent returns the hash of given encoded event without the hash field.
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// appendHash returns a line of given encoded event with the hash field appended as the last field.
+// The hash is computed from the encoded bytes rather than values, so that Verify reproduces it from the line as written.
+func appendHash(content []byte, hash string) []byte {
+	line := make([]byte, 0, len(content)+len(hashField)+len(hash)+3)
+	line = append(line, content[:len(content)-1]...)
+	line = append(line, hashField...)
+	line = append(line, hash...)
+	return append(line, '"', '}', '\n')
+}
+
+// ignorableSyncError reports whether given error of syncing consists only of errors of files which do not support it,
+// such as stdout attached to a terminal.
+func ignorableSyncError(err error) bool {
+	for _, err := range multierr.Errors(err) {
+		if !errors.Is(err, syscall.EINVAL) && !errors.Is(err, syscall.ENOTTY) && !errors.Is(err, syscall.EBADF) {
+			return false
+		}
+	}
+	return true
+}
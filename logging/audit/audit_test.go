@@ -0,0 +1,180 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aqyuki/util/logging"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+)
+
+// fixedClock is a logging.Clock which always returns the same time.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func (c fixedClock) NewTicker(d time.Duration) *time.Ticker {
+	return time.NewTicker(d)
+}
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	buf := &zaptest.Buffer{}
+	logger, err := New(Config{Writers: []zapcore.WriteSyncer{buf}, Clock: fixedClock(now)})
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	t.Cleanup(func() { _ = logger.Close() })
+
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	events := []Event{
+		{Actor: "alice", Action: "user.delete", Resource: "users/42", Details: map[string]any{"reason": "spam"}},
+		{Actor: "bob", Action: "user.update", Resource: "users/42", Outcome: OutcomeDenied},
+	}
+	for _, event := range events {
+		if err := logger.Log(ctx, event); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+	}
+
+	lines := buf.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("expect 2 events, but received %d", len(lines))
+	}
+	var first map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("expect JSON event, but received %v", err)
+	}
+	want := map[string]any{
+		"seq":        float64(1),
+		"timestamp":  "2024-01-02T03:04:05Z",
+		"actor":      "alice",
+		"action":     "user.delete",
+		"resource":   "users/42",
+		"outcome":    "success",
+		"request_id": "req-1",
+		"details":    map[string]any{"reason": "spam"},
+		"prev_hash":  "",
+		"hash":       first["hash"],
+	}
+	if diff := cmp.Diff(want, first); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	checkpoint, err := Verify(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff(logger.Checkpoint(), checkpoint); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(uint64(2), checkpoint.Sequence); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestLoggerInvalidEvent(t *testing.T) {
+	t.Parallel()
+
+	logger, err := New(Config{Writers: []zapcore.WriteSyncer{&zaptest.Buffer{}}})
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if err := logger.Log(context.Background(), Event{Action: "user.delete"}); err == nil {
+		t.Error("expect error for missing actor, but received nil")
+	}
+	if diff := cmp.Diff(Checkpoint{}, logger.Checkpoint()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if err := logger.Log(context.Background(), Event{Actor: "alice", Action: "user.delete"}); err == nil {
+		t.Error("expect error after close, but received nil")
+	}
+}
+
+func TestNewWithoutOutput(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(Config{}); err == nil {
+		t.Error("expect error, but received nil")
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	logger, err := New(Config{Writers: []zapcore.WriteSyncer{buf}})
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	for _, actor := range []string{"alice", "bob", "carol"} {
+		if err := logger.Log(context.Background(), Event{Actor: actor, Action: "login"}); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+	}
+	lines := buf.Lines()
+
+	cases := map[string][]string{
+		"modified":  {lines[0], strings.Replace(lines[1], "bob", "mallory", 1), lines[2]},
+		"removed":   {lines[0], lines[2]},
+		"reordered": {lines[1], lines[0], lines[2]},
+		"appended":  {lines[0], strings.TrimSuffix(lines[1], "}") + `,"extra":true}`, lines[2]},
+	}
+	for name, tampered := range cases {
+		if _, err := Verify(strings.NewReader(strings.Join(tampered, "\n"))); err == nil {
+			t.Errorf("%s: expect error, but received nil", name)
+		}
+	}
+}
+
+func TestLoggerContinuesChain(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	for _, actor := range []string{"alice", "bob"} {
+		var checkpoint Checkpoint
+		if data, err := os.ReadFile(path); err == nil {
+			if checkpoint, err = Verify(bytes.NewReader(data)); err != nil {
+				t.Fatalf("expect no error, but received %v", err)
+			}
+		}
+		logger, err := New(Config{OutputPaths: []string{path}, Checkpoint: checkpoint})
+		if err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+		if err := logger.Log(context.Background(), Event{Actor: actor, Action: "login"}); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+		if err := logger.Close(); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpoint, err := Verify(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff(uint64(2), checkpoint.Sequence); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// maxLineSize is a maximum size of an encoded event read by Verify.
+const maxLineSize = 1 << 20
+
+// Verify reads events written by Logger from given reader, and checks that their sequence numbers are consecutive
+// and their hashes form a chain. It returns the checkpoint of the last event, so that a new logger can continue the chain.
+// If the chain is broken, it returns an error which describes the first broken event.
+func Verify(r io.Reader) (Checkpoint, error) {
+	return VerifyFrom(r, Checkpoint{})
+}
+
+// VerifyFrom is same as Verify, but the first event must follow given checkpoint,
+// such as the checkpoint of events archived before.
+func VerifyFrom(r io.Reader, from Checkpoint) (Checkpoint, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	last := from
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return last, fmt.Errorf("audit: line %d: invalid event: %w", line, err)
+		}
+		if rec.Sequence != last.Sequence+1 {
+			return last, fmt.Errorf("audit: line %d: expect sequence %d, but found %d", line, last.Sequence+1, rec.Sequence)
+		}
+		if rec.PrevHash != last.Hash {
+			return last, fmt.Errorf("audit: line %d: previous hash does not match event %d", line, last.Sequence)
+		}
+		if !validHash(scanner.Bytes(), rec.Hash) {
+			return last, fmt.Errorf("audit: line %d: hash does not match the content of event %d", line, rec.Sequence)
+		}
+		last = Checkpoint{Sequence: rec.Sequence, Hash: rec.Hash}
+	}
+	if err := scanner.Err(); err != nil {
+		return last, fmt.Errorf("audit: failed to read events: %w", err)
+	}
+	return last, nil
+}
+
+// validHash reports whether given line of an event has given hash.
+func validHash(line []byte, hash string) bool {
+	i := bytes.LastIndex(line, []byte(hashField))
+	if i < 0 || hash == "" || string(line[i:]) != hashField+hash+`"}` {
+		return false
+	}
+	content := append(line[:i:i], '}')
+	return hashContent(content) == hash
+}
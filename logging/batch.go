@@ -0,0 +1,226 @@
+package logging
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultQueueSize is a default number of items queued by a batcher.
+	defaultQueueSize = 8192
+
+	// defaultBatchSize is a default maximum number of items sent at once by a batcher.
+	defaultBatchSize = 256
+
+	// defaultFlushInterval is a default interval to send queued items by a batcher.
+	defaultFlushInterval = time.Second
+)
+
+// batcherConfig is a configuration of batcher.
+type batcherConfig struct {
+	// sink is a name of the destination reported as "sink" label of dropped entries.
+	sink string
+
+	// queueSize is a maximum number of queued items.
+	queueSize int
+
+	// batchSize is a maximum number of items sent at once.
+	batchSize int
+
+	// interval is an interval to send queued items even if the batch is not full.
+	interval time.Duration
+
+	// block reports whether add blocks while the queue is full, instead of dropping the item.
+	block bool
+}
+
+// partialSendError is returned by send functions of batchers when only some items of a batch are sent,
+// so that only unsent items are spooled or dropped, instead of sending accepted items again.
+type partialSendError[T any] struct {
+	unsent []T
+	err    error
+}
+
+// Error returns the message of the underlying error.
+func (e *partialSendError[T]) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *partialSendError[T]) Unwrap() error {
+	return e.err
+}
+
+// unsentItems returns items of given batch which were not sent due to given error.
+func unsentItems[T any](batch []T, err error) []T {
+	var partial *partialSendError[T]
+	if errors.As(err, &partial) {
+		return partial.unsent
+	}
+	return batch
+}
+
+// batcher queues items and sends them in batches from a background goroutine,
+// so that writers are not blocked by slow destinations.
+type batcher[T any] struct {
+	config batcherConfig
+	send   func([]T) error
+
+	// spool persists batches which failed to be sent. If nil, they are dropped.
+	spool *spool[T]
+
+	queue   chan T
+	flushes chan chan error
+
+	// closeOnce guards closing done.
+	closeOnce sync.Once
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// newBatcher creates a batcher which sends items via given function, and starts its goroutine.
+// Zero values of the configuration are replaced with defaults.
+func newBatcher[T any](config batcherConfig, send func([]T) error) *batcher[T] {
+	return newSpoolingBatcher(config, send, nil)
+}
+
+// newSpoolingBatcher is same as newBatcher, but batches which failed to be sent are persisted to given spool,
+// and they are replayed after a batch is sent successfully or on each interval. If spool is nil, they are dropped.
+func newSpoolingBatcher[T any](config batcherConfig, send func([]T) error, spool *spool[T]) *batcher[T] {
+	if config.queueSize <= 0 {
+		config.queueSize = defaultQueueSize
+	}
+	if config.batchSize <= 0 {
+		config.batchSize = defaultBatchSize
+	}
+	if config.interval <= 0 {
+		config.interval = defaultFlushInterval
+	}
+
+	b := &batcher[T]{
+		config:  config,
+		send:    send,
+		spool:   spool,
+		queue:   make(chan T, config.queueSize),
+		flushes: make(chan chan error),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// add queues given item. If the queue is full and the batcher does not block, the item is dropped
+// and counted as log_entries_dropped_total. It reports whether the item is queued.
+func (b *batcher[T]) add(item T) bool {
+	select {
+	case <-b.done:
+		droppedEntries.WithLabelValues(b.config.sink).Inc()
+		return false
+	default:
+	}
+
+	if b.config.block {
+		select {
+		case b.queue <- item:
+			return true
+		case <-b.done:
+		}
+	} else {
+		select {
+		case b.queue <- item:
+			return true
+		default:
+		}
+	}
+	droppedEntries.WithLabelValues(b.config.sink).Inc()
+	return false
+}
+
+// flush sends queued items and waits for it. It returns the error of the last send, if any.
+func (b *batcher[T]) flush() error {
+	errc := make(chan error, 1)
+	select {
+	case b.flushes <- errc:
+		return <-errc
+	case <-b.stopped:
+		return nil
+	}
+}
+
+// close sends queued items and stops the goroutine. Items added after close are dropped.
+func (b *batcher[T]) close() error {
+	err := b.flush()
+	b.closeOnce.Do(func() { close(b.done) })
+	<-b.stopped
+	return err
+}
+
+// run collects queued items into batches and sends them until the batcher is closed.
+func (b *batcher[T]) run() {
+	defer close(b.stopped)
+
+	ticker := time.NewTicker(b.config.interval)
+	defer ticker.Stop()
+
+	batch := make([]T, 0, b.config.batchSize)
+	var lastErr error
+	replay := func() {
+		if b.spool != nil && b.spool.pending() {
+			_ = b.spool.replay(b.send)
+		}
+	}
+	sendBatch := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := b.send(batch); err != nil {
+			unsent := unsentItems(batch, err)
+			if b.spool == nil || b.spool.store(unsent) != nil {
+				droppedEntries.WithLabelValues(b.config.sink).Add(float64(len(unsent)))
+			}
+			lastErr = err
+		} else {
+			replay()
+		}
+		batch = make([]T, 0, b.config.batchSize)
+	}
+
+	for {
+		select {
+		case item := <-b.queue:
+			batch = append(batch, item)
+			if len(batch) >= b.config.batchSize {
+				sendBatch()
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				sendBatch()
+			} else {
+				replay()
+			}
+		case errc := <-b.flushes:
+			for drained := false; !drained; {
+				select {
+				case item := <-b.queue:
+					batch = append(batch, item)
+					if len(batch) >= b.config.batchSize {
+						sendBatch()
+					}
+				default:
+					drained = true
+				}
+			}
+			sendBatch()
+			errc <- lastErr
+			lastErr = nil
+		case <-b.done:
+			for len(b.queue) > 0 {
+				batch = append(batch, <-b.queue)
+			}
+			sendBatch()
+			return
+		}
+	}
+}
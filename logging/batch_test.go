@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestBatcher(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var batches [][]int
+	b := newBatcher(batcherConfig{sink: "batch-test", batchSize: 2, interval: time.Hour}, func(items []int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, append([]int(nil), items...))
+		return nil
+	})
+
+	for i := 1; i <= 3; i++ {
+		b.add(i)
+	}
+	if err := b.close(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if diff := cmp.Diff([][]int{{1, 2}, {3}}, batches); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestBatcherDrop(t *testing.T) {
+	t.Parallel()
+
+	counter := droppedEntries.WithLabelValues("batch-drop-test")
+	before := testutil.ToFloat64(counter)
+
+	release := make(chan struct{})
+	b := newBatcher(batcherConfig{sink: "batch-drop-test", queueSize: 1, batchSize: 1, interval: time.Hour}, func([]int) error {
+		<-release
+		return errors.New("unavailable")
+	})
+
+	// The first item is being sent, the second one is queued, and the rest are dropped.
+	b.add(1)
+	time.Sleep(10 * time.Millisecond)
+	for i := 2; i <= 4; i++ {
+		b.add(i)
+	}
+	close(release)
+
+	if err := b.close(); err == nil {
+		t.Error("expect an error, but received nil")
+	}
+	// Two items are dropped because the queue is full, and two items are dropped because sending failed.
+	if diff := cmp.Diff(before+4, testutil.ToFloat64(counter)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,164 @@
+package logging
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// bufferedContextMaxEntries is a maximum number of entries held by a buffered context.
+// If exceeded, the oldest entry is dropped and counted as log_entries_dropped_total{sink="buffer"}.
+const bufferedContextMaxEntries = 1024
+
+// WithBufferedContext returns a context which buffers debug and info entries logged via FromContext in memory.
+// Buffered entries are written only if an entry at error level or higher is logged via the context
+// before FlushOrDiscard is called, so that failing requests keep full detail while successful ones stay quiet.
+// Entries at warn level are written immediately, so they precede buffered entries in the output.
+// Entries are still filtered at the logger's level, so use debug level to buffer debug entries.
+// If given context already buffers entries, it will return given context as it is.
+func WithBufferedContext(ctx context.Context) context.Context {
+	if bufferFromContext(ctx) != nil {
+		return ctx
+	}
+	return withContextLogger(ctx, func(c *contextLogger) {
+		c.buffer = &entryBuffer{}
+	})
+}
+
+// FlushOrDiscard ends buffering of given context created by WithBufferedContext.
+// If no error has been logged via the context, buffered entries are discarded.
+// Entries logged via the context afterwards are written immediately.
+func FlushOrDiscard(ctx context.Context) {
+	if buf := bufferFromContext(ctx); buf != nil {
+		buf.discard()
+	}
+}
+
+// bufferFromContext returns an entry buffer stored in given context.
+// If not contained entry buffer from given context, it will return nil.
+func bufferFromContext(ctx context.Context) *entryBuffer {
+	if stored := loggerFromContext(ctx); stored != nil {
+		return stored.buffer
+	}
+	return nil
+}
+
+// entryBuffer holds entries checked by the wrapped core until an error is logged.
+type entryBuffer struct {
+	// mu guards fields below.
+	mu      sync.Mutex
+	entries []bufferedEntry
+
+	// done reports whether entries are written immediately, because an error is logged or buffering has ended.
+	done bool
+}
+
+// bufferedEntry is an entry waiting to be written.
+type bufferedEntry struct {
+	// ce is a checked entry of the wrapped core.
+	ce     *zapcore.CheckedEntry
+	fields []zapcore.Field
+}
+
+// wrap returns given logger whose entries are buffered.
+func (b *entryBuffer) wrap(logger *zap.Logger) *zap.Logger {
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &bufferedCore{Core: core, buf: b}
+	}))
+}
+
+// add buffers given entry. It reports false if the entry should be written immediately.
+func (b *entryBuffer) add(entry bufferedEntry) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.done {
+		return false
+	}
+	if len(b.entries) >= bufferedContextMaxEntries {
+		b.entries = b.entries[1:]
+		droppedEntries.WithLabelValues("buffer").Inc()
+	}
+	b.entries = append(b.entries, entry)
+	return true
+}
+
+// flush writes buffered entries in order, and makes later entries written immediately.
+func (b *entryBuffer) flush() {
+	b.mu.Lock()
+	entries := b.entries
+	b.entries = nil
+	b.done = true
+	b.mu.Unlock()
+
+	for _, entry := range entries {
+		entry.ce.Write(entry.fields...)
+	}
+}
+
+// discard drops buffered entries, and makes later entries written immediately.
+func (b *entryBuffer) discard() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = nil
+	b.done = true
+}
+
+// bufferedCore is a zapcore.Core which buffers debug and info entries checked by the wrapped core,
+// and writes them when an entry at error level or higher is logged.
+type bufferedCore struct {
+	zapcore.Core
+
+	buf *entryBuffer
+}
+
+// With returns a child core with given fields.
+func (c *bufferedCore) With(fields []zapcore.Field) zapcore.Core {
+	return &bufferedCore{Core: c.Core.With(fields), buf: c.buf}
+}
+
+// Check writes buffered entries before an entry at error level or higher,
+// and adds a writer which buffers debug and info entries enabled by the wrapped core.
+func (c *bufferedCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level >= zapcore.ErrorLevel {
+		c.buf.flush()
+		return c.Core.Check(ent, ce)
+	}
+	if ent.Level >= zapcore.WarnLevel {
+		return c.Core.Check(ent, ce)
+	}
+
+	downstream := c.Core.Check(ent, nil)
+	if downstream == nil {
+		return ce
+	}
+	w := &bufferedWriter{Core: c.Core, buf: c.buf, downstream: downstream}
+	w.outer = ce.AddCore(ent, w)
+	return w.outer
+}
+
+// bufferedWriter is a zapcore.Core added to a checked entry by bufferedCore.
+type bufferedWriter struct {
+	zapcore.Core
+
+	buf *entryBuffer
+
+	// downstream is a checked entry of the wrapped core.
+	downstream *zapcore.CheckedEntry
+
+	// outer is a checked entry of the logger which this writer is added to.
+	outer *zapcore.CheckedEntry
+}
+
+// Write buffers given entry and fields. If buffering has ended, it writes them immediately.
+func (w *bufferedWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	w.downstream.Entry = ent
+	w.downstream.ErrorOutput = w.outer.ErrorOutput
+
+	if !w.buf.add(bufferedEntry{ce: w.downstream, fields: append([]zapcore.Field(nil), fields...)}) {
+		w.downstream.Write(fields...)
+	}
+	return nil
+}
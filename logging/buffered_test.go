@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithBufferedContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		fail bool
+		want []string
+	}{
+		{name: "success", want: []string{"warn"}},
+		{name: "failure", fail: true, want: []string{"warn", "debug", "info", "error"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &zaptest.Buffer{}
+			ctx := WithLogger(context.Background(), NewLogger(WithWriteSyncer(buf), WithLevel("debug")))
+			ctx = WithBufferedContext(ctx)
+
+			logger := FromContext(ctx).With("key", "value")
+			logger.Debug("debug")
+			logger.Info("info")
+			logger.Warn("warn")
+			if tt.fail {
+				StructuredFromContext(ctx).Error("error")
+			}
+			FlushOrDiscard(ctx)
+
+			var messages []string
+			for _, line := range buf.Lines() {
+				for _, msg := range []string{"debug", "info", "warn", "error"} {
+					if strings.Contains(line, `"msg":"`+msg+`"`) {
+						messages = append(messages, msg)
+					}
+				}
+			}
+			if diff := cmp.Diff(tt.want, messages); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+
+			FromContext(ctx).Info("after")
+			lines := buf.Lines()
+			if !strings.Contains(lines[len(lines)-1], `"msg":"after"`) {
+				t.Errorf("expect entries after FlushOrDiscard to be written, but received %v", lines)
+			}
+		})
+	}
+}
+
+func TestWithBufferedContextLevel(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	ctx := WithLogger(context.Background(), NewLogger(WithWriteSyncer(buf), WithLevel("info")))
+	ctx = WithBufferedContext(ctx)
+	if WithBufferedContext(ctx) != ctx {
+		t.Error("expect the context which already buffers entries to be returned as it is")
+	}
+
+	FromContext(ctx).Debug("ignored")
+	FromContext(ctx).Error("error")
+	if diff := cmp.Diff(1, len(buf.Lines())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
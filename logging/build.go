@@ -0,0 +1,319 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newStructuredLogger creates a structured logger with given options.
+// It also returns the atomic level and component levels used by the logger,
+// so that they can be changed at runtime.
+// If failed to build the logger, it will return a no-op logger.
+func newStructuredLogger(opts ...Option) (*zap.Logger, zap.AtomicLevel, *componentLevels) {
+	logger, level, components, err := buildStructuredLogger(opts...)
+	if err != nil {
+		logger = zap.NewNop()
+	}
+	return logger, level, components
+}
+
+// buildStructuredLogger creates a structured logger with given options as same as newStructuredLogger,
+// but it returns an error instead of a no-op logger if failed to build the logger.
+func buildStructuredLogger(opts ...Option) (*zap.Logger, zap.AtomicLevel, *componentLevels, error) {
+	o := newOptions(opts...)
+
+	var level zap.AtomicLevel
+	if o.atomicLevel != nil {
+		level = *o.atomicLevel
+		if o.levelSet {
+			level.SetLevel(o.level)
+		}
+	} else {
+		level = zap.NewAtomicLevelAt(o.level)
+	}
+
+	components := newComponentLevels(o.componentLevels)
+
+	logger, err := build(o, level, components)
+	return logger, level, components, err
+}
+
+// build creates a logger from given options, level, and component levels.
+// It follows the same steps as zap.Config.Build, so that the mode defaults are kept,
+// but it allows writers which can not be expressed as output paths.
+func build(o *options, level zap.AtomicLevel, components *componentLevels) (*zap.Logger, error) {
+	// config is a configuration to use base to create logger.
+	var config zap.Config
+
+	if o.develop {
+		config = zap.NewDevelopmentConfig()
+	} else {
+		config = zap.NewProductionConfig()
+	}
+
+	if o.caller != nil {
+		config.DisableCaller = !*o.caller
+	}
+	if o.stacktraceDisabled {
+		config.DisableStacktrace = true
+	}
+	if o.samplingSet {
+		config.Sampling = o.sampling
+	}
+
+	for _, modify := range o.encoderConfigs {
+		modify(&config.EncoderConfig)
+	}
+
+	encoding := config.Encoding
+	if o.encoding != "" {
+		encoding = o.encoding
+	}
+	// encoderConfig is a configuration of the encoder of the logger's own output.
+	// It is separated from additional sinks, because colors depend on the output.
+	encoderConfig := config.EncoderConfig
+	paths := outputPaths(o, config.OutputPaths)
+	if encoding == "console" && !o.levelEncodingSet && colorEnabled(o.color, paths, len(o.writers)) {
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+
+	// closers release resources owned by the logger, in the order to be called.
+	closers := append([]closeFunc(nil), o.closers...)
+	// fail releases resources acquired so far, and returns given error.
+	fail := func(err error) (*zap.Logger, error) {
+		for _, fn := range closers {
+			_ = fn(context.Background())
+		}
+		return nil, err
+	}
+
+	enc, err := newEncoder(encoding, encoderConfig)
+	if err != nil {
+		return fail(err)
+	}
+
+	sink, closeSink, err := openSink(o, paths)
+	if err != nil {
+		return fail(err)
+	}
+	closers = appendCloser(closers, closeSink)
+	errSink, closeErrSink, err := zap.Open(config.ErrorOutputPaths...)
+	if err != nil {
+		return fail(err)
+	}
+	if !stdStreams(config.ErrorOutputPaths) {
+		closers = append(closers, closeOpened(closeErrSink))
+	}
+
+	for _, err := range o.errs {
+		fmt.Fprintf(errSink, "%v logging: %v\n", time.Now(), err)
+	}
+
+	core := newLeveledCore(enc, sink, level, zapcore.DebugLevel, components)
+	if len(o.sinks) > 0 || o.stderrLevel != nil || len(o.cores) > 0 || len(o.leveledCores) > 0 {
+		cores := []zapcore.Core{core}
+		if o.stderrLevel != nil {
+			cores = append(cores, newLeveledCore(enc.Clone(), o.stderr, level, *o.stderrLevel, components))
+		}
+		for _, s := range o.sinks {
+			c, closeSinkCore, err := newSinkCore(s, config.EncoderConfig, encoding, level, components)
+			if err != nil {
+				return fail(err)
+			}
+			cores = append(cores, c)
+			closers = appendCloser(closers, closeSinkCore)
+		}
+		for _, c := range o.leveledCores {
+			cores = append(cores, newComponentFilter(c, level, zapcore.DebugLevel, components))
+			closers = appendCoreCloser(closers, c)
+		}
+		for _, c := range o.cores {
+			cores = append(cores, c)
+			closers = appendCoreCloser(closers, c)
+		}
+		core = zapcore.NewTee(cores...)
+	}
+	if o.router != nil {
+		destinations := make(map[string]zapcore.Core, len(o.router.destinations))
+		for name, c := range o.router.destinations {
+			destinations[name] = newComponentFilter(c, level, zapcore.DebugLevel, components)
+			closers = appendCoreCloser(closers, c)
+		}
+		core = newRouteCore(core, o.router, destinations)
+	}
+	if len(o.hooks) > 0 {
+		core = newHookCore(core, o.hooks)
+	}
+	if o.schema != nil {
+		core = newSchemaCore(core, *o.schema, errSink)
+	}
+	if scrubbers := o.allScrubbers(); len(scrubbers) > 0 {
+		core = NewScrubCore(core, scrubbers...)
+	}
+	if o.levelRules != nil {
+		core = newLevelRuleCore(core, o.levelRules)
+	}
+	if o.dedupWindow > 0 {
+		core = newDedupCore(core, o.dedupWindow)
+	}
+	if o.rateLimit != nil {
+		core = newRateLimitCore(core, *o.rateLimit)
+	}
+	if o.messageFilters != nil {
+		core = newMessageFilterCore(core, o.messageFilters)
+	}
+	if o.async != nil {
+		async := newTrackedAsyncCore(core, *o.async)
+		closers = append([]closeFunc{async.Close}, closers...)
+		core = async
+	}
+
+	owner := &trackedLogger{closers: closers}
+	zapOpts := append(buildOptions(o, config, core, errSink), zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &ownedCore{Core: core, owner: owner}
+	}))
+	owner.logger = zap.New(core, zapOpts...)
+	if len(closers) > 0 {
+		track(owner)
+	}
+	return owner.logger, nil
+}
+
+// knownEncoding reports whether given name of encoder is supported by newEncoder.
+func knownEncoding(encoding string) bool {
+	switch encoding {
+	case "json", "console", "logfmt":
+		return true
+	default:
+		return false
+	}
+}
+
+// newEncoder creates an encoder with given name and configuration.
+func newEncoder(encoding string, config zapcore.EncoderConfig) (zapcore.Encoder, error) {
+	switch encoding {
+	case "json":
+		return zapcore.NewJSONEncoder(config), nil
+	case "console":
+		return zapcore.NewConsoleEncoder(config), nil
+	case "logfmt":
+		return NewLogfmtEncoder(config), nil
+	default:
+		return nil, fmt.Errorf("logging: unknown encoding %q", encoding)
+	}
+}
+
+// outputPaths returns output paths given via options.
+// If neither output paths nor writers are given, default paths of the mode are used unless they are disabled.
+func outputPaths(o *options, defaultPaths []string) []string {
+	if len(o.outputPaths) == 0 && len(o.writers) == 0 && !o.defaultOutputDisabled {
+		return defaultPaths
+	}
+	return o.outputPaths
+}
+
+// openSink opens given output paths and combines them with writers given via options.
+// It also returns a function to close opened paths, or nil if there is nothing to close.
+func openSink(o *options, paths []string) (zapcore.WriteSyncer, closeFunc, error) {
+	writers := make([]zapcore.WriteSyncer, 0, len(o.writers)+1)
+	var closeSink closeFunc
+	if len(paths) > 0 {
+		sink, closePaths, err := openPaths(paths)
+		if err != nil {
+			return nil, nil, err
+		}
+		writers = append(writers, sink)
+		closeSink = closePaths
+	}
+	writers = append(writers, o.writers...)
+	return zap.CombineWriteSyncers(writers...), closeSink, nil
+}
+
+// buildOptions returns zap options from given options and mode configuration.
+// Given core is flushed before the process exits on fatal entries.
+func buildOptions(o *options, config zap.Config, core zapcore.Core, errSink zapcore.WriteSyncer) []zap.Option {
+	zapOpts := []zap.Option{zap.ErrorOutput(errSink)}
+
+	var onFatal zapcore.CheckWriteHook = zapcore.WriteThenFatal
+	if o.fatalHook != nil {
+		onFatal = o.fatalHook
+	}
+	zapOpts = append(zapOpts, zap.WithFatalHook(fatalHook{core: core, next: onFatal}))
+
+	if config.Development {
+		zapOpts = append(zapOpts, zap.Development())
+	}
+
+	if !config.DisableCaller {
+		zapOpts = append(zapOpts, zap.AddCaller())
+	}
+
+	stackLevel := zap.ErrorLevel
+	if config.Development {
+		stackLevel = zap.WarnLevel
+	}
+	if o.stacktraceLevel != nil {
+		stackLevel = *o.stacktraceLevel
+	}
+	if !config.DisableStacktrace {
+		zapOpts = append(zapOpts, zap.AddStacktrace(stackLevel))
+	}
+
+	if sampling := config.Sampling; sampling != nil {
+		zapOpts = append(zapOpts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewSamplerWithOptions(core, time.Second, sampling.Initial, sampling.Thereafter)
+		}))
+	}
+
+	if len(o.initialFields) > 0 {
+		keys := make([]string, 0, len(o.initialFields))
+		for k := range o.initialFields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		fields := make([]zap.Field, 0, len(keys))
+		for _, k := range keys {
+			fields = append(fields, zap.Any(k, o.initialFields[k]))
+		}
+		zapOpts = append(zapOpts, zap.Fields(fields...))
+	}
+
+	if o.callerSkip != 0 {
+		zapOpts = append(zapOpts, zap.AddCallerSkip(o.callerSkip))
+	}
+
+	if o.clock != nil {
+		zapOpts = append(zapOpts, zap.WithClock(o.clock))
+	}
+
+	zapOpts = append(zapOpts, o.zapOptions...)
+
+	return zapOpts
+}
+
+// newLeveledCore creates a core filtered at given level, and entries below floor are never written.
+// If components is not nil, entries of named components are filtered at their component level instead.
+// Components may be added later, so that the core is component aware even if there is no component yet.
+func newLeveledCore(enc zapcore.Encoder, ws zapcore.WriteSyncer, level zapcore.LevelEnabler, floor zapcore.Level, components *componentLevels) zapcore.Core {
+	if components != nil {
+		return newComponentCore(enc, ws, level, floor, components)
+	}
+	if floor > zapcore.DebugLevel {
+		level = floorLevel(floor, level)
+	}
+	return zapcore.NewCore(enc, ws, level)
+}
+
+// floorLevel returns a level enabler which enables entries at or above given floor,
+// only while they are also enabled by given level.
+func floorLevel(floor zapcore.Level, level zapcore.LevelEnabler) zapcore.LevelEnabler {
+	return zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l >= floor && level.Enabled(l)
+	})
+}
@@ -0,0 +1,254 @@
+package logging
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// cloudWatchMaxBatchBytes is a maximum size of a PutLogEvents request.
+	cloudWatchMaxBatchBytes = 1_048_576
+
+	// cloudWatchMaxBatchEvents is a maximum number of events in a PutLogEvents request.
+	cloudWatchMaxBatchEvents = 10_000
+
+	// cloudWatchEventOverhead is a number of bytes added to the size of each event by CloudWatch Logs.
+	cloudWatchEventOverhead = 26
+
+	// cloudWatchRetries is a number of attempts of a PutLogEvents request.
+	cloudWatchRetries = 5
+
+	// cloudWatchBackoff is an initial duration to wait before retrying throttled requests. It doubles on each attempt.
+	cloudWatchBackoff = 200 * time.Millisecond
+
+	// cloudWatchTimeout is a timeout of a PutLogEvents request.
+	cloudWatchTimeout = 10 * time.Second
+)
+
+// CloudWatchLogsClient is a client of CloudWatch Logs. It is implemented by *cloudwatchlogs.Client.
+type CloudWatchLogsClient interface {
+	PutLogEvents(ctx context.Context, params *cloudwatchlogs.PutLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error)
+}
+
+// CloudWatchConfig is a configuration of CloudWatch Logs destination.
+type CloudWatchConfig struct {
+	// Client is a client of CloudWatch Logs.
+	Client CloudWatchLogsClient
+
+	// LogGroup is a name of the log group. It must exist.
+	LogGroup string
+
+	// LogStream is a name of the log stream. It must exist.
+	LogStream string
+
+	// QueueSize is a maximum number of entries waiting to be sent. If zero, 8192 is used.
+	QueueSize int
+
+	// BatchSize is a maximum number of entries sent at once. If zero, 256 is used.
+	BatchSize int
+
+	// FlushInterval is an interval to send queued entries. If zero, one second is used.
+	FlushInterval time.Duration
+
+	// Block reports whether logging blocks while the queue is full.
+	// If false, entries are dropped and counted as log_entries_dropped_total{sink="cloudwatch"}.
+	Block bool
+
+	// Spool persists batches which could not be delivered to disk, and replays them when CloudWatch Logs is reachable again.
+	// If nil, such batches are dropped.
+	Spool *SpoolConfig
+}
+
+// WithCloudWatch ships entries to CloudWatch Logs as JSON, in addition to the logger's own output.
+// Entries are filtered at the logger's level and component levels.
+func WithCloudWatch(config CloudWatchConfig) Option {
+	return func(o *options) {
+		o.leveledCores = append(o.leveledCores, NewCloudWatchCore(config))
+	}
+}
+
+// cloudWatchCore is a zapcore.Core which sends entries to CloudWatch Logs.
+type cloudWatchCore struct {
+	enc     zapcore.Encoder
+	batcher *batcher[types.InputLogEvent]
+}
+
+// NewCloudWatchCore creates a core which sends every entry to CloudWatch Logs as JSON.
+// Entries are queued and sent in batches via PutLogEvents from a background goroutine.
+// Throttled requests are retried with backoff, and sequence tokens are tracked for streams which require them.
+// Sync sends queued entries and waits for it.
+func NewCloudWatchCore(config CloudWatchConfig) zapcore.Core {
+	s := &cloudWatchSender{config: config}
+	var sp *spool[types.InputLogEvent]
+	if config.Spool != nil {
+		sp = newSpool(*config.Spool, "cloudwatch", encodeCloudWatchEvent, decodeCloudWatchEvent)
+	}
+	return &cloudWatchCore{
+		enc: zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		batcher: newSpoolingBatcher(batcherConfig{
+			sink:      "cloudwatch",
+			queueSize: config.QueueSize,
+			batchSize: config.BatchSize,
+			interval:  config.FlushInterval,
+			block:     config.Block,
+		}, s.send, sp),
+	}
+}
+
+// Enabled reports true for every level.
+func (c *cloudWatchCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With returns a child core with given fields.
+func (c *cloudWatchCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	return &cloudWatchCore{enc: enc, batcher: c.batcher}
+}
+
+// Check adds the core to given checked entry.
+func (c *cloudWatchCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+// Write encodes given entry as JSON and queues it.
+func (c *cloudWatchCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	message := string(trimLineEnding(buf.Bytes()))
+	buf.Free()
+
+	c.batcher.add(types.InputLogEvent{Message: aws.String(message), Timestamp: aws.Int64(ent.Time.UnixMilli())})
+	return nil
+}
+
+// Sync sends queued entries and waits for it.
+func (c *cloudWatchCore) Sync() error {
+	return c.batcher.flush()
+}
+
+// Close sends queued entries and stops the background goroutine, or gives up when given context is done.
+func (c *cloudWatchCore) Close(ctx context.Context) error {
+	return closeWithContext(ctx, c.batcher.close)
+}
+
+// cloudWatchSender sends batches of events. It is used only from the goroutine of batcher.
+type cloudWatchSender struct {
+	config CloudWatchConfig
+
+	// sequenceToken is a token returned by the last request.
+	sequenceToken *string
+}
+
+// send sends given events in chronological order, splitting them into requests within the size and count limits.
+// When some requests fail, it returns *partialSendError holding only events of them,
+// so that events already accepted by CloudWatch Logs are not sent again.
+func (s *cloudWatchSender) send(events []types.InputLogEvent) error {
+	sort.SliceStable(events, func(i, j int) bool {
+		return *events[i].Timestamp < *events[j].Timestamp
+	})
+
+	var (
+		errs   []error
+		unsent []types.InputLogEvent
+	)
+	put := func(events []types.InputLogEvent) {
+		if err := s.put(events); err != nil {
+			errs = append(errs, err)
+			unsent = append(unsent, events...)
+		}
+	}
+	start, size := 0, 0
+	for i, event := range events {
+		eventSize := len(*event.Message) + cloudWatchEventOverhead
+		if i > start && (size+eventSize > cloudWatchMaxBatchBytes || i-start >= cloudWatchMaxBatchEvents) {
+			put(events[start:i])
+			start, size = i, 0
+		}
+		size += eventSize
+	}
+	put(events[start:])
+	if len(errs) == 0 {
+		return nil
+	}
+	return &partialSendError[types.InputLogEvent]{unsent: unsent, err: errors.Join(errs...)}
+}
+
+// put calls PutLogEvents with given events, retrying throttled requests and rejected sequence tokens.
+func (s *cloudWatchSender) put(events []types.InputLogEvent) error {
+	var err error
+	backoff := cloudWatchBackoff
+	for attempt := 0; attempt < cloudWatchRetries; attempt++ {
+		var output *cloudwatchlogs.PutLogEventsOutput
+		output, err = s.putOnce(events)
+		if err == nil {
+			if output != nil && output.NextSequenceToken != nil {
+				s.sequenceToken = output.NextSequenceToken
+			}
+			return nil
+		}
+
+		var invalidToken *types.InvalidSequenceTokenException
+		var accepted *types.DataAlreadyAcceptedException
+		var throttled *types.ThrottlingException
+		var unavailable *types.ServiceUnavailableException
+		switch {
+		case errors.As(err, &accepted):
+			s.sequenceToken = accepted.ExpectedSequenceToken
+			return nil
+		case errors.As(err, &invalidToken):
+			s.sequenceToken = invalidToken.ExpectedSequenceToken
+		case errors.As(err, &throttled), errors.As(err, &unavailable):
+			time.Sleep(backoff)
+			backoff *= 2
+		default:
+			return fmt.Errorf("logging: failed to put %d events to cloudwatch: %w", len(events), err)
+		}
+	}
+	return fmt.Errorf("logging: failed to put %d events to cloudwatch after %d attempts: %w", len(events), cloudWatchRetries, err)
+}
+
+// putOnce calls PutLogEvents once.
+func (s *cloudWatchSender) putOnce(events []types.InputLogEvent) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cloudWatchTimeout)
+	defer cancel()
+	return s.config.Client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String(s.config.LogGroup),
+		LogStreamName: aws.String(s.config.LogStream),
+		LogEvents:     events,
+		SequenceToken: s.sequenceToken,
+	})
+}
+
+// encodeCloudWatchEvent encodes given event for spool, as the timestamp in milliseconds and the message.
+func encodeCloudWatchEvent(event types.InputLogEvent) []byte {
+	b := make([]byte, 0, 8+len(aws.ToString(event.Message)))
+	b = binary.BigEndian.AppendUint64(b, uint64(aws.ToInt64(event.Timestamp)))
+	return append(b, aws.ToString(event.Message)...)
+}
+
+// decodeCloudWatchEvent decodes an event encoded by encodeCloudWatchEvent.
+func decodeCloudWatchEvent(b []byte) (types.InputLogEvent, error) {
+	if len(b) < 8 {
+		return types.InputLogEvent{}, errors.New("logging: malformed spooled cloudwatch event")
+	}
+	return types.InputLogEvent{
+		Timestamp: aws.Int64(int64(binary.BigEndian.Uint64(b))),
+		Message:   aws.String(string(b[8:])),
+	}, nil
+}
@@ -0,0 +1,170 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+)
+
+// cloudWatchRecorder is a CloudWatchLogsClient which records requests and returns given errors in order.
+type cloudWatchRecorder struct {
+	mu     sync.Mutex
+	inputs []*cloudwatchlogs.PutLogEventsInput
+	errs   []error
+}
+
+func (r *cloudWatchRecorder) PutLogEvents(_ context.Context, input *cloudwatchlogs.PutLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.inputs = append(r.inputs, input)
+	if len(r.errs) > 0 {
+		err := r.errs[0]
+		r.errs = r.errs[1:]
+		return nil, err
+	}
+	return &cloudwatchlogs.PutLogEventsOutput{NextSequenceToken: aws.String("next")}, nil
+}
+
+func TestWithCloudWatch(t *testing.T) {
+	t.Parallel()
+
+	client := &cloudWatchRecorder{errs: []error{
+		&types.ThrottlingException{},
+		&types.InvalidSequenceTokenException{ExpectedSequenceToken: aws.String("expected")},
+	}}
+	logger := NewLogger(
+		WithWriteSyncer(&zaptest.Buffer{}),
+		WithCloudWatch(CloudWatchConfig{Client: client, LogGroup: "group", LogStream: "stream"}),
+	)
+	logger.Infow("hello", "user", 42)
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if diff := cmp.Diff(3, len(client.inputs)); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+
+	input := client.inputs[2]
+	if diff := cmp.Diff("expected", aws.ToString(input.SequenceToken)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("group", aws.ToString(input.LogGroupName)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if len(input.LogEvents) != 1 {
+		t.Fatalf("expect 1 event, but received %d", len(input.LogEvents))
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(aws.ToString(input.LogEvents[0].Message)), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	if diff := cmp.Diff("hello", entry["msg"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestCloudWatchSenderSplit(t *testing.T) {
+	t.Parallel()
+
+	client := &cloudWatchRecorder{}
+	s := &cloudWatchSender{config: CloudWatchConfig{Client: client}}
+
+	large := string(make([]byte, cloudWatchMaxBatchBytes/2))
+	events := []types.InputLogEvent{
+		{Message: aws.String(large), Timestamp: aws.Int64(3)},
+		{Message: aws.String(large), Timestamp: aws.Int64(1)},
+		{Message: aws.String("small"), Timestamp: aws.Int64(2)},
+	}
+	if err := s.send(events); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	var timestamps [][]int64
+	for _, input := range client.inputs {
+		var batch []int64
+		for _, event := range input.LogEvents {
+			batch = append(batch, aws.ToInt64(event.Timestamp))
+		}
+		timestamps = append(timestamps, batch)
+	}
+	if diff := cmp.Diff([][]int64{{1, 2}, {3}}, timestamps); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestCloudWatchSenderSplitCount(t *testing.T) {
+	t.Parallel()
+
+	client := &cloudWatchRecorder{}
+	s := &cloudWatchSender{config: CloudWatchConfig{Client: client}}
+
+	events := make([]types.InputLogEvent, cloudWatchMaxBatchEvents+1)
+	for i := range events {
+		events[i] = types.InputLogEvent{Message: aws.String("small"), Timestamp: aws.Int64(int64(i))}
+	}
+	if err := s.send(events); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	var sizes []int
+	for _, input := range client.inputs {
+		sizes = append(sizes, len(input.LogEvents))
+	}
+	if diff := cmp.Diff([]int{cloudWatchMaxBatchEvents, 1}, sizes); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestCloudWatchSenderPartialFailure(t *testing.T) {
+	t.Parallel()
+
+	client := &cloudWatchRecorder{errs: []error{nil, errors.New("boom")}}
+	s := &cloudWatchSender{config: CloudWatchConfig{Client: client}}
+
+	large := string(make([]byte, cloudWatchMaxBatchBytes/2))
+	events := []types.InputLogEvent{
+		{Message: aws.String(large), Timestamp: aws.Int64(1)},
+		{Message: aws.String(large), Timestamp: aws.Int64(2)},
+		{Message: aws.String("small"), Timestamp: aws.Int64(3)},
+	}
+	err := s.send(events)
+	if err == nil {
+		t.Fatal("expect an error, but received nil")
+	}
+
+	var timestamps []int64
+	for _, event := range unsentItems(events, err) {
+		timestamps = append(timestamps, aws.ToInt64(event.Timestamp))
+	}
+	if diff := cmp.Diff([]int64{2, 3}, timestamps); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestCloudWatchEventCodec(t *testing.T) {
+	t.Parallel()
+
+	event := types.InputLogEvent{Message: aws.String(`{"msg":"spooled"}`), Timestamp: aws.Int64(1700000000123)}
+	got, err := decodeCloudWatchEvent(encodeCloudWatchEvent(event))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff(aws.ToString(event.Message), aws.ToString(got.Message)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(aws.ToInt64(event.Timestamp), aws.ToInt64(got.Timestamp)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"os"
+	"strings"
+)
+
+// ColorMode is a mode to decide whether levels are colored in console encoding.
+type ColorMode int
+
+const (
+	// ColorAuto colors levels if the output is a terminal, honoring NO_COLOR and FORCE_COLOR environment variables.
+	ColorAuto ColorMode = iota
+
+	// ColorAlways always colors levels.
+	ColorAlways
+
+	// ColorNever never colors levels.
+	ColorNever
+)
+
+// WithColor sets whether levels are colored in console encoding, overriding NO_COLOR and FORCE_COLOR.
+// By default, ColorAuto is used. Other encodings are never colored.
+func WithColor(mode ColorMode) Option {
+	return func(o *options) {
+		o.color = mode
+	}
+}
+
+// colorEnabled reports whether levels written to given output paths are colored in given mode.
+// In ColorAuto mode, a non-empty NO_COLOR disables colors, and FORCE_COLOR other than "0" or "false" enables them.
+// Otherwise, levels are colored only if every output is stdout or stderr connected to a terminal.
+func colorEnabled(mode ColorMode, paths []string, writers int) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	switch force := strings.ToLower(strings.TrimSpace(os.Getenv("FORCE_COLOR"))); force {
+	case "", "0", "false":
+	default:
+		return true
+	}
+
+	if len(paths) == 0 || writers > 0 {
+		return false
+	}
+	for _, path := range paths {
+		switch path {
+		case "stdout":
+			if !isTerminal(os.Stdout) {
+				return false
+			}
+		case "stderr":
+			if !isTerminal(os.Stderr) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isTerminal reports whether given file is a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
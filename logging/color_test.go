@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestColorEnabled(t *testing.T) {
+	cases := []struct {
+		name    string
+		mode    ColorMode
+		noColor string
+		force   string
+		paths   []string
+		want    bool
+	}{
+		{name: "always", mode: ColorAlways, noColor: "1", want: true},
+		{name: "never", mode: ColorNever, force: "1", paths: []string{"stdout"}, want: false},
+		{name: "no color", noColor: "1", force: "1", want: false},
+		{name: "force color", force: "1", paths: []string{"app.log"}, want: true},
+		{name: "force color disabled", force: "0", paths: []string{"app.log"}, want: false},
+		{name: "file", paths: []string{"app.log"}, want: false},
+		{name: "no output", want: false},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", cs.noColor)
+			t.Setenv("FORCE_COLOR", cs.force)
+			if diff := cmp.Diff(cs.want, colorEnabled(cs.mode, cs.paths, 0)); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithColor(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		encoding string
+		want     bool
+	}{
+		{name: "console", encoding: "console", want: true},
+		{name: "json", encoding: "json", want: false},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &zaptest.Buffer{}
+			logger := NewLogger(WithWriteSyncer(buf), WithEncoding(cs.encoding), WithColor(ColorAlways))
+			logger.Info("message")
+			if diff := cmp.Diff(cs.want, strings.Contains(buf.String(), "\x1b[")); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
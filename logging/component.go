@@ -0,0 +1,228 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// registry holds names of every logger created via Named.
+var registry sync.Map
+
+// Named returns a child logger of the logger stored in given context, named with given component name.
+// If a level is configured for the component, such as "LOG_LEVEL=info,database=debug",
+// entries of the child logger are filtered at the component level instead of the logger's level.
+// The name of the returned logger is recorded, so that it is reported by Levels.
+func Named(ctx context.Context, name string) *zap.SugaredLogger {
+	logger := FromContext(ctx).Named(name)
+	registry.LoadOrStore(logger.Desugar().Name(), struct{}{})
+	return logger
+}
+
+// Levels returns current levels of named loggers created via Named and components configured in default logger.
+// A logger which does not belong to any component reports the level of default logger.
+func Levels() map[string]zapcore.Level {
+	d := loadDefaults()
+
+	levels := make(map[string]zapcore.Level)
+	for name, level := range d.components.snapshot() {
+		levels[name] = level.Level()
+	}
+	registry.Range(func(key, _ any) bool {
+		name := key.(string)
+		if level, ok := d.components.lookup(name); ok {
+			levels[name] = level.Level()
+		} else {
+			levels[name] = d.level.Level()
+		}
+		return true
+	})
+	return levels
+}
+
+// SetComponentLevel changes the level of given component of default logger at runtime.
+// If the component is unknown, it is added. Loggers created via Named after or before the call are affected.
+// If given level can not be parsed, it will return an error and the level is not changed.
+func SetComponentLevel(name string, level string) error {
+	lvl, ok := parseLevel(level)
+	if !ok {
+		return fmt.Errorf("logging: unknown level %q", level)
+	}
+	loadDefaults().components.set(name, lvl)
+	return nil
+}
+
+// componentLevels holds levels of named components.
+// Levels are stored as copy-on-write map, so that lookups on logging path do not take a lock.
+type componentLevels struct {
+	// mu serializes updates of levels.
+	mu     sync.Mutex
+	levels atomic.Pointer[map[string]zap.AtomicLevel]
+
+	// resolved caches the level resolved for each logger name.
+	// It is replaced when a component is added.
+	resolved atomic.Pointer[sync.Map]
+}
+
+// resolvedLevel is a cached result of componentLevels.lookup.
+type resolvedLevel struct {
+	level zap.AtomicLevel
+	ok    bool
+}
+
+// newComponentLevels creates componentLevels from given map of component names to level names.
+// If not parse level, info level is used.
+func newComponentLevels(levels map[string]string) *componentLevels {
+	m := make(map[string]zap.AtomicLevel, len(levels))
+	for name, level := range levels {
+		m[name] = zap.NewAtomicLevelAt(stringToZapLevel(level))
+	}
+
+	c := &componentLevels{}
+	c.levels.Store(&m)
+	c.resolved.Store(&sync.Map{})
+	return c
+}
+
+// set changes the level of given component. If the component is unknown, it is added.
+func (c *componentLevels) set(name string, l zapcore.Level) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current := *c.levels.Load()
+	if level, ok := current[name]; ok {
+		level.SetLevel(l)
+		return
+	}
+
+	m := make(map[string]zap.AtomicLevel, len(current)+1)
+	for k, v := range current {
+		m[k] = v
+	}
+	m[name] = zap.NewAtomicLevelAt(l)
+	c.levels.Store(&m)
+	c.resolved.Store(&sync.Map{})
+}
+
+// setAll changes levels of given components. Unknown components are added.
+func (c *componentLevels) setAll(levels map[string]zapcore.Level) {
+	for name, l := range levels {
+		c.set(name, l)
+	}
+}
+
+// snapshot returns current levels of components.
+func (c *componentLevels) snapshot() map[string]zap.AtomicLevel {
+	return *c.levels.Load()
+}
+
+// lookup returns the level of the component which given logger name belongs to.
+// A logger name belongs to a component if the component appears as dot separated segments of the name,
+// e.g. "app.database.pool" belongs to "database". The most specific component wins.
+func (c *componentLevels) lookup(name string) (zap.AtomicLevel, bool) {
+	if name == "" {
+		return zap.AtomicLevel{}, false
+	}
+	resolved := c.resolved.Load()
+	if v, ok := resolved.Load(name); ok {
+		r := v.(resolvedLevel)
+		return r.level, r.ok
+	}
+
+	var best string
+	var r resolvedLevel
+	for component, level := range c.snapshot() {
+		if len(component) > len(best) && containsSegments(name, component) {
+			best = component
+			r = resolvedLevel{level: level, ok: true}
+		}
+	}
+
+	resolved.Store(name, r)
+	return r.level, r.ok
+}
+
+// enabledAny reports whether any component enables given level.
+func (c *componentLevels) enabledAny(l zapcore.Level) bool {
+	for _, level := range c.snapshot() {
+		if level.Enabled(l) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsSegments reports whether sub appears as contiguous dot separated segments of name.
+func containsSegments(name, sub string) bool {
+	for {
+		i := strings.Index(name, sub)
+		if i < 0 {
+			return false
+		}
+		end := i + len(sub)
+		if (i == 0 || name[i-1] == '.') && (end == len(name) || name[end] == '.') {
+			return true
+		}
+		name = name[i+1:]
+	}
+}
+
+// componentCore is a zapcore.Core which filters entries at the level of their component.
+// Entries which do not belong to any component are filtered at the default level.
+type componentCore struct {
+	zapcore.Core
+
+	// level is a default level for entries which do not belong to any component.
+	level zapcore.LevelEnabler
+
+	// floor is a minimum level applied to every entry regardless of components.
+	floor zapcore.Level
+
+	components *componentLevels
+}
+
+// newComponentCore creates a core which writes entries to given encoder and writer,
+// filtering them at the level of their component or at given default level.
+// Entries below floor are never written.
+func newComponentCore(enc zapcore.Encoder, ws zapcore.WriteSyncer, level zapcore.LevelEnabler, floor zapcore.Level, components *componentLevels) zapcore.Core {
+	c := &componentCore{level: level, floor: floor, components: components}
+	c.Core = zapcore.NewCore(enc, ws, zap.LevelEnablerFunc(c.Enabled))
+	return c
+}
+
+// newComponentFilter wraps given core, so that entries are filtered at the level of their component
+// or at given default level before the core checks them. Entries below floor are never written.
+func newComponentFilter(core zapcore.Core, level zapcore.LevelEnabler, floor zapcore.Level, components *componentLevels) zapcore.Core {
+	return &componentCore{Core: core, level: level, floor: floor, components: components}
+}
+
+// Enabled reports whether given level is enabled by the default level or any component.
+func (c *componentCore) Enabled(l zapcore.Level) bool {
+	return l >= c.floor && (c.level.Enabled(l) || c.components.enabledAny(l))
+}
+
+// With returns a child core with given fields.
+func (c *componentCore) With(fields []zapcore.Field) zapcore.Core {
+	return &componentCore{Core: c.Core.With(fields), level: c.level, floor: c.floor, components: c.components}
+}
+
+// Check adds the core to given checked entry if the entry is enabled at its component level.
+func (c *componentCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < c.floor {
+		return ce
+	}
+
+	enabled := c.level.Enabled(ent.Level)
+	if level, ok := c.components.lookup(ent.LoggerName); ok {
+		enabled = level.Enabled(ent.Level)
+	}
+	if !enabled {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
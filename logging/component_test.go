@@ -0,0 +1,134 @@
+package logging
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestNamed(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(
+		WithLevel("info"),
+		WithComponentLevels(map[string]string{"database": "debug", "http": "warn"}),
+		WithWriteSyncer(buf),
+	)
+	ctx := WithLogger(context.Background(), logger)
+
+	logger.Debug("root debug")
+	logger.Info("root info")
+	Named(ctx, "database").Debug("database debug")
+	Named(ctx, "database").Named("pool").Debug("pool debug")
+	Named(ctx, "http").Info("http info")
+	Named(ctx, "http").Warn("http warn")
+	Named(ctx, "cache").Debug("cache debug")
+	_ = logger.Sync()
+
+	var messages []string
+	for _, line := range buf.Lines() {
+		for _, msg := range []string{"root info", "database debug", "pool debug", "http warn"} {
+			if strings.Contains(line, `"msg":"`+msg+`"`) {
+				messages = append(messages, msg)
+			}
+		}
+	}
+	if diff := cmp.Diff([]string{"root info", "database debug", "pool debug", "http warn"}, messages); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(4, len(buf.Lines())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestComponentLevelsLookup(t *testing.T) {
+	t.Parallel()
+
+	c := newComponentLevels(map[string]string{"database": "debug", "database.pool": "error"})
+
+	cases := []struct {
+		name  string
+		found bool
+		want  string
+	}{
+		{name: "", found: false},
+		{name: "http", found: false},
+		{name: "database", found: true, want: "debug"},
+		{name: "app.database", found: true, want: "debug"},
+		{name: "app.database.pool", found: true, want: "error"},
+		{name: "databases", found: false},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.name, func(t *testing.T) {
+			t.Parallel()
+
+			level, ok := c.lookup(cs.name)
+			if diff := cmp.Diff(cs.found, ok); diff != "" {
+				t.Fatalf("(-want, +got)\n%s", diff)
+			}
+			if ok {
+				if diff := cmp.Diff(cs.want, level.String()); diff != "" {
+					t.Errorf("(-want, +got)\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestSetComponentLevel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv("LOG_LEVEL", "info,database=warn")
+	t.Setenv("LOG_OUTPUT", path)
+	ResetDefault()
+	t.Cleanup(ResetDefault)
+
+	worker := Named(context.Background(), "worker")
+	worker.Debug("before")
+
+	levels := Levels()
+	if diff := cmp.Diff(zap.InfoLevel, levels["worker"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(zap.WarnLevel, levels["database"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	if err := SetComponentLevel("worker", "debug"); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	worker.Debug("after")
+
+	if err := SetComponentLevel("database", "error"); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	Named(context.Background(), "database").Warn("database warn")
+
+	if err := SetComponentLevel("worker", "unknown"); err == nil {
+		t.Error("expect error, but received nil")
+	}
+
+	levels = Levels()
+	if diff := cmp.Diff(zap.DebugLevel, levels["worker"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(zap.ErrorLevel, levels["database"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	_ = DefaultLogger().Sync()
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("after", entries[0]["msg"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,373 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// configReloadInterval is an interval to check whether the config file is changed.
+const configReloadInterval = time.Second
+
+// FileConfig is a configuration of a logger read from a YAML or JSON file by NewLoggerFromConfig.
+type FileConfig struct {
+	// Mode is "develop" to switch logger mode to develop mode. Otherwise, production mode is used.
+	Mode string `json:"mode" yaml:"mode"`
+
+	// Level is a minimum level, optionally followed by component levels such as "info,database=debug",
+	// as same as LOG_LEVEL. It is reloaded at runtime.
+	Level string `json:"level" yaml:"level"`
+
+	// Components is a map of component names to their levels, merged with component levels of Level.
+	// It is reloaded at runtime.
+	Components map[string]string `json:"components" yaml:"components"`
+
+	// Encoding is a name of encoder such as "json", "console", or "logfmt".
+	Encoding string `json:"encoding" yaml:"encoding"`
+
+	// Outputs is a list of paths to write logs, as same as LOG_OUTPUT.
+	Outputs []string `json:"outputs" yaml:"outputs"`
+
+	// Sinks is a list of additional destinations with their own encoding and level.
+	Sinks []FileSinkConfig `json:"sinks" yaml:"sinks"`
+
+	// Sampling is a configuration of sampling. If nil, the default of the mode is used.
+	Sampling *FileSamplingConfig `json:"sampling" yaml:"sampling"`
+
+	// Redaction is a configuration of redaction. If nil, entries are not redacted.
+	Redaction *FileRedactionConfig `json:"redaction" yaml:"redaction"`
+
+	// Filters is a list of regular expressions of messages to drop. It is reloaded at runtime.
+	Filters []string `json:"filters" yaml:"filters"`
+
+	// LevelRules is a list of rules to change levels of entries by field values. It is reloaded at runtime.
+	LevelRules []FileLevelRule `json:"level_rules" yaml:"level_rules"`
+}
+
+// FileSinkConfig is a configuration of an additional destination in FileConfig.
+type FileSinkConfig struct {
+	// Encoding is a name of encoder. If empty, the encoding of the logger is used.
+	Encoding string `json:"encoding" yaml:"encoding"`
+
+	// Level is a minimum level of the sink. If empty, the level of the logger is used.
+	Level string `json:"level" yaml:"level"`
+
+	// Color reports whether levels are colored.
+	Color bool `json:"color" yaml:"color"`
+
+	// Outputs is a list of paths to write logs.
+	Outputs []string `json:"outputs" yaml:"outputs"`
+}
+
+// FileSamplingConfig is a configuration of sampling in FileConfig.
+type FileSamplingConfig struct {
+	// Disabled reports whether sampling is disabled.
+	Disabled bool `json:"disabled" yaml:"disabled"`
+
+	// Initial and Thereafter are arguments of WithSampling.
+	Initial    int `json:"initial" yaml:"initial"`
+	Thereafter int `json:"thereafter" yaml:"thereafter"`
+}
+
+// FileRedactionConfig is a configuration of redaction in FileConfig.
+type FileRedactionConfig struct {
+	// Keys is a list of field keys to redact. If empty, DefaultRedactedKeys is used.
+	Keys []string `json:"keys" yaml:"keys"`
+
+	// Patterns is a list of regular expressions to mask in messages and string values.
+	Patterns []string `json:"patterns" yaml:"patterns"`
+}
+
+// FileLevelRule is a LevelRule in FileConfig.
+type FileLevelRule struct {
+	Field string `json:"field" yaml:"field"`
+	Value string `json:"value" yaml:"value"`
+	Level string `json:"level" yaml:"level"`
+}
+
+// ReadFileConfig reads a configuration from given file.
+// Files with ".json" extension are decoded as JSON, and others are decoded as YAML. Unknown keys are rejected.
+func ReadFileConfig(path string) (FileConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return FileConfig{}, fmt.Errorf("logging: failed to read config: %w", err)
+	}
+
+	var config FileConfig
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&config)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true)
+		if err = dec.Decode(&config); err != nil && len(bytes.TrimSpace(b)) == 0 {
+			err = nil
+		}
+	}
+	if err != nil {
+		return FileConfig{}, fmt.Errorf("logging: failed to decode config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// NewLoggerFromConfig creates a logger from given YAML or JSON file described by FileConfig.
+// The file is watched, and changes of level, component levels, filters, and level rules are applied
+// to the logger without recreating it. Other changes require a new logger.
+// Failures of reloading are reported by the logger. The watch stops on Close.
+// Environment variables are not applied.
+func NewLoggerFromConfig(path string) (*zap.SugaredLogger, error) {
+	logger, err := newStructuredLoggerFromConfig(path, configReloadInterval)
+	if err != nil {
+		return nil, err
+	}
+	return logger.Sugar(), nil
+}
+
+// newStructuredLoggerFromConfig creates a logger from given file, and watches the file at given interval.
+func newStructuredLoggerFromConfig(path string, interval time.Duration) (*zap.Logger, error) {
+	config, err := ReadFileConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := config.options()
+	if err != nil {
+		return nil, err
+	}
+	live, err := config.reloadable()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &configWatcher{
+		liveConfig: liveConfig{rules: NewLevelRules(live.rules...), filters: &messageFilters{}},
+		path:       path,
+		stop:       make(chan struct{}),
+	}
+	w.filters.set(live.filters)
+	w.modTime, w.size = statFile(path)
+
+	opts = append(opts, WithLevelRules(w.rules), func(o *options) {
+		o.messageFilters = w.filters
+		o.closers = append(o.closers, w.close)
+	})
+	w.logger, w.level, w.components, err = buildStructuredLogger(opts...)
+	if err != nil {
+		return nil, err
+	}
+	w.level.SetLevel(live.level)
+	w.components.setAll(live.components)
+
+	go w.run(interval)
+	return w.logger, nil
+}
+
+// options converts the configuration to options, except for reloadable ones.
+func (c FileConfig) options() ([]Option, error) {
+	opts := []Option{WithDevelopment(strings.EqualFold(strings.TrimSpace(c.Mode), "develop"))}
+
+	if c.Encoding != "" {
+		if !knownEncoding(c.Encoding) {
+			return nil, fmt.Errorf("logging: unknown encoding %q", c.Encoding)
+		}
+		opts = append(opts, WithEncoding(c.Encoding))
+	}
+	if len(c.Outputs) > 0 {
+		opts = append(opts, WithOutputPaths(c.Outputs...))
+	}
+
+	for _, s := range c.Sinks {
+		if s.Encoding != "" && !knownEncoding(s.Encoding) {
+			return nil, fmt.Errorf("logging: unknown encoding %q", s.Encoding)
+		}
+		sink := SinkConfig{Encoding: s.Encoding, Color: s.Color, OutputPaths: s.Outputs}
+		if s.Level != "" {
+			lvl, ok := parseLevel(s.Level)
+			if !ok {
+				return nil, fmt.Errorf("logging: unknown level %q", s.Level)
+			}
+			sink.Level = lvl
+		}
+		opts = append(opts, WithTee(sink))
+	}
+
+	if c.Sampling != nil {
+		if c.Sampling.Disabled {
+			opts = append(opts, WithoutSampling())
+		} else {
+			opts = append(opts, WithSampling(c.Sampling.Initial, c.Sampling.Thereafter))
+		}
+	}
+
+	if c.Redaction != nil {
+		patterns, err := compilePatterns(c.Redaction.Patterns)
+		if err != nil {
+			return nil, err
+		}
+		keys := c.Redaction.Keys
+		if len(keys) == 0 {
+			keys = nil
+		}
+		opts = append(opts, WithRedaction(NewRedactor(keys, patterns...)))
+	}
+	return opts, nil
+}
+
+// reloadableConfig holds the parts of FileConfig applied at runtime.
+type reloadableConfig struct {
+	level      zapcore.Level
+	components map[string]zapcore.Level
+	filters    []*regexp.Regexp
+	rules      []LevelRule
+}
+
+// reloadable parses the parts of the configuration applied at runtime.
+func (c FileConfig) reloadable() (reloadableConfig, error) {
+	spec, components := parseLevelSpec(c.Level)
+	if components == nil {
+		components = make(map[string]string, len(c.Components))
+	}
+	for name, level := range c.Components {
+		components[name] = level
+	}
+
+	r := reloadableConfig{level: zapcore.InfoLevel, components: make(map[string]zapcore.Level, len(components))}
+	if spec != "" {
+		lvl, ok := parseLevel(spec)
+		if !ok {
+			return reloadableConfig{}, fmt.Errorf("logging: unknown level %q", spec)
+		}
+		r.level = lvl
+	}
+	for name, level := range components {
+		lvl, ok := parseLevel(level)
+		if !ok {
+			return reloadableConfig{}, fmt.Errorf("logging: unknown level %q of component %q", level, name)
+		}
+		r.components[name] = lvl
+	}
+
+	filters, err := compilePatterns(c.Filters)
+	if err != nil {
+		return reloadableConfig{}, err
+	}
+	r.filters = filters
+
+	for _, rule := range c.LevelRules {
+		lvl, ok := parseLevel(rule.Level)
+		if !ok {
+			return reloadableConfig{}, fmt.Errorf("logging: unknown level %q of rule for %q", rule.Level, rule.Field)
+		}
+		if lvl > zapcore.ErrorLevel {
+			return reloadableConfig{}, fmt.Errorf("logging: level %q of rule for %q must not be higher than error", rule.Level, rule.Field)
+		}
+		r.rules = append(r.rules, LevelRule{Field: rule.Field, Value: rule.Value, Level: lvl})
+	}
+	return r, nil
+}
+
+// compilePatterns compiles given regular expressions.
+func compilePatterns(exprs []string) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(exprs))
+	for _, expr := range exprs {
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("logging: invalid pattern %q: %w", expr, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// liveConfig holds the parts of a logger changed by reloadable parts of FileConfig.
+type liveConfig struct {
+	logger     *zap.Logger
+	level      zap.AtomicLevel
+	components *componentLevels
+	rules      *LevelRules
+	filters    *messageFilters
+}
+
+// apply applies reloadable parts of given configuration.
+func (l *liveConfig) apply(config FileConfig) error {
+	live, err := config.reloadable()
+	if err != nil {
+		return err
+	}
+
+	l.level.SetLevel(live.level)
+	l.components.setAll(live.components)
+	l.filters.set(live.filters)
+	l.rules.Set(live.rules...)
+	return nil
+}
+
+// configWatcher applies changes of a config file to a logger.
+type configWatcher struct {
+	liveConfig
+
+	path string
+
+	// modTime and size identify the last loaded content of the file. They are used only by run.
+	modTime time.Time
+	size    int64
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// run checks the file at given interval until the watcher is closed.
+func (w *configWatcher) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			modTime, size := statFile(w.path)
+			if modTime.Equal(w.modTime) && size == w.size {
+				continue
+			}
+			w.modTime, w.size = modTime, size
+			if err := w.reload(); err != nil {
+				w.logger.Error("logging: failed to reload config", zap.String("path", w.path), zap.Error(err))
+			}
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// reload reads the file and applies reloadable parts of it.
+func (w *configWatcher) reload() error {
+	config, err := ReadFileConfig(w.path)
+	if err != nil {
+		return err
+	}
+	return w.apply(config)
+}
+
+// close stops watching the file.
+func (w *configWatcher) close(context.Context) error {
+	w.closeOnce.Do(func() { close(w.stop) })
+	return nil
+}
+
+// statFile returns the modification time and size of given file. If failed, it returns zero values.
+func statFile(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+func TestReadFileConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	want := FileConfig{
+		Level:      "info,database=debug",
+		Encoding:   "json",
+		Outputs:    []string{"stdout"},
+		Sampling:   &FileSamplingConfig{Disabled: true},
+		Filters:    []string{"^health"},
+		LevelRules: []FileLevelRule{{Field: "user", Value: "admin", Level: "warn"}},
+	}
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr bool
+	}{
+		{
+			name: "yaml",
+			file: "logging.yaml",
+			content: `level: info,database=debug
+encoding: json
+outputs: [stdout]
+sampling:
+  disabled: true
+filters: ["^health"]
+level_rules:
+  - {field: user, value: admin, level: warn}
+`,
+		},
+		{
+			name:    "json",
+			file:    "logging.json",
+			content: `{"level":"info,database=debug","encoding":"json","outputs":["stdout"],"sampling":{"disabled":true},"filters":["^health"],"level_rules":[{"field":"user","value":"admin","level":"warn"}]}`,
+		},
+		{name: "unknown key", file: "unknown.yaml", content: "levle: debug\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadFileConfig(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expect an error, but received nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, but received %v", err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewLoggerFromConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logging.yaml")
+	output := filepath.Join(dir, "app.log")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content+"outputs: ["+output+"]\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("level: info\n")
+	logger, err := newStructuredLoggerFromConfig(path, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	logger.Debug("ignored")
+	logger.Info("healthcheck ok")
+
+	write("level: debug\nfilters: [\"^healthcheck\"]\n")
+	deadline := time.Now().Add(5 * time.Second)
+	for !logger.Core().Enabled(zap.DebugLevel) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	logger.Debug("debug")
+	logger.Info("healthcheck ok")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	var messages []any
+	for _, entry := range readEntries(t, output) {
+		messages = append(messages, entry["msg"])
+	}
+	if diff := cmp.Diff([]any{"healthcheck ok", "debug"}, messages); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	if _, err := NewLoggerFromConfig(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expect an error for missing file, but received nil")
+	}
+}
+
+func TestNewLoggerFromConfigBuildError(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	if err := os.WriteFile(path, []byte("outputs: [\"unknown://sink\"]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLoggerFromConfig(path); err == nil {
+		t.Error("expect an error of the unknown sink, but received nil")
+	}
+}
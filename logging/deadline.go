@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// deadlineFieldsEnabled reports whether loggers returned by FromContext have ctx_deadline_remaining field.
+var deadlineFieldsEnabled atomic.Bool
+
+// EnableDeadlineFields switches whether FromContext adds ctx_deadline_remaining field
+// when given context has a deadline. It is disabled by default.
+// The field is the duration until the deadline at the time of the lookup, and negative after the deadline.
+func EnableDeadlineFields(enabled bool) {
+	deadlineFieldsEnabled.Store(enabled)
+}
+
+// deadlineFields returns ctx_deadline_remaining field from given context if enabled.
+func deadlineFields(ctx context.Context) []zap.Field {
+	if !deadlineFieldsEnabled.Load() {
+		return nil
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+	return []zap.Field{zap.Duration("ctx_deadline_remaining", time.Until(deadline))}
+}
+
+// LogIfCanceled logs given message at warn level with the cause of the cancellation via the logger from given context,
+// if the context is canceled or its deadline is exceeded. It reports whether the context is done.
+// The cause is taken via context.Cause, so that causes given to context.WithCancelCause are recorded.
+//
+//	if logging.LogIfCanceled(ctx, "stopped fetching pages") {
+//		return ctx.Err()
+//	}
+func LogIfCanceled(ctx context.Context, msg string) bool {
+	err := ctx.Err()
+	if err == nil {
+		return false
+	}
+	StructuredFromContext(ctx).WithOptions(zap.AddCallerSkip(1)).Warn(msg, zap.String("ctx_err", err.Error()), Err(context.Cause(ctx)))
+	return true
+}
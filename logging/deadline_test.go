@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestEnableDeadlineFields is not parallel, because it changes the global setting of FromContext.
+func TestEnableDeadlineFields(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	base := WithStructuredLogger(context.Background(), zap.New(core))
+	ctx, cancel := context.WithTimeout(base, time.Hour)
+	defer cancel()
+
+	FromContext(ctx).Info("disabled")
+	EnableDeadlineFields(true)
+	t.Cleanup(func() { EnableDeadlineFields(false) })
+	FromContext(ctx).Info("enabled")
+	FromContext(base).Info("no deadline")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("expect 3 entries, but received %d", len(entries))
+	}
+	if _, ok := entries[0].ContextMap()["ctx_deadline_remaining"]; ok {
+		t.Error("expect no deadline field while disabled, but found")
+	}
+	remaining, ok := entries[1].ContextMap()["ctx_deadline_remaining"].(time.Duration)
+	if !ok || remaining <= 0 || remaining > time.Hour {
+		t.Errorf("expect remaining duration within an hour, but received %v", entries[1].ContextMap())
+	}
+	if _, ok := entries[2].ContextMap()["ctx_deadline_remaining"]; ok {
+		t.Error("expect no deadline field without deadline, but found")
+	}
+}
+
+func TestLogIfCanceled(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	base := WithStructuredLogger(context.Background(), zap.New(core))
+	cause := errors.New("client disconnected")
+	ctx, cancel := context.WithCancelCause(base)
+
+	if LogIfCanceled(ctx, "stopped") {
+		t.Error("expect false before cancel, but received true")
+	}
+	cancel(cause)
+	if !LogIfCanceled(ctx, "stopped") {
+		t.Error("expect true after cancel, but received false")
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff(zapcore.WarnLevel, entries[0].Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	fields := entries[0].ContextMap()
+	if diff := cmp.Diff("context canceled", fields["ctx_err"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	errField, _ := fields["error"].(map[string]any)
+	if diff := cmp.Diff("client disconnected", errField["message"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,172 @@
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// repeatCountField is a key of the field which reports the number of collapsed duplicate entries.
+const repeatCountField = "repeat_count"
+
+// WithDedup collapses identical consecutive entries logged within given window of each other.
+// The first entry is written as it is, and the following duplicates are suppressed until a different entry
+// is logged, the window elapses, or the logger is synced. Then the last duplicate is written once
+// with "repeat_count" field reporting the number of suppressed entries.
+// Entries are identical if their level, logger name, message, and fields are equal.
+// Entries higher than error level are never suppressed.
+func WithDedup(window time.Duration) Option {
+	return func(o *options) {
+		o.dedupWindow = window
+	}
+}
+
+// dedupCore is a zapcore.Core which suppresses identical consecutive entries.
+type dedupCore struct {
+	zapcore.Core
+
+	// enc encodes fields added via With, to compare entries including them.
+	enc zapcore.Encoder
+
+	d *deduplicator
+}
+
+// newDedupCore wraps given core, so that identical consecutive entries within given window are collapsed.
+func newDedupCore(core zapcore.Core, window time.Duration) zapcore.Core {
+	return &dedupCore{
+		Core: core,
+		enc:  zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		d:    &deduplicator{window: window},
+	}
+}
+
+// With returns a child core with given fields.
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	return &dedupCore{Core: c.Core.With(fields), enc: enc, d: c.d}
+}
+
+// Check asks the wrapped core whether given entry should be written,
+// and if so, adds a writer which suppresses the entry if it duplicates the previous one.
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level > zapcore.ErrorLevel {
+		c.d.flush()
+		return c.Core.Check(ent, ce)
+	}
+	downstream := c.Core.Check(ent, nil)
+	if downstream == nil {
+		return ce
+	}
+	w := &dedupWriter{Core: c.Core, enc: c.enc, d: c.d, downstream: downstream}
+	w.outer = ce.AddCore(ent, w)
+	return w.outer
+}
+
+// Sync writes suppressed duplicates and syncs the wrapped core.
+func (c *dedupCore) Sync() error {
+	c.d.flush()
+	return c.Core.Sync()
+}
+
+// dedupWriter is a zapcore.Core added to a checked entry by dedupCore.
+type dedupWriter struct {
+	zapcore.Core
+
+	enc zapcore.Encoder
+	d   *deduplicator
+
+	// downstream is a checked entry of the wrapped core.
+	downstream *zapcore.CheckedEntry
+
+	// outer is a checked entry of the logger which this writer is added to.
+	outer *zapcore.CheckedEntry
+}
+
+// Write writes given entry to the wrapped core unless it duplicates the previous one.
+func (w *dedupWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	w.downstream.Entry = ent
+	w.downstream.ErrorOutput = w.outer.ErrorOutput
+
+	key, err := w.enc.EncodeEntry(zapcore.Entry{Level: ent.Level, LoggerName: ent.LoggerName, Message: ent.Message}, fields)
+	if err != nil {
+		w.downstream.Write(fields...)
+		return nil
+	}
+	w.d.write(key.String(), ent.Time, w.downstream, fields)
+	key.Free()
+	return nil
+}
+
+// deduplicator holds the previous entry and suppressed duplicates of it.
+type deduplicator struct {
+	window time.Duration
+
+	// mu guards fields below. It is held while writing, so that entries keep their order.
+	mu sync.Mutex
+
+	// key identifies the previous entry, and last is the time when it was logged.
+	key  string
+	last time.Time
+
+	// count is a number of suppressed duplicates, and pending is the last of them.
+	count   int
+	pending bufferedEntry
+
+	// timer writes suppressed duplicates when the window elapses.
+	timer *time.Timer
+}
+
+// write writes given checked entry unless it duplicates the previous one within the window.
+func (d *deduplicator) write(key string, t time.Time, ce *zapcore.CheckedEntry, fields []zapcore.Field) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if key == d.key && t.Sub(d.last) < d.window {
+		d.count++
+		d.pending = bufferedEntry{ce: ce, fields: append([]zapcore.Field(nil), fields...)}
+		d.last = t
+		if d.timer == nil {
+			d.timer = time.AfterFunc(d.window, d.expire)
+		} else {
+			d.timer.Reset(d.window)
+		}
+		return
+	}
+
+	d.flushLocked()
+	d.key, d.last = key, t
+	ce.Write(fields...)
+}
+
+// flush writes suppressed duplicates.
+func (d *deduplicator) flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.flushLocked()
+}
+
+// expire writes suppressed duplicates when the window elapses, so that the next entry starts a new run.
+func (d *deduplicator) expire() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.flushLocked()
+	d.key = ""
+}
+
+// flushLocked writes the last suppressed duplicate with the number of suppressed entries. d.mu must be held.
+func (d *deduplicator) flushLocked() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if d.count == 0 {
+		return
+	}
+	d.pending.ce.Write(append(d.pending.fields, zap.Int(repeatCountField, d.count))...)
+	d.count, d.pending = 0, bufferedEntry{}
+}
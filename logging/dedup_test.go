@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithDedup(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf), WithDedup(time.Hour))
+	for i := 0; i < 4; i++ {
+		logger.Infow("connection refused", "host", "db")
+	}
+	logger.Infow("connection refused", "host", "cache")
+	logger.With("host", "cache").Info("connection refused")
+	logger.Info("recovered")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	var got []map[string]any
+	for _, line := range buf.Lines() {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("expect JSON entry, but received %v", err)
+		}
+		got = append(got, map[string]any{"msg": entry["msg"], "host": entry["host"], "repeat_count": entry["repeat_count"]})
+	}
+	want := []map[string]any{
+		{"msg": "connection refused", "host": "db", "repeat_count": nil},
+		{"msg": "connection refused", "host": "db", "repeat_count": 3.0},
+		{"msg": "connection refused", "host": "cache", "repeat_count": nil},
+		{"msg": "connection refused", "host": "cache", "repeat_count": 1.0},
+		{"msg": "recovered", "host": nil, "repeat_count": nil},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestWithDedupWindow(t *testing.T) {
+	t.Parallel()
+
+	buf := &lockedBuffer{}
+	logger := NewLogger(WithWriteSyncer(buf), WithDedup(10*time.Millisecond))
+	logger.Info("noisy")
+	logger.Info("noisy")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(buf.Lines()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if diff := cmp.Diff(2, len(buf.Lines())); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+
+	logger.Info("noisy")
+	if diff := cmp.Diff(3, len(buf.Lines())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,16 @@
+package logging
+
+import "go.uber.org/zap"
+
+// WithDualDevelopment switches logger mode to develop mode, writing human friendly console entries to stderr
+// while writing JSON entries to given file simultaneously, so that tools parsing JSON work during local debugging.
+// Console entries are colored if stderr is a terminal, and the file uses the encoder configuration of production mode.
+// Both outputs are filtered at the logger's level and component levels.
+func WithDualDevelopment(path string) Option {
+	config := zap.NewProductionEncoderConfig()
+	return func(o *options) {
+		o.develop = true
+		o.outputPaths = []string{"stderr"}
+		o.sinks = append(o.sinks, SinkConfig{Encoding: "json", EncoderConfig: &config, OutputPaths: []string{path}})
+	}
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithDualDevelopment(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "dev.log")
+	o := newOptions(WithDualDevelopment(path))
+	if diff := cmp.Diff([]string{"stderr"}, o.outputPaths); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	console := &zaptest.Buffer{}
+	logger := NewStructuredLogger(WithDualDevelopment(path), WithOutputPaths(), WithWriteSyncer(console))
+	logger.Info("written")
+	_ = logger.Sync()
+
+	lines := console.Lines()
+	if len(lines) != 1 || !strings.Contains(lines[0], "\tINFO\t") || !strings.Contains(lines[0], "\twritten") {
+		t.Errorf("expect a console entry, but received %q", lines)
+	}
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("written", entries[0]["msg"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("info", entries[0]["level"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,44 @@
+// Package echolog provides Echo middleware which bridges echo.Context to request-scoped loggers of package logging.
+package echolog
+
+import (
+	"time"
+
+	"github.com/aqyuki/util/logging"
+	"github.com/aqyuki/util/logging/httplog"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// Middleware returns an Echo middleware which attaches a request-scoped logger to the request context
+// and writes an access log, in the same way as httplog.Middleware.
+// Errors returned by handlers are passed to the HTTP error handler of Echo before the access log is written,
+// so that the access log has the status of the error response.
+func Middleware(config httplog.Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			req := c.Request()
+			req = req.WithContext(httplog.RequestContext(c.Response(), req, config))
+			c.SetRequest(req)
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			if config.Skip != nil && config.Skip(req) {
+				return nil
+			}
+			res := c.Response()
+			httplog.LogAccess(req.Context(), res.Status, res.Size, time.Since(start))
+			return nil
+		}
+	}
+}
+
+// FromContext returns the request-scoped logger of given Echo context.
+// If the middleware is not used, it will return a default logger.
+func FromContext(c echo.Context) *zap.SugaredLogger {
+	return logging.FromContext(c.Request().Context())
+}
@@ -0,0 +1,55 @@
+package echolog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aqyuki/util/logging/httplog"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	e := echo.New()
+	e.Use(Middleware(httplog.Config{Logger: zap.New(core).Sugar()}))
+	e.GET("/users/:id", func(c echo.Context) error {
+		FromContext(c).Info("handling")
+		return echo.NewHTTPError(http.StatusNotFound, "user not found")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if diff := cmp.Diff(http.StatusNotFound, rec.Code); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("req-1", rec.Header().Get("X-Request-ID")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("req-1", entries[0].ContextMap()["request_id"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(zapcore.WarnLevel, entries[1].Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(int64(http.StatusNotFound), entries[1].ContextMap()["status"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(int64(rec.Body.Len()), entries[1].ContextMap()["bytes"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ecsVersion is a version of Elastic Common Schema which entries follow.
+const ecsVersion = "8.11.0"
+
+// WithECSEncoding makes the logger write JSON entries with field names of Elastic Common Schema,
+// such as "@timestamp", "log.level", "message", and "error.stack_trace",
+// so that they can be ingested by Elasticsearch without a translation pipeline.
+// Error fields created by zap.Error are written as "error.message", because "error" is an object in ECS.
+func WithECSEncoding() Option {
+	return func(o *options) {
+		o.encoding = "json"
+		o.encoderConfigs = append(o.encoderConfigs, ecsEncoderConfig)
+		o.scrubbers = append(o.scrubbers, ScrubberFunc(ecsErrorFields))
+		WithInitialFields(map[string]any{"ecs.version": ecsVersion})(o)
+	}
+}
+
+// ecsEncoderConfig modifies given encoder configuration for Elastic Common Schema.
+func ecsEncoderConfig(config *zapcore.EncoderConfig) {
+	config.TimeKey = "@timestamp"
+	config.LevelKey = "log.level"
+	config.NameKey = "log.logger"
+	config.CallerKey = "log.origin.file.name"
+	config.MessageKey = "message"
+	config.StacktraceKey = "error.stack_trace"
+	config.EncodeTime = zapcore.ISO8601TimeEncoder
+	config.EncodeLevel = zapcore.LowercaseLevelEncoder
+	config.EncodeDuration = zapcore.NanosDurationEncoder
+}
+
+// ecsErrorFields rewrites error fields with "error" key to "error.message".
+func ecsErrorFields(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	var rewritten []zapcore.Field
+	for i, field := range fields {
+		if field.Type != zapcore.ErrorType || field.Key != "error" {
+			continue
+		}
+		err, ok := field.Interface.(error)
+		if !ok {
+			continue
+		}
+		if rewritten == nil {
+			rewritten = append([]zapcore.Field(nil), fields...)
+		}
+		rewritten[i] = zap.String("error.message", err.Error())
+	}
+	if rewritten == nil {
+		return ent, fields
+	}
+	return ent, rewritten
+}
@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithECSEncoding(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	logger := NewStructuredLogger(WithWriteSyncer(buf), WithECSEncoding()).Named("worker")
+	logger.Error("failed", zap.Error(errors.New("boom")))
+
+	lines := buf.Lines()
+	if len(lines) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(lines))
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	want := map[string]any{
+		"log.level":     "error",
+		"log.logger":    "worker",
+		"message":       "failed",
+		"error.message": "boom",
+		"ecs.version":   ecsVersion,
+	}
+	for key, value := range want {
+		if diff := cmp.Diff(value, entry[key]); diff != "" {
+			t.Errorf("%s: (-want, +got)\n%s", key, diff)
+		}
+	}
+	for _, key := range []string{"@timestamp", "error.stack_trace", "log.origin.file.name"} {
+		if _, ok := entry[key]; !ok {
+			t.Errorf("expect %s key, but not found", key)
+		}
+	}
+	if _, ok := entry["error"]; ok {
+		t.Error("expect no error key, but found")
+	}
+}
@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// omitKey is a key name given to EncoderKeys to omit the key from entries.
+const omitKey = "-"
+
+// EncoderKeys is a set of key names of entries. Empty names keep the default of selected mode,
+// and "-" omits the key from entries.
+type EncoderKeys struct {
+	Time       string
+	Level      string
+	Name       string
+	Caller     string
+	Function   string
+	Message    string
+	Stacktrace string
+}
+
+// WithEncoderConfig modifies the encoder configuration of selected mode with given function.
+// It is applied after other options which change the encoder configuration.
+func WithEncoderConfig(modify func(*zapcore.EncoderConfig)) Option {
+	return func(o *options) {
+		o.encoderConfigs = append(o.encoderConfigs, modify)
+	}
+}
+
+// WithEncoderKeys changes key names of entries, such as "@timestamp" instead of "ts".
+func WithEncoderKeys(keys EncoderKeys) Option {
+	return WithEncoderConfig(func(config *zapcore.EncoderConfig) {
+		setKey(&config.TimeKey, keys.Time)
+		setKey(&config.LevelKey, keys.Level)
+		setKey(&config.NameKey, keys.Name)
+		setKey(&config.CallerKey, keys.Caller)
+		setKey(&config.FunctionKey, keys.Function)
+		setKey(&config.MessageKey, keys.Message)
+		setKey(&config.StacktraceKey, keys.Stacktrace)
+	})
+}
+
+// setKey replaces given key name with name, unless name is empty.
+func setKey(key *string, name string) {
+	switch name {
+	case "":
+	case omitKey:
+		*key = zapcore.OmitKey
+	default:
+		*key = name
+	}
+}
+
+// WithTimeFormat sets a format of timestamps.
+// It is one of "rfc3339", "rfc3339nano", "iso8601", "epoch" (seconds), "epoch_millis", and "epoch_nanos",
+// or otherwise a layout of time.Format such as "2006-01-02 15:04:05".
+func WithTimeFormat(format string) Option {
+	return func(o *options) {
+		if format == "" {
+			o.errs = append(o.errs, errors.New("empty time format"))
+			return
+		}
+		encode := timeEncoder(format)
+		WithEncoderConfig(func(config *zapcore.EncoderConfig) {
+			config.EncodeTime = encode
+		})(o)
+	}
+}
+
+// timeEncoder returns an encoder of timestamps in given format.
+func timeEncoder(format string) zapcore.TimeEncoder {
+	switch strings.ToLower(format) {
+	case "rfc3339":
+		return zapcore.RFC3339TimeEncoder
+	case "rfc3339nano":
+		return zapcore.RFC3339NanoTimeEncoder
+	case "iso8601":
+		return zapcore.ISO8601TimeEncoder
+	case "epoch":
+		return zapcore.EpochTimeEncoder
+	case "epoch_millis":
+		return zapcore.EpochMillisTimeEncoder
+	case "epoch_nanos":
+		return zapcore.EpochNanosTimeEncoder
+	default:
+		return zapcore.TimeEncoderOfLayout(format)
+	}
+}
+
+// WithDurationEncoding sets a format of durations.
+// It is one of "string" such as "1.5s", "seconds", "millis", and "nanos".
+// If not parse encoding argument, the default is kept and the error is reported to the error output.
+func WithDurationEncoding(encoding string) Option {
+	return func(o *options) {
+		var encode zapcore.DurationEncoder
+		switch strings.ToLower(encoding) {
+		case "string":
+			encode = zapcore.StringDurationEncoder
+		case "seconds":
+			encode = zapcore.SecondsDurationEncoder
+		case "millis":
+			encode = zapcore.MillisDurationEncoder
+		case "nanos":
+			encode = zapcore.NanosDurationEncoder
+		default:
+			o.errs = append(o.errs, fmt.Errorf("unknown duration encoding %q", encoding))
+			return
+		}
+		WithEncoderConfig(func(config *zapcore.EncoderConfig) {
+			config.EncodeDuration = encode
+		})(o)
+	}
+}
+
+// WithLevelEncoding sets a format of levels.
+// It is one of "lowercase" such as "info", "capital" such as "INFO", "color", and "capital_color".
+// Colors are written regardless of WithColor.
+// If not parse encoding argument, the default is kept and the error is reported to the error output.
+func WithLevelEncoding(encoding string) Option {
+	return func(o *options) {
+		var encode zapcore.LevelEncoder
+		switch strings.ToLower(encoding) {
+		case "lowercase":
+			encode = zapcore.LowercaseLevelEncoder
+		case "capital":
+			encode = zapcore.CapitalLevelEncoder
+		case "color":
+			encode = zapcore.LowercaseColorLevelEncoder
+		case "capital_color":
+			encode = zapcore.CapitalColorLevelEncoder
+		default:
+			o.errs = append(o.errs, fmt.Errorf("unknown level encoding %q", encoding))
+			return
+		}
+		WithEncoderConfig(func(config *zapcore.EncoderConfig) {
+			config.EncodeLevel = encode
+		})(o)
+		o.levelEncodingSet = true
+	}
+}
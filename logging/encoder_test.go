@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestEncoderOptions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	cases := []struct {
+		name string
+		opts []Option
+		want map[string]any
+	}{
+		{
+			name: "rfc3339nano timestamp",
+			opts: []Option{WithTimeFormat("rfc3339nano"), WithEncoderKeys(EncoderKeys{Time: "@timestamp", Message: "message"})},
+			want: map[string]any{"@timestamp": "2024-01-02T03:04:05.0000006Z", "message": "done", "level": "info", "elapsed": 1.5},
+		},
+		{
+			name: "epoch millis",
+			opts: []Option{WithTimeFormat("epoch_millis")},
+			want: map[string]any{"ts": float64(now.UnixNano()) / float64(time.Millisecond), "msg": "done", "level": "info", "elapsed": 1.5},
+		},
+		{
+			name: "custom layout",
+			opts: []Option{WithTimeFormat("2006-01-02 15:04:05")},
+			want: map[string]any{"ts": "2024-01-02 03:04:05", "msg": "done", "level": "info", "elapsed": 1.5},
+		},
+		{
+			name: "omitted key",
+			opts: []Option{WithEncoderKeys(EncoderKeys{Time: "-"}), WithLevelEncoding("capital"), WithDurationEncoding("string")},
+			want: map[string]any{"msg": "done", "level": "INFO", "elapsed": "1.5s"},
+		},
+		{
+			name: "unknown encodings",
+			opts: []Option{WithEncoderKeys(EncoderKeys{Time: "-"}), WithLevelEncoding("loud"), WithDurationEncoding("weeks")},
+			want: map[string]any{"msg": "done", "level": "info", "elapsed": 1.5},
+		},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &zaptest.Buffer{}
+			opts := append([]Option{WithWriteSyncer(buf), WithClock(fixedClock(now)), WithCaller(false)}, cs.opts...)
+			logger := NewStructuredLogger(opts...)
+			logger.Info("done", zap.Duration("elapsed", 1500*time.Millisecond))
+
+			var entry map[string]any
+			if err := json.Unmarshal([]byte(buf.Lines()[0]), &entry); err != nil {
+				t.Fatalf("expect JSON entry, but received %v", err)
+			}
+			if diff := cmp.Diff(cs.want, entry); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// encryptedSegmentIDSize is a size of the random ID of each segment.
+	encryptedSegmentIDSize = 16
+
+	// encryptedMaxChunk is a maximum size of a sealed chunk. Larger writes are split into chunks.
+	encryptedMaxChunk = 1 * megabyte
+)
+
+// encryptedMagic starts each segment of encrypted output. A segment is written by each EncryptedWriter,
+// so that a file appended by multiple processes is still readable.
+var encryptedMagic = []byte("LGE1")
+
+// ErrDecrypt is returned when encrypted logs are corrupted, tampered with, or decrypted with a wrong key.
+var ErrDecrypt = errors.New("logging: failed to decrypt logs")
+
+// EncryptedWriter is a zapcore.WriteSyncer which encrypts logs with AES-GCM before writing them to the wrapped writer.
+// Each write is sealed as a chunk framed with its length, so that entries written before a crash are readable.
+// The output starts with a segment header, and chunks are bound to the segment and their order,
+// so that reordered, removed, or truncated chunks are detected by NewDecryptReader, except chunks removed
+// from the end of a segment, because a segment has no end marker.
+// After a failed write, a new segment is started, so that chunks written later are still readable.
+type EncryptedWriter struct {
+	w    io.Writer
+	aead cipher.AEAD
+
+	// mu guards fields below.
+	mu sync.Mutex
+
+	// segment is a random ID of the segment, and seq is an index of the next chunk.
+	segment [encryptedSegmentIDSize]byte
+	seq     uint64
+
+	// headerWritten reports whether the segment header is written.
+	headerWritten bool
+}
+
+var _ zapcore.WriteSyncer = (*EncryptedWriter)(nil)
+
+// NewEncryptedWriter creates an EncryptedWriter writing to given writer with given AES key of 16, 24, or 32 bytes.
+func NewEncryptedWriter(w io.Writer, key []byte) (*EncryptedWriter, error) {
+	aead, err := newLogCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	e := &EncryptedWriter{w: w, aead: aead}
+	if _, err := rand.Read(e.segment[:]); err != nil {
+		return nil, fmt.Errorf("logging: failed to generate segment ID: %w", err)
+	}
+	return e, nil
+}
+
+// WithEncryptedFile adds a file which logs are written to encrypted with given AES key, in append mode.
+// Use NewDecryptReader to read the file. If the file can not be opened or the key is invalid,
+// the error is reported to the error output and the file is not used.
+func WithEncryptedFile(path string, key []byte) Option {
+	return func(o *options) {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		if err != nil {
+			o.errs = append(o.errs, fmt.Errorf("failed to open encrypted file: %w", err))
+			return
+		}
+		w, err := NewEncryptedWriter(f, key)
+		if err != nil {
+			_ = f.Close()
+			o.errs = append(o.errs, err)
+			return
+		}
+		o.writers = append(o.writers, w)
+		o.closers = append(o.closers, func(context.Context) error { return f.Close() })
+	}
+}
+
+// Write seals given bytes as chunks and writes them.
+func (e *EncryptedWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var frame []byte
+	if !e.headerWritten {
+		frame = append(append(frame, encryptedMagic...), e.segment[:]...)
+	}
+	seq := e.seq
+	limit := encryptedMaxChunk - e.aead.NonceSize() - e.aead.Overhead()
+	for rest := p; len(rest) > 0; seq++ {
+		chunk := rest[:min(len(rest), limit)]
+		rest = rest[len(chunk):]
+		sealed, err := e.seal(chunk, seq)
+		if err != nil {
+			return 0, err
+		}
+		frame = binary.BigEndian.AppendUint32(frame, uint32(len(sealed)))
+		frame = append(frame, sealed...)
+	}
+	if _, err := e.w.Write(frame); err != nil {
+		// The reader can not tell how much of the frame is written, so chunks written later start a new segment.
+		if _, rerr := rand.Read(e.segment[:]); rerr != nil {
+			err = errors.Join(err, fmt.Errorf("logging: failed to generate segment ID: %w", rerr))
+		}
+		e.seq = 0
+		e.headerWritten = false
+		return 0, err
+	}
+	e.seq = seq
+	e.headerWritten = true
+	return len(p), nil
+}
+
+// Sync syncs the wrapped writer if it can.
+func (e *EncryptedWriter) Sync() error {
+	if s, ok := e.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// seal encrypts given chunk as the chunk of given index in the segment. e.mu must be held.
+func (e *EncryptedWriter) seal(chunk []byte, seq uint64) ([]byte, error) {
+	nonce := make([]byte, e.aead.NonceSize(), e.aead.NonceSize()+len(chunk)+e.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("logging: failed to generate nonce: %w", err)
+	}
+	return e.aead.Seal(nonce, nonce, chunk, chunkAAD(e.segment[:], seq)), nil
+}
+
+// NewDecryptReader returns a reader of logs written by EncryptedWriter with given key.
+// Reading fails with ErrDecrypt if chunks are corrupted, tampered with, or the key is wrong.
+func NewDecryptReader(r io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newLogCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{r: bufio.NewReader(r), aead: aead}, nil
+}
+
+// decryptReader reads chunks and returns their plaintext.
+type decryptReader struct {
+	r    *bufio.Reader
+	aead cipher.AEAD
+
+	segment []byte
+	seq     uint64
+
+	// pending is plaintext not returned yet.
+	pending []byte
+}
+
+// Read returns plaintext of chunks in order.
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.pending) == 0 {
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	return n, nil
+}
+
+// next reads the next chunk, and the segment header before it if any.
+func (d *decryptReader) next() error {
+	if head, err := d.r.Peek(len(encryptedMagic)); err == nil && bytes.Equal(head, encryptedMagic) {
+		header := make([]byte, len(encryptedMagic)+encryptedSegmentIDSize)
+		if _, err := io.ReadFull(d.r, header); err != nil {
+			return fmt.Errorf("%w: truncated segment header", ErrDecrypt)
+		}
+		d.segment = header[len(encryptedMagic):]
+		d.seq = 0
+	} else if err == io.EOF && len(head) == 0 {
+		return io.EOF
+	}
+	if d.segment == nil {
+		return fmt.Errorf("%w: no segment header", ErrDecrypt)
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(d.r, size[:]); err != nil {
+		return fmt.Errorf("%w: truncated chunk", ErrDecrypt)
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n < uint32(d.aead.NonceSize()+d.aead.Overhead()) || n > encryptedMaxChunk {
+		return fmt.Errorf("%w: invalid chunk size %d", ErrDecrypt, n)
+	}
+	sealed := make([]byte, n)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		return fmt.Errorf("%w: truncated chunk", ErrDecrypt)
+	}
+	nonce, ciphertext := sealed[:d.aead.NonceSize()], sealed[d.aead.NonceSize():]
+	plaintext, err := d.aead.Open(nil, nonce, ciphertext, chunkAAD(d.segment, d.seq))
+	if err != nil {
+		return fmt.Errorf("%w: chunk %d of segment %x", ErrDecrypt, d.seq, d.segment)
+	}
+	d.seq++
+	d.pending = plaintext
+	return nil
+}
+
+// newLogCipher creates AES-GCM with given key.
+func newLogCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("logging: invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkAAD returns additional data binding a chunk to its segment and index.
+func chunkAAD(segment []byte, seq uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte(nil), segment...), seq)
+}
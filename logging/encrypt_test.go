@@ -0,0 +1,204 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEncryptedWriter(t *testing.T) {
+	t.Parallel()
+
+	key := bytes.Repeat([]byte{1}, 32)
+	var buf bytes.Buffer
+
+	// Two writers append segments to the same output.
+	for _, lines := range [][]string{{"first\n", "second\n"}, {"third\n"}} {
+		w, err := NewEncryptedWriter(&buf, key)
+		if err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+		for _, line := range lines {
+			if _, err := w.Write([]byte(line)); err != nil {
+				t.Fatalf("expect no error, but received %v", err)
+			}
+		}
+	}
+	if bytes.Contains(buf.Bytes(), []byte("first")) {
+		t.Error("expect encrypted output, but received plaintext")
+	}
+
+	r, err := NewDecryptReader(bytes.NewReader(buf.Bytes()), key)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff("first\nsecond\nthird\n", string(got)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+// failingWriter is an io.Writer which fails the write of given index, and writes others to the buffer.
+type failingWriter struct {
+	bytes.Buffer
+	fail   int
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes == w.fail {
+		return 0, errors.New("write failed")
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestEncryptedWriterFailedWrite(t *testing.T) {
+	t.Parallel()
+
+	key := bytes.Repeat([]byte{3}, 32)
+	// The failed write is the one of the segment header, or the one after it.
+	for _, fail := range []int{1, 2} {
+		out := &failingWriter{fail: fail}
+		w, err := NewEncryptedWriter(out, key)
+		if err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+		var want string
+		for _, line := range []string{"first\n", "second\n", "third\n"} {
+			if _, err := w.Write([]byte(line)); err == nil {
+				want += line
+			}
+		}
+
+		r, err := NewDecryptReader(bytes.NewReader(out.Bytes()), key)
+		if err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+		if diff := cmp.Diff(want, string(got)); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+}
+
+func TestEncryptedWriterLargeWrite(t *testing.T) {
+	t.Parallel()
+
+	key := bytes.Repeat([]byte{2}, 16)
+	var buf bytes.Buffer
+	w, err := NewEncryptedWriter(&buf, key)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	want := strings.Repeat("x", 3*encryptedMaxChunk)
+	if _, err := w.Write([]byte(want)); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	r, err := NewDecryptReader(&buf, key)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if len(got) != len(want) {
+		t.Errorf("expect %d bytes, but received %d", len(want), len(got))
+	}
+}
+
+func TestDecryptReaderErrors(t *testing.T) {
+	t.Parallel()
+
+	key := bytes.Repeat([]byte{3}, 32)
+	var buf bytes.Buffer
+	w, err := NewEncryptedWriter(&buf, key)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	_, _ = w.Write([]byte("first\n"))
+	_, _ = w.Write([]byte("second\n"))
+	encrypted := buf.Bytes()
+	header := len(encryptedMagic) + encryptedSegmentIDSize
+
+	tampered := append([]byte(nil), encrypted...)
+	tampered[len(tampered)-1] ^= 1
+
+	// The segment header followed by the second chunk only.
+	firstSize := 4 + int(binary.BigEndian.Uint32(encrypted[header:]))
+	removed := append(append([]byte(nil), encrypted[:header]...), encrypted[header+firstSize:]...)
+
+	tests := map[string]struct {
+		data []byte
+		key  []byte
+	}{
+		"tampered":  {data: tampered, key: key},
+		"wrong key": {data: encrypted, key: bytes.Repeat([]byte{4}, 32)},
+		"truncated": {data: encrypted[:len(encrypted)-3], key: key},
+		"removed":   {data: removed, key: key},
+		"no header": {data: encrypted[header:], key: key},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := NewDecryptReader(bytes.NewReader(tt.data), tt.key)
+			if err != nil {
+				t.Fatalf("expect no error, but received %v", err)
+			}
+			if _, err := io.ReadAll(r); !errors.Is(err, ErrDecrypt) {
+				t.Errorf("expect %v, but received %v", ErrDecrypt, err)
+			}
+		})
+	}
+}
+
+func TestNewEncryptedWriterInvalidKey(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewEncryptedWriter(io.Discard, []byte("short")); err == nil {
+		t.Error("expect an error, but received nil")
+	}
+}
+
+func TestWithEncryptedFile(t *testing.T) {
+	t.Parallel()
+
+	key := bytes.Repeat([]byte{5}, 32)
+	path := filepath.Join(t.TempDir(), "app.log.enc")
+	logger := NewLogger(WithEncryptedFile(path, key))
+	logger.Info("encrypted")
+	_ = logger.Sync()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	defer f.Close()
+	r, err := NewDecryptReader(f, key)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if !strings.Contains(string(got), `"msg":"encrypted"`) {
+		t.Errorf("expect the entry, but received %q", got)
+	}
+}
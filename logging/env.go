@@ -0,0 +1,171 @@
+package logging
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aqyuki/util/config"
+)
+
+// envPrefix is a prefix of environment variables read by envOptions.
+const envPrefix = "LOG_"
+
+// envOptions returns options from environment variables.
+//
+//   - LOG_MODE: "develop" switches logger mode to develop mode, and other values such as "production" select
+//     production mode. If empty, develop mode is selected when stdout is a terminal.
+//   - LOG_LEVEL: minimum level such as "debug" or "warn", optionally followed by component levels
+//     such as "info,database=debug,http=warn".
+//   - LOG_FORMAT: name of encoder such as "json", "console", or "logfmt". Unknown names are ignored.
+//   - LOG_OUTPUT: comma separated list of "stdout", "stderr", file paths, or network addresses
+//     such as "tcp://host:port", "udp://host:port", or "unix:///path/to/socket".
+//   - LOG_SAMPLING: "initial,thereafter" such as "100,100", or "off" to disable sampling.
+//   - LOG_FIELDS: comma separated list of "key=value" fields added to every entry,
+//     such as "service=payments,env=prod". Values are written as strings.
+//   - LOG_GELF_ADDR: address of Graylog such as "udp://graylog:12201" or "tcp://graylog:12201".
+//     Entries are additionally sent in GELF. UDP is used if the scheme is omitted.
+//   - NO_COLOR, FORCE_COLOR: disable or enable colored levels of console encoding. See WithColor.
+//   - JOURNAL_STREAM: set by systemd. If the journal is available and LOG_OUTPUT is empty,
+//     entries are written to the journal instead of stdout.
+//
+// Variables with envPrefix are loaded via config.Env, as same as other configuration sources.
+func envOptions() []Option {
+	// Loading environment variables never fails.
+	c, _ := config.New(config.Env(envPrefix))
+	return envConfigOptions(c)
+}
+
+// envConfigOptions returns options from given configuration loaded via config.Env with envPrefix.
+func envConfigOptions(c *config.Config) []Option {
+	// develop is a flag variable to switch logger mode between develop mode or not develop mode.
+	// default is develop mode if stdout is a terminal, and not develop mode otherwise.
+	develop := developMode(c.String("mode"))
+
+	// level is a log level variable to set log level.
+	level, components := parseLevelSpec(c.String("level"))
+
+	opts := []Option{WithDevelopment(develop), WithLevel(level)}
+	if len(components) > 0 {
+		opts = append(opts, WithComponentLevels(components))
+	}
+
+	if format := strings.ToLower(strings.TrimSpace(c.String("format"))); knownEncoding(format) {
+		opts = append(opts, WithEncoding(format))
+	}
+
+	if paths := splitList(c.String("output")); len(paths) > 0 {
+		opts = append(opts, WithOutputPaths(paths...))
+	} else if journalAvailable() {
+		opts = append(opts, WithJournald(), withoutDefaultOutput())
+	}
+
+	if opt, ok := parseSampling(c.String("sampling")); ok {
+		opts = append(opts, opt)
+	}
+
+	if fields := parseFields(c.String("fields")); len(fields) > 0 {
+		opts = append(opts, WithInitialFields(fields))
+	}
+
+	if gelf, ok := parseGELFAddr(c.String("gelf_addr")); ok {
+		opts = append(opts, WithGELF(gelf))
+	}
+
+	return opts
+}
+
+// stdoutIsTerminal reports whether stdout is a terminal. It is replaced in tests.
+var stdoutIsTerminal = func() bool {
+	return isTerminal(os.Stdout)
+}
+
+// developMode reports whether given value of LOG_MODE selects develop mode.
+// If the value is empty, develop mode is selected when stdout is a terminal.
+func developMode(mode string) bool {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "develop":
+		return true
+	case "":
+		return stdoutIsTerminal()
+	default:
+		return false
+	}
+}
+
+// parseSampling parses given sampling specification such as "100,100" or "off".
+// If the specification is empty or invalid, it will return false as second value.
+func parseSampling(spec string) (Option, bool) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	switch spec {
+	case "":
+		return nil, false
+	case "off", "none", "false", "0":
+		return WithoutSampling(), true
+	}
+
+	first, second, ok := strings.Cut(spec, ",")
+	if !ok {
+		return nil, false
+	}
+	initial, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil || initial < 0 {
+		return nil, false
+	}
+	thereafter, err := strconv.Atoi(strings.TrimSpace(second))
+	if err != nil || thereafter < 0 {
+		return nil, false
+	}
+	return WithSampling(initial, thereafter), true
+}
+
+// splitList splits given comma separated list and trims each element.
+// Empty elements are removed.
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// parseFields parses given list of fields such as "service=payments,env=prod".
+// Elements without "=" or with an empty key are ignored.
+func parseFields(spec string) map[string]any {
+	var fields map[string]any
+	for _, item := range splitList(spec) {
+		key, value, ok := strings.Cut(item, "=")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]any)
+		}
+		fields[key] = strings.TrimSpace(value)
+	}
+	return fields
+}
+
+// parseLevelSpec parses given level specification such as "info,database=debug,http=warn".
+// It returns the default level and a map of component names to their levels.
+func parseLevelSpec(spec string) (string, map[string]string) {
+	var level string
+	var components map[string]string
+	for _, item := range splitList(spec) {
+		name, lvl, ok := strings.Cut(item, "=")
+		if !ok {
+			level = item
+			continue
+		}
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if components == nil {
+			components = make(map[string]string)
+		}
+		components[name] = strings.TrimSpace(lvl)
+	}
+	return level, components
+}
@@ -0,0 +1,179 @@
+package logging
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+func TestEnvOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv("LOG_MODE", "develop")
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("LOG_FORMAT", "JSON")
+	t.Setenv("LOG_OUTPUT", " "+path+" , ")
+
+	o := newOptions(envOptions()...)
+	if !o.develop {
+		t.Error("expect develop mode, but received production mode")
+	}
+	if diff := cmp.Diff("json", o.encoding); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{path}, o.outputPaths); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	logger := NewLoggerFromEnv()
+	logger.Warn("from env")
+	_ = logger.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("from env", entries[0]["M"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestEnvOptionsUnknownFormat(t *testing.T) {
+	t.Setenv("LOG_FORMAT", "unknown")
+
+	if o := newOptions(envOptions()...); o.encoding != "" {
+		t.Errorf("expect unknown format ignored, but received %q", o.encoding)
+	}
+}
+
+func TestDevelopMode(t *testing.T) {
+	original := stdoutIsTerminal
+	t.Cleanup(func() {
+		stdoutIsTerminal = original
+	})
+
+	cases := []struct {
+		mode     string
+		terminal bool
+		want     bool
+	}{
+		{mode: "develop", terminal: false, want: true},
+		{mode: " Develop ", terminal: false, want: true},
+		{mode: "production", terminal: true, want: false},
+		{mode: "", terminal: true, want: true},
+		{mode: "", terminal: false, want: false},
+	}
+	for _, cs := range cases {
+		terminal := cs.terminal
+		stdoutIsTerminal = func() bool { return terminal }
+		if diff := cmp.Diff(cs.want, developMode(cs.mode)); diff != "" {
+			t.Errorf("%q (terminal=%v): (-want, +got)\n%s", cs.mode, cs.terminal, diff)
+		}
+	}
+}
+
+func TestEnvOptionsFields(t *testing.T) {
+	t.Setenv("LOG_FIELDS", "service=payments, env=prod,invalid,=empty,region=us-east-1")
+
+	o := newOptions(envOptions()...)
+	want := map[string]any{"service": "payments", "env": "prod", "region": "us-east-1"}
+	if diff := cmp.Diff(want, o.initialFields); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		input string
+		want  []string
+	}{
+		{input: "", want: nil},
+		{input: "stdout", want: []string{"stdout"}},
+		{input: " stdout, ,/var/log/app.log ", want: []string{"stdout", "/var/log/app.log"}},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.input, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(cs.want, splitList(cs.input)); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseLevelSpec(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		input      string
+		level      string
+		components map[string]string
+	}{
+		{input: "", level: "", components: nil},
+		{input: "debug", level: "debug", components: nil},
+		{input: "info,database=debug, http = warn", level: "info", components: map[string]string{"database": "debug", "http": "warn"}},
+		{input: "database=debug,=warn", level: "", components: map[string]string{"database": "debug"}},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.input, func(t *testing.T) {
+			t.Parallel()
+
+			level, components := parseLevelSpec(cs.input)
+			if diff := cmp.Diff(cs.level, level); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(cs.components, components); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseSampling(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		input    string
+		ok       bool
+		sampling *zap.SamplingConfig
+	}{
+		{input: "", ok: false},
+		{input: "off", ok: true, sampling: nil},
+		{input: "100,10", ok: true, sampling: &zap.SamplingConfig{Initial: 100, Thereafter: 10}},
+		{input: " 5 , 0 ", ok: true, sampling: &zap.SamplingConfig{Initial: 5, Thereafter: 0}},
+		{input: "100", ok: false},
+		{input: "a,b", ok: false},
+		{input: "-1,1", ok: false},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.input, func(t *testing.T) {
+			t.Parallel()
+
+			opt, ok := parseSampling(cs.input)
+			if diff := cmp.Diff(cs.ok, ok); diff != "" {
+				t.Fatalf("(-want, +got)\n%s", diff)
+			}
+			if !ok {
+				return
+			}
+
+			o := newOptions(opt)
+			if !o.samplingSet {
+				t.Error("expect sampling set, but not set")
+			}
+			if diff := cmp.Diff(cs.sampling, o.sampling); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
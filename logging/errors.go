@@ -0,0 +1,183 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// multiError is implemented by errors which wrap multiple errors, such as errors returned by errors.Join.
+type multiError interface {
+	Unwrap() []error
+}
+
+// stackTracer is implemented by errors which carry a stack trace as a string.
+type stackTracer interface {
+	Stack() string
+}
+
+// omittedCounter is implemented by multi errors which count errors omitted beyond a limit, such as *errorsx.MultiError.
+type omittedCounter interface {
+	Omitted() int
+}
+
+// fieldCarrier is implemented by errors which carry structured fields, such as errors of errorsx.
+type fieldCarrier interface {
+	Fields() map[string]any
+}
+
+// Err returns a field which describes given error under "error" key, as an object of
+// "message", "type" formatted with %T, "chain" of every error in the unwrap chain, "stack_trace"
+// if any error in the chain carries a stack trace, and "fields" if any error in the chain carries fields
+// via a Fields() map[string]any method, such as errors of errorsx. If err is nil, the field is skipped.
+// Errors implementing zapcore.ObjectMarshaler, such as *config.FieldError, additionally encode themselves as "details".
+//
+// If the chain ends with an error which wraps multiple errors, such as errors returned by errors.Join or multierr,
+// each of them is described in the same way as an element of "errors" array, in addition to the flattened message.
+// If it omits errors beyond a limit via an Omitted() int method, such as *errorsx.MultiError, they are counted as "errors_omitted".
+//
+// Stack traces are taken from errors with a Stack() string method,
+// or formatted with %+v from errors implementing fmt.Formatter, such as errors of github.com/pkg/errors.
+func Err(err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object("error", errorObject{err: err})
+}
+
+// errorObject is a zapcore.ObjectMarshaler which encodes an error with its unwrap chain.
+type errorObject struct {
+	err error
+}
+
+// MarshalLogObject encodes the error into given encoder.
+func (e errorObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("message", e.err.Error())
+	enc.AddString("type", fmt.Sprintf("%T", e.err))
+	if details, ok := e.err.(zapcore.ObjectMarshaler); ok {
+		if err := enc.AddObject("details", details); err != nil {
+			return err
+		}
+	}
+
+	chain := unwrapChain(e.err)
+	if err := enc.AddArray("chain", errorChain(chain)); err != nil {
+		return err
+	}
+	if stack, ok := errorStack(chain); ok {
+		enc.AddString("stack_trace", stack)
+	}
+	if fields := errorFields(chain); len(fields) > 0 {
+		if err := enc.AddObject("fields", fields); err != nil {
+			return err
+		}
+	}
+	if multi, ok := chain[len(chain)-1].(multiError); ok {
+		if counter, ok := multi.(omittedCounter); ok && counter.Omitted() > 0 {
+			enc.AddInt("errors_omitted", counter.Omitted())
+		}
+		return enc.AddArray("errors", errorObjects(multi.Unwrap()))
+	}
+	return nil
+}
+
+// errorObjects is a zapcore.ArrayMarshaler which encodes errors wrapped by a multi error.
+type errorObjects []error
+
+// MarshalLogArray encodes each error as an errorObject. Nil errors are skipped.
+func (o errorObjects) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, err := range o {
+		if err == nil {
+			continue
+		}
+		if err := enc.AppendObject(errorObject{err: err}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorChain is a zapcore.ArrayMarshaler which encodes errors in an unwrap chain.
+type errorChain []error
+
+// MarshalLogArray encodes each error as an object of its message and type.
+func (c errorChain) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, err := range c {
+		err := err
+		if err := enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddString("message", err.Error())
+			enc.AddString("type", fmt.Sprintf("%T", err))
+			return nil
+		})); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unwrapChain returns given error followed by errors returned by errors.Unwrap in order.
+func unwrapChain(err error) []error {
+	var chain []error
+	for ; err != nil; err = errors.Unwrap(err) {
+		chain = append(chain, err)
+	}
+	return chain
+}
+
+// errorStack returns the stack trace carried by the innermost error in given chain,
+// because it is the closest to where the error occurred.
+// Errors wrapping multiple errors are skipped, because their verbose format joins the collected errors
+// rather than carrying a stack trace.
+func errorStack(chain []error) (string, bool) {
+	for i := len(chain) - 1; i >= 0; i-- {
+		switch err := chain[i].(type) {
+		case stackTracer:
+			if stack := err.Stack(); stack != "" {
+				return stack, true
+			}
+		case multiError:
+		case fmt.Formatter:
+			if verbose := fmt.Sprintf("%+v", err); verbose != chain[i].Error() {
+				return verbose, true
+			}
+		}
+	}
+	return "", false
+}
+
+// errorFieldMap is a zapcore.ObjectMarshaler which encodes fields carried by errors.
+type errorFieldMap map[string]any
+
+// MarshalLogObject encodes each field in order of keys.
+func (m errorFieldMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		zap.Any(k, m[k]).AddTo(enc)
+	}
+	return nil
+}
+
+// errorFields returns fields carried by errors in given chain, where fields of outer errors win.
+func errorFields(chain []error) errorFieldMap {
+	var fields errorFieldMap
+	for i := len(chain) - 1; i >= 0; i-- {
+		carrier, ok := chain[i].(fieldCarrier)
+		if !ok {
+			continue
+		}
+		for k, v := range carrier.Fields() {
+			if fields == nil {
+				fields = make(errorFieldMap)
+			}
+			fields[k] = v
+		}
+	}
+	return fields
+}
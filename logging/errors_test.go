@@ -0,0 +1,238 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aqyuki/util/config"
+	"github.com/aqyuki/util/errorsx"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+)
+
+// stackError is an error which carries a fixed stack trace.
+type stackError struct {
+	msg   string
+	stack string
+}
+
+func (e *stackError) Error() string { return e.msg }
+
+func (e *stackError) Stack() string { return e.stack }
+
+func TestErr(t *testing.T) {
+	t.Parallel()
+
+	cause := &stackError{msg: "connection refused", stack: "main.dial\n\tmain.go:10"}
+	err := fmt.Errorf("query failed: %w", cause)
+
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf))
+	logger.Errorw("failed", Err(err))
+	logger.Infow("no error", Err(nil))
+
+	lines := buf.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("expect 2 entries, but received %d", len(lines))
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	want := map[string]any{
+		"message": "query failed: connection refused",
+		"type":    "*fmt.wrapError",
+		"chain": []any{
+			map[string]any{"message": "query failed: connection refused", "type": "*fmt.wrapError"},
+			map[string]any{"message": "connection refused", "type": "*logging.stackError"},
+		},
+		"stack_trace": "main.dial\n\tmain.go:10",
+	}
+	if diff := cmp.Diff(want, entry["error"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	var skipped map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &skipped); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	if _, ok := skipped["error"]; ok {
+		t.Errorf("expect no error key, but received %s", lines[1])
+	}
+}
+
+func TestErrWithoutStack(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf))
+	logger.Errorw("failed", Err(errors.New("boom")))
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(buf.Lines()[0]), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	want := map[string]any{
+		"message": "boom",
+		"type":    "*errors.errorString",
+		"chain":   []any{map[string]any{"message": "boom", "type": "*errors.errorString"}},
+	}
+	if diff := cmp.Diff(want, entry["error"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestErrWithMultiError(t *testing.T) {
+	t.Parallel()
+
+	first := errors.New("first")
+	second := &stackError{msg: "second", stack: "main.run\n\tmain.go:20"}
+	err := fmt.Errorf("batch failed: %w", errors.Join(first, second))
+
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf))
+	logger.Errorw("failed", Err(err))
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(buf.Lines()[0]), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	want := map[string]any{
+		"message": "batch failed: first\nsecond",
+		"type":    "*fmt.wrapError",
+		"chain": []any{
+			map[string]any{"message": "batch failed: first\nsecond", "type": "*fmt.wrapError"},
+			map[string]any{"message": "first\nsecond", "type": "*errors.joinError"},
+		},
+		"errors": []any{
+			map[string]any{
+				"message": "first",
+				"type":    "*errors.errorString",
+				"chain":   []any{map[string]any{"message": "first", "type": "*errors.errorString"}},
+			},
+			map[string]any{
+				"message":     "second",
+				"type":        "*logging.stackError",
+				"chain":       []any{map[string]any{"message": "second", "type": "*logging.stackError"}},
+				"stack_trace": "main.run\n\tmain.go:20",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, entry["error"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestErrWithValidationError(t *testing.T) {
+	t.Parallel()
+
+	var server struct {
+		Port int `json:"port" validate:"min=1"`
+	}
+	err := config.Validate(server)
+
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf))
+	logger.Errorw("invalid config", Err(err))
+
+	var entry struct {
+		Error struct {
+			Errors []map[string]any `json:"errors"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(buf.Lines()[0]), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	if len(entry.Error.Errors) != 1 {
+		t.Fatalf("expect 1 error, but received %v", entry.Error.Errors)
+	}
+	want := map[string]any{"field": "port", "rule": "min", "param": "1"}
+	if diff := cmp.Diff(want, entry.Error.Errors[0]["details"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestErrWithErrorsx(t *testing.T) {
+	t.Parallel()
+
+	cause := errorsx.WithFields(errorsx.New("connection refused"), "host", "db-1")
+	err := errorsx.WithFields(fmt.Errorf("query failed: %w", cause), "user_id", 42)
+
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf))
+	logger.Errorw("failed", Err(err))
+
+	var entry struct {
+		Error struct {
+			Fields     map[string]any `json:"fields"`
+			StackTrace string         `json:"stack_trace"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(buf.Lines()[0]), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"host": "db-1", "user_id": float64(42)}, entry.Error.Fields); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if !strings.HasPrefix(entry.Error.StackTrace, "github.com/aqyuki/util/logging.TestErrWithErrorsx\n\t") {
+		t.Errorf("expect the stack trace of errorsx.New, but received %q", entry.Error.StackTrace)
+	}
+}
+
+func TestErrWithErrorsxJoin(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf))
+	logger.Errorw("failed", Err(errorsx.Join(errors.New("a"), errors.New("b"))))
+
+	var entry struct {
+		Error map[string]any `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(buf.Lines()[0]), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	if stack, ok := entry.Error["stack_trace"]; ok {
+		t.Errorf("expect no stack_trace, but received %q", stack)
+	}
+}
+
+func TestErrWithMultiErrorLimit(t *testing.T) {
+	t.Parallel()
+
+	errs := errorsx.NewMultiError(1)
+	errs.Append(errorsx.WithFields(errorsx.New("first"), "item", 1), errors.New("second"), errors.New("third"))
+
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf))
+	logger.Errorw("batch failed", Err(errs.Err()))
+
+	var entry struct {
+		Error struct {
+			Message string           `json:"message"`
+			Omitted int              `json:"errors_omitted"`
+			Errors  []map[string]any `json:"errors"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(buf.Lines()[0]), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	if diff := cmp.Diff("first\nand 2 more", entry.Error.Message); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(2, entry.Error.Omitted); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if len(entry.Error.Errors) != 1 {
+		t.Fatalf("expect 1 error, but received %v", entry.Error.Errors)
+	}
+	if diff := cmp.Diff(map[string]any{"item": float64(1)}, entry.Error.Errors[0]["fields"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if _, ok := entry.Error.Errors[0]["stack_trace"]; !ok {
+		t.Error("expect stack_trace of the collected error, but not found")
+	}
+}
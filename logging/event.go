@@ -0,0 +1,156 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	// eventNamePattern matches event names, which are dot-separated snake_case words such as "user.signup".
+	eventNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)+$`)
+
+	// eventKeyPattern matches keys of event fields, which are snake_case words such as "plan_id".
+	eventKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// ErrInvalidEvent is returned by (*EventBuilder).Emit when the name or a key of the event is invalid.
+var ErrInvalidEvent = errors.New("logging: invalid event")
+
+// eventSamplers holds *eventSampler of each event name set by SetEventSampling.
+var eventSamplers sync.Map
+
+// eventSampler emits one in every `every` events.
+type eventSampler struct {
+	every uint64
+	count atomic.Uint64
+}
+
+// SetEventSampling makes only one in every given number of events with given name emitted.
+// Emitted events have an "event_sample_rate" field with the number, so that counts can be scaled.
+// Zero or one disables sampling of the event.
+func SetEventSampling(name string, every uint64) {
+	if every <= 1 {
+		eventSamplers.Delete(name)
+		return
+	}
+	eventSamplers.Store(name, &eventSampler{every: every})
+}
+
+// EventBuilder builds a domain event, distinct from free-form log lines. Create it via Event.
+// It is not safe for concurrent use, and must not be used after Emit.
+type EventBuilder struct {
+	ctx    context.Context
+	name   string
+	level  zapcore.Level
+	fields []zap.Field
+
+	// err is the first error found while building the event.
+	err error
+}
+
+// Event starts building a domain event with given name, such as "user.signup".
+// The name must be dot-separated snake_case words, and keys of fields must be snake_case words.
+// The event is logged at info level with the name as the message and an "event" field, when Emit is called.
+//
+//	logging.Event(ctx, "user.signup").Str("plan", plan).Int("seats", seats).Emit()
+func Event(ctx context.Context, name string) *EventBuilder {
+	b := &EventBuilder{ctx: ctx, name: name, level: zapcore.InfoLevel}
+	if !eventNamePattern.MatchString(name) {
+		b.err = fmt.Errorf("%w: name %q is not dot-separated snake_case words", ErrInvalidEvent, name)
+	}
+	return b
+}
+
+// Level changes the level which the event is logged at.
+func (b *EventBuilder) Level(level zapcore.Level) *EventBuilder {
+	b.level = level
+	return b
+}
+
+// Str adds a string field.
+func (b *EventBuilder) Str(key, value string) *EventBuilder {
+	return b.add(zap.String(key, value))
+}
+
+// Int adds an int field.
+func (b *EventBuilder) Int(key string, value int) *EventBuilder {
+	return b.add(zap.Int(key, value))
+}
+
+// Int64 adds an int64 field.
+func (b *EventBuilder) Int64(key string, value int64) *EventBuilder {
+	return b.add(zap.Int64(key, value))
+}
+
+// Float adds a float64 field.
+func (b *EventBuilder) Float(key string, value float64) *EventBuilder {
+	return b.add(zap.Float64(key, value))
+}
+
+// Bool adds a bool field.
+func (b *EventBuilder) Bool(key string, value bool) *EventBuilder {
+	return b.add(zap.Bool(key, value))
+}
+
+// Dur adds a duration field.
+func (b *EventBuilder) Dur(key string, value time.Duration) *EventBuilder {
+	return b.add(zap.Duration(key, value))
+}
+
+// Time adds a time field.
+func (b *EventBuilder) Time(key string, value time.Time) *EventBuilder {
+	return b.add(zap.Time(key, value))
+}
+
+// Any adds a field of any value, as zap.Any.
+func (b *EventBuilder) Any(key string, value any) *EventBuilder {
+	return b.add(zap.Any(key, value))
+}
+
+// Err adds an error field as Err. It does nothing if given error is nil.
+func (b *EventBuilder) Err(err error) *EventBuilder {
+	if err == nil {
+		return b
+	}
+	return b.add(Err(err))
+}
+
+// Emit logs the event with the logger from the context, unless it is sampled out.
+// If the name or a key is invalid, nothing is logged and an error wrapping ErrInvalidEvent is returned.
+func (b *EventBuilder) Emit() error {
+	if b.err != nil {
+		return b.err
+	}
+
+	fields := append([]zap.Field{zap.String("event", b.name)}, b.fields...)
+	if v, ok := eventSamplers.Load(b.name); ok {
+		s := v.(*eventSampler)
+		if s.count.Add(1)%s.every != 1 {
+			return nil
+		}
+		fields = append(fields, zap.Uint64("event_sample_rate", s.every))
+	}
+
+	logger := StructuredFromContext(b.ctx).WithOptions(zap.AddCallerSkip(1))
+	if ce := logger.Check(b.level, b.name); ce != nil {
+		ce.Write(fields...)
+	}
+	return nil
+}
+
+// add adds given field after validating its key.
+func (b *EventBuilder) add(field zap.Field) *EventBuilder {
+	if b.err == nil && (!eventKeyPattern.MatchString(field.Key) || field.Key == "event" || field.Key == "event_sample_rate") {
+		b.err = fmt.Errorf("%w: key %q of %s is reserved or not snake_case", ErrInvalidEvent, field.Key, b.name)
+	}
+	b.fields = append(b.fields, field)
+	return b
+}
@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestEvent(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	ctx := WithStructuredLogger(context.Background(), zap.New(core))
+
+	err := Event(ctx, "user.signup").
+		Str("plan", "pro").
+		Int("seats", 3).
+		Bool("trial", true).
+		Dur("elapsed", time.Second).
+		Err(nil).
+		Emit()
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("user.signup", entries[0].Message); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	want := map[string]any{"event": "user.signup", "plan": "pro", "seats": int64(3), "trial": true, "elapsed": time.Second}
+	if diff := cmp.Diff(want, entries[0].ContextMap()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestEventInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]func(ctx context.Context) error{
+		"no dot":       func(ctx context.Context) error { return Event(ctx, "signup").Emit() },
+		"upper case":   func(ctx context.Context) error { return Event(ctx, "User.Signup").Emit() },
+		"empty word":   func(ctx context.Context) error { return Event(ctx, "user..signup").Emit() },
+		"camel key":    func(ctx context.Context) error { return Event(ctx, "user.signup").Str("planID", "p").Emit() },
+		"reserved key": func(ctx context.Context) error { return Event(ctx, "user.signup").Str("event", "x").Emit() },
+	}
+	for name, emit := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			core, logs := observer.New(zap.DebugLevel)
+			ctx := WithStructuredLogger(context.Background(), zap.New(core))
+			if err := emit(ctx); !errors.Is(err, ErrInvalidEvent) {
+				t.Errorf("expect %v, but received %v", ErrInvalidEvent, err)
+			}
+			if logs.Len() != 0 {
+				t.Errorf("expect no entry, but received %d", logs.Len())
+			}
+		})
+	}
+}
+
+func TestSetEventSampling(t *testing.T) {
+	t.Parallel()
+
+	// The event name is unique to this test, because sampling is global.
+	SetEventSampling("test.sampled", 3)
+	defer SetEventSampling("test.sampled", 0)
+
+	core, logs := observer.New(zap.DebugLevel)
+	ctx := WithStructuredLogger(context.Background(), zap.New(core))
+	for i := 0; i < 7; i++ {
+		if err := Event(ctx, "test.sampled").Int("index", i).Emit(); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+	}
+
+	var got []any
+	for _, entry := range logs.AllUntimed() {
+		fields := entry.ContextMap()
+		if diff := cmp.Diff(uint64(3), fields["event_sample_rate"]); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+		got = append(got, fields["index"])
+	}
+	if diff := cmp.Diff([]any{int64(0), int64(3), int64(6)}, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
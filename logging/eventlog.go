@@ -0,0 +1,177 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultEventLogBaseID is a default event ID of debug entries.
+const defaultEventLogBaseID = 1000
+
+// EventLogConfig is a configuration of Windows Event Log destination.
+type EventLogConfig struct {
+	// Source is an event source name registered in the Application log, for example by
+	// eventlog.InstallAsEventCreate of golang.org/x/sys/windows/svc/eventlog during installation.
+	// If empty, the name of the executable without its extension is used.
+	Source string
+
+	// BaseEventID is an event ID of debug entries, and IDs of higher levels follow it,
+	// such as 1001 for info, 1002 for warn, and 1003 for error when it is 1000. If zero, 1000 is used.
+	BaseEventID uint32
+}
+
+// WithEventLog writes entries to Windows Event Log, in addition to the logger's own output.
+// Entries are filtered at the logger's level and component levels.
+// On other platforms, writing entries fails and errors are reported to the error output.
+func WithEventLog(config EventLogConfig) Option {
+	return func(o *options) {
+		o.leveledCores = append(o.leveledCores, NewEventLogCore(config))
+	}
+}
+
+// eventLogger writes events to an event source. It is implemented by *eventlog.Log of golang.org/x/sys.
+type eventLogger interface {
+	Info(eid uint32, msg string) error
+	Warning(eid uint32, msg string) error
+	Error(eid uint32, msg string) error
+	Close() error
+}
+
+// eventLogCore is a zapcore.Core which writes entries to Windows Event Log.
+type eventLogCore struct {
+	enc zapcore.Encoder
+	w   *eventLogWriter
+}
+
+// NewEventLogCore creates a core which writes every entry to Windows Event Log as JSON.
+// Debug and info entries are written as information events, warn entries as warning events,
+// and higher entries as error events, with event IDs derived from the level.
+// The event source is opened lazily, and Close closes it.
+func NewEventLogCore(config EventLogConfig) zapcore.Core {
+	if config.Source == "" {
+		exe := filepath.Base(os.Args[0])
+		config.Source = strings.TrimSuffix(exe, filepath.Ext(exe))
+	}
+	if config.BaseEventID == 0 {
+		config.BaseEventID = defaultEventLogBaseID
+	}
+	return newEventLogCore(config, openEventLog)
+}
+
+// newEventLogCore creates a core which opens the event source via given function.
+func newEventLogCore(config EventLogConfig, open func(source string) (eventLogger, error)) zapcore.Core {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		MessageKey:     "msg",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		StacktraceKey:  "stacktrace",
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	})
+	return &eventLogCore{enc: enc, w: &eventLogWriter{config: config, open: open}}
+}
+
+// Enabled reports true for every level.
+func (c *eventLogCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With returns a child core with given fields.
+func (c *eventLogCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	return &eventLogCore{enc: enc, w: c.w}
+}
+
+// Check adds the core to given checked entry.
+func (c *eventLogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+// Write encodes given entry and writes it as an event.
+func (c *eventLogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	msg, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer msg.Free()
+	return c.w.write(ent.Level, string(trimLineEnding(msg.Bytes())))
+}
+
+// Sync does nothing, because events are written without buffering.
+func (c *eventLogCore) Sync() error {
+	return nil
+}
+
+// Close closes the event source, or gives up when given context is done. It is opened again on next write.
+func (c *eventLogCore) Close(ctx context.Context) error {
+	return closeWithContext(ctx, c.w.close)
+}
+
+// eventLogWriter writes events to an event source opened lazily.
+type eventLogWriter struct {
+	config EventLogConfig
+	open   func(source string) (eventLogger, error)
+
+	// mu guards log.
+	mu  sync.Mutex
+	log eventLogger
+}
+
+// write writes given message as an event of given level.
+func (w *eventLogWriter) write(level zapcore.Level, msg string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.log == nil {
+		log, err := w.open(w.config.Source)
+		if err != nil {
+			return fmt.Errorf("logging: failed to open event source %q: %w", w.config.Source, err)
+		}
+		w.log = log
+	}
+
+	id := eventID(w.config.BaseEventID, level)
+	var err error
+	switch {
+	case level >= zapcore.ErrorLevel:
+		err = w.log.Error(id, msg)
+	case level == zapcore.WarnLevel:
+		err = w.log.Warning(id, msg)
+	default:
+		err = w.log.Info(id, msg)
+	}
+	if err != nil {
+		return fmt.Errorf("logging: failed to write to event log: %w", err)
+	}
+	return nil
+}
+
+// close closes the event source if opened.
+func (w *eventLogWriter) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.log == nil {
+		return nil
+	}
+	err := w.log.Close()
+	w.log = nil
+	return err
+}
+
+// eventID returns an event ID of given level, counted from given base ID of debug level.
+func eventID(base uint32, level zapcore.Level) uint32 {
+	if level < zapcore.DebugLevel {
+		level = zapcore.DebugLevel
+	}
+	return base + uint32(level-zapcore.DebugLevel)
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package logging
+
+import "errors"
+
+// openEventLog fails, because Windows Event Log is available only on Windows.
+func openEventLog(string) (eventLogger, error) {
+	return nil, errors.New("windows event log is not supported on this platform")
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// event is an event written to eventLogRecorder.
+type event struct {
+	kind string
+	id   uint32
+	msg  string
+}
+
+// eventLogRecorder is an eventLogger which records written events.
+type eventLogRecorder struct {
+	events []event
+	closed bool
+}
+
+func (r *eventLogRecorder) Info(id uint32, msg string) error {
+	r.events = append(r.events, event{kind: "info", id: id, msg: msg})
+	return nil
+}
+
+func (r *eventLogRecorder) Warning(id uint32, msg string) error {
+	r.events = append(r.events, event{kind: "warning", id: id, msg: msg})
+	return nil
+}
+
+func (r *eventLogRecorder) Error(id uint32, msg string) error {
+	r.events = append(r.events, event{kind: "error", id: id, msg: msg})
+	return nil
+}
+
+func (r *eventLogRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestEventLogCore(t *testing.T) {
+	t.Parallel()
+
+	recorder := &eventLogRecorder{}
+	var sources []string
+	core := newEventLogCore(EventLogConfig{Source: "app", BaseEventID: 100}, func(source string) (eventLogger, error) {
+		sources = append(sources, source)
+		return recorder, nil
+	})
+	logger := zap.New(core).With(zap.String("service", "api"))
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error", zap.Int("code", 1))
+
+	want := []event{
+		{kind: "info", id: 100, msg: `{"msg":"debug","service":"api"}`},
+		{kind: "info", id: 101, msg: `{"msg":"info","service":"api"}`},
+		{kind: "warning", id: 102, msg: `{"msg":"warn","service":"api"}`},
+		{kind: "error", id: 103, msg: `{"msg":"error","service":"api","code":1}`},
+	}
+	if diff := cmp.Diff(want, recorder.events, cmp.AllowUnexported(event{})); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"app"}, sources); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	if err := core.(*eventLogCore).Close(context.Background()); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if !recorder.closed {
+		t.Error("expect event source closed, but not closed")
+	}
+}
+
+func TestEventLogCoreOpenError(t *testing.T) {
+	t.Parallel()
+
+	core := newEventLogCore(EventLogConfig{Source: "app"}, func(string) (eventLogger, error) {
+		return nil, errors.New("access denied")
+	})
+	err := core.Write(zapcore.Entry{Message: "failed"}, nil)
+	if err == nil || !strings.Contains(err.Error(), `event source "app"`) {
+		t.Errorf("expect open error, but received %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package logging
+
+import "golang.org/x/sys/windows/svc/eventlog"
+
+// openEventLog opens given event source of the Application log.
+func openEventLog(source string) (eventLogger, error) {
+	return eventlog.Open(source)
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// ContextExtractor derives fields from application specific values in given context, such as tenant ID and locale.
+// It is called on each lookup of FromContext, so it should be cheap, and it returns nil if the context has no value.
+type ContextExtractor func(ctx context.Context) []zap.Field
+
+// contextExtractors holds registered extractors. The slice is replaced on registration,
+// so that lookup reads it without locking.
+var contextExtractors struct {
+	mu         sync.Mutex
+	extractors atomic.Pointer[[]*ContextExtractor]
+}
+
+// WithContextExtractor registers given extractor to the package, so that loggers returned by FromContext
+// and StructuredFromContext contain fields derived by it, after fields derived by this package.
+// Extractors are called in registration order. It returns a function to unregister the extractor.
+func WithContextExtractor(extractor ContextExtractor) (unregister func()) {
+	registered := &extractor
+
+	contextExtractors.mu.Lock()
+	defer contextExtractors.mu.Unlock()
+
+	var current []*ContextExtractor
+	if p := contextExtractors.extractors.Load(); p != nil {
+		current = *p
+	}
+	next := append(append(make([]*ContextExtractor, 0, len(current)+1), current...), registered)
+	contextExtractors.extractors.Store(&next)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { removeContextExtractor(registered) })
+	}
+}
+
+// removeContextExtractor unregisters given extractor.
+func removeContextExtractor(registered *ContextExtractor) {
+	contextExtractors.mu.Lock()
+	defer contextExtractors.mu.Unlock()
+
+	p := contextExtractors.extractors.Load()
+	if p == nil {
+		return
+	}
+	next := make([]*ContextExtractor, 0, len(*p))
+	for _, e := range *p {
+		if e != registered {
+			next = append(next, e)
+		}
+	}
+	contextExtractors.extractors.Store(&next)
+}
+
+// extractedFields returns fields derived from given context by registered extractors.
+func extractedFields(ctx context.Context) []zap.Field {
+	p := contextExtractors.extractors.Load()
+	if p == nil {
+		return nil
+	}
+	var fields []zap.Field
+	for _, extract := range *p {
+		fields = append(fields, (*extract)(ctx)...)
+	}
+	return fields
+}
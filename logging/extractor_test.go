@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// tenantKey is a context key of tenant ID used in tests.
+type tenantKey struct{}
+
+// TestWithContextExtractor is not parallel, because it registers extractors to the package.
+func TestWithContextExtractor(t *testing.T) {
+	unregisterTenant := WithContextExtractor(func(ctx context.Context) []zap.Field {
+		if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+			return []zap.Field{zap.String("tenant_id", tenant)}
+		}
+		return nil
+	})
+	defer unregisterTenant()
+	unregisterLocale := WithContextExtractor(func(context.Context) []zap.Field {
+		return []zap.Field{zap.String("locale", "ja-JP")}
+	})
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := WithStructuredLogger(context.Background(), zap.New(core))
+	FromContext(context.WithValue(ctx, tenantKey{}, "acme")).Info("extracted")
+	unregisterLocale()
+	unregisterLocale()
+	StructuredFromContext(ctx).Info("unregistered")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, but received %d", len(entries))
+	}
+	if diff := cmp.Diff(map[string]any{"tenant_id": "acme", "locale": "ja-JP"}, entries[0].ContextMap()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{}, entries[1].ContextMap()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
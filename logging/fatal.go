@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// WithFatalHook makes Fatal call given hook after the entry is written and sinks are flushed, instead of os.Exit.
+// If the hook returns, Fatal also returns, so that tests can intercept fatal entries.
+func WithFatalHook(hook zapcore.CheckWriteHook) Option {
+	return func(o *options) {
+		o.fatalHook = hook
+	}
+}
+
+// WithExitFunc makes Fatal call given function with exit code 1, instead of os.Exit,
+// after the entry is written and sinks are flushed.
+func WithExitFunc(exit func(code int)) Option {
+	return WithFatalHook(exitHook(exit))
+}
+
+// exitHook is a zapcore.CheckWriteHook which calls the function with exit code 1.
+type exitHook func(code int)
+
+// OnWrite calls the function.
+func (h exitHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	h(1)
+}
+
+// fatalHook is a zapcore.CheckWriteHook which flushes the core of the logger before calling next hook,
+// so that entries queued for remote sinks are not lost when the process exits.
+type fatalHook struct {
+	core zapcore.Core
+	next zapcore.CheckWriteHook
+}
+
+// OnWrite syncs the core and calls next hook.
+func (h fatalHook) OnWrite(ce *zapcore.CheckedEntry, fields []zapcore.Field) {
+	_ = h.core.Sync()
+	h.next.OnWrite(ce, fields)
+}
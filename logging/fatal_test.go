@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithExitFunc(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	var codes []int
+	logger := NewStructuredLogger(
+		WithWriteSyncer(buf),
+		WithAsync(AsyncConfig{}),
+		WithExitFunc(func(code int) { codes = append(codes, code) }),
+	)
+	t.Cleanup(func() { _ = Flush(context.Background()) })
+
+	logger.Info("queued")
+	logger.Fatal("fatal")
+
+	if diff := cmp.Diff([]int{1}, codes); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if len(buf.Lines()) != 2 {
+		t.Errorf("expect queued entries to be written before exit, but received %v", buf.Lines())
+	}
+}
+
+func TestWithFatalHook(t *testing.T) {
+	t.Parallel()
+
+	recorder := &kafkaRecorder{}
+	var messages []string
+	var published int
+	logger := NewStructuredLogger(
+		WithWriteSyncer(&zaptest.Buffer{}),
+		WithKafka(KafkaConfig{Writer: recorder, FlushInterval: time.Hour}),
+		WithFatalHook(hookFunc(func(ce *zapcore.CheckedEntry, _ []zapcore.Field) {
+			messages = append(messages, ce.Message)
+			recorder.mu.Lock()
+			published = len(recorder.messages)
+			recorder.mu.Unlock()
+		})),
+	)
+	logger.Info("queued")
+	logger.Fatal("fatal", zap.String("key", "value"))
+
+	if diff := cmp.Diff([]string{"fatal"}, messages); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if published != 2 {
+		t.Errorf("expect 2 messages published before the hook, but received %d", published)
+	}
+}
+
+// hookFunc is a zapcore.CheckWriteHook implemented by a function.
+type hookFunc func(*zapcore.CheckedEntry, []zapcore.Field)
+
+func (f hookFunc) OnWrite(ce *zapcore.CheckedEntry, fields []zapcore.Field) {
+	f(ce, fields)
+}
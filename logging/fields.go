@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// WithFields attaches given fields to given context.
+// Fields are accumulated across calls, and the logger returned by FromContext contains all of them.
+// Each field is a key-value pair or a zap.Field, as accepted by (*zap.SugaredLogger).With.
+// If context is nil, it will panic. because, this function is wrapper for context.WithValue.
+func WithFields(ctx context.Context, fields ...any) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	return withContextLogger(ctx, func(c *contextLogger) {
+		// copy accumulated fields to avoid sharing the backing array with parent context.
+		merged := make([]any, 0, len(c.fields)+len(fields))
+		merged = append(merged, c.fields...)
+		c.fields = append(merged, fields...)
+	})
+}
+
+// contextFields returns fields derived from given context and its stored contextLogger on each lookup.
+// Unlike fields attached by WithFields, they can not be composed in advance
+// because they depend on values stored in context by other packages.
+// They are looked up only while enabled, so that lookup does not walk the context chain otherwise.
+func contextFields(ctx context.Context, stored *contextLogger) []zap.Field {
+	correlated := stored != nil && stored.correlated
+	var fields []zap.Field
+	if trace := traceFields(ctx, correlated); len(trace) > 0 {
+		fields = append(fields, trace...)
+	}
+	if correlated {
+		fields = append(fields, baggageFields(ctx)...)
+	}
+	if deadline := deadlineFields(ctx); len(deadline) > 0 {
+		fields = append(fields, deadline...)
+	}
+	if extracted := extractedFields(ctx); len(extracted) > 0 {
+		fields = append(fields, extracted...)
+	}
+	return fields
+}
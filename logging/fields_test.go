@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithFields(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := WithLogger(context.Background(), zap.New(core).Sugar())
+
+	ctx1 := WithFields(ctx, "service", "test")
+	ctx2 := WithFields(ctx1, zap.String("user", "u1"))
+	ctx3 := WithFields(ctx1, "user", "u2")
+
+	FromContext(ctx2).Info("first")
+	StructuredFromContext(ctx3).Info("second")
+	FromContext(ctx1).Info("third")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("expect 3 entries, but received %d", len(entries))
+	}
+
+	wants := []map[string]any{
+		{"service": "test", "user": "u1"},
+		{"service": "test", "user": "u2"},
+		{"service": "test"},
+	}
+	for i, want := range wants {
+		if diff := cmp.Diff(want, entries[i].ContextMap()); diff != "" {
+			t.Errorf("entry %d (-want, +got)\n%s", i, diff)
+		}
+	}
+}
+
+func TestWithFieldsBeforeLogger(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := WithFields(context.Background(), "request", "r1")
+	ctx = WithLogger(ctx, zap.New(core).Sugar())
+
+	FromContext(ctx).Info("message")
+
+	if diff := cmp.Diff(1, logs.FilterField(zap.String("request", "r1")).Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
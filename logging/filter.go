@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"regexp"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithMessageFilter drops entries whose message matches any of given patterns,
+// such as well-known harmless TLS handshake errors. Entries are dropped before they are encoded.
+// Entries higher than error level are never dropped.
+func WithMessageFilter(patterns ...*regexp.Regexp) Option {
+	return func(o *options) {
+		if o.messageFilters == nil {
+			o.messageFilters = &messageFilters{}
+		}
+		o.messageFilters.set(append(o.messageFilters.load(), patterns...))
+	}
+}
+
+// messageFilters is a list of patterns which can be replaced at runtime.
+type messageFilters struct {
+	patterns atomic.Pointer[[]*regexp.Regexp]
+}
+
+// set replaces patterns.
+func (f *messageFilters) set(patterns []*regexp.Regexp) {
+	patterns = append([]*regexp.Regexp(nil), patterns...)
+	f.patterns.Store(&patterns)
+}
+
+// load returns current patterns.
+func (f *messageFilters) load() []*regexp.Regexp {
+	if patterns := f.patterns.Load(); patterns != nil {
+		return *patterns
+	}
+	return nil
+}
+
+// match reports whether given message matches any pattern.
+func (f *messageFilters) match(msg string) bool {
+	for _, pattern := range f.load() {
+		if pattern.MatchString(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// messageFilterCore is a zapcore.Core which drops entries whose message matches any pattern.
+type messageFilterCore struct {
+	zapcore.Core
+
+	filters *messageFilters
+}
+
+// newMessageFilterCore wraps given core, so that entries whose message matches any of given filters are dropped.
+func newMessageFilterCore(core zapcore.Core, filters *messageFilters) zapcore.Core {
+	return &messageFilterCore{Core: core, filters: filters}
+}
+
+// With returns a child core with given fields.
+func (c *messageFilterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &messageFilterCore{Core: c.Core.With(fields), filters: c.filters}
+}
+
+// Check asks the wrapped core whether given entry should be written, unless its message matches any pattern.
+func (c *messageFilterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level <= zapcore.ErrorLevel && c.filters.match(ent.Message) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
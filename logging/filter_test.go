@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithMessageFilter(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(
+		WithWriteSyncer(buf),
+		WithMessageFilter(regexp.MustCompile(`^http: TLS handshake error`), regexp.MustCompile(`EOF$`)),
+	)
+	logger.Error("http: TLS handshake error from 10.0.0.1:5000: EOF")
+	logger.With("key", "value").Warn("unexpected EOF")
+	logger.Info("served")
+
+	lines := buf.Lines()
+	if diff := cmp.Diff(1, len(lines)); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+	if !strings.Contains(lines[0], `"msg":"served"`) {
+		t.Errorf("expect unmatched entry to be written, but received %s", lines[0])
+	}
+}
@@ -0,0 +1,312 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// fluentDefaultAddress is a default address of Fluentd or Fluent Bit.
+	fluentDefaultAddress = "localhost:24224"
+
+	// fluentRetries is a number of attempts to send a batch, reconnecting between them.
+	fluentRetries = 3
+
+	// fluentBackoff is an initial duration to wait before reconnecting. It doubles on each attempt.
+	fluentBackoff = 100 * time.Millisecond
+
+	// fluentTimeout is a timeout of connecting and writing.
+	fluentTimeout = 5 * time.Second
+)
+
+// FluentConfig is a configuration of Fluentd or Fluent Bit destination.
+type FluentConfig struct {
+	// Network is a network of the server such as "tcp" or "unix". If empty, "tcp" is used.
+	Network string
+
+	// Address is an address of the server. If empty, "localhost:24224" is used.
+	Address string
+
+	// Tag is a tag of events. If empty, the name of the executable is used.
+	Tag string
+
+	// QueueSize is a maximum number of entries waiting to be sent. If zero, 8192 is used.
+	QueueSize int
+
+	// BatchSize is a maximum number of entries sent at once. If zero, 256 is used.
+	BatchSize int
+
+	// FlushInterval is an interval to send queued entries. If zero, one second is used.
+	FlushInterval time.Duration
+
+	// Block reports whether logging blocks while the queue is full.
+	// If false, entries are dropped and counted as log_entries_dropped_total{sink="fluent"}.
+	Block bool
+
+	// Spool persists batches which could not be delivered to disk, and replays them when the server is reachable again.
+	// If nil, such batches are dropped.
+	Spool *SpoolConfig
+}
+
+// WithFluent ships entries to Fluentd or Fluent Bit via the forward protocol, in addition to the logger's own output.
+// Entries are filtered at the logger's level and component levels.
+func WithFluent(config FluentConfig) Option {
+	return func(o *options) {
+		o.leveledCores = append(o.leveledCores, NewFluentCore(config))
+	}
+}
+
+// fluentEvent is an entry waiting to be sent.
+type fluentEvent struct {
+	time   time.Time
+	record map[string]any
+}
+
+// fluentCore is a zapcore.Core which sends entries via the forward protocol.
+type fluentCore struct {
+	fields    []zapcore.Field
+	forwarder *fluentForwarder
+}
+
+// NewFluentCore creates a core which sends every entry to Fluentd or Fluent Bit via the forward protocol.
+// Entries are queued and sent in batches as MessagePack over a connection from a background goroutine,
+// and the connection is reestablished with backoff if sending fails. Sync sends queued entries and waits for it.
+func NewFluentCore(config FluentConfig) zapcore.Core {
+	if config.Network == "" {
+		config.Network = "tcp"
+	}
+	if config.Address == "" {
+		config.Address = fluentDefaultAddress
+	}
+	if config.Tag == "" {
+		config.Tag = filepath.Base(os.Args[0])
+	}
+
+	f := &fluentForwarder{config: config}
+	var s *spool[fluentEvent]
+	if config.Spool != nil {
+		s = newSpool(*config.Spool, "fluent", encodeFluentEvent, decodeFluentEvent)
+	}
+	f.batcher = newSpoolingBatcher(batcherConfig{
+		sink:      "fluent",
+		queueSize: config.QueueSize,
+		batchSize: config.BatchSize,
+		interval:  config.FlushInterval,
+		block:     config.Block,
+	}, f.send, s)
+	return &fluentCore{forwarder: f}
+}
+
+// Enabled reports true for every level.
+func (c *fluentCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With returns a child core with given fields.
+func (c *fluentCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &fluentCore{fields: merged, forwarder: c.forwarder}
+}
+
+// Check adds the core to given checked entry.
+func (c *fluentCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+// Write converts given entry to a record and queues it.
+func (c *fluentCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.forwarder.batcher.add(fluentEvent{time: ent.Time, record: entryRecord(ent, c.fields, fields)})
+	return nil
+}
+
+// Sync sends queued entries and waits for it.
+func (c *fluentCore) Sync() error {
+	return c.forwarder.batcher.flush()
+}
+
+// Close sends queued entries and stops the background goroutine, or gives up when given context is done.
+func (c *fluentCore) Close(ctx context.Context) error {
+	return closeWithContext(ctx, c.forwarder.batcher.close)
+}
+
+// entryRecord converts given entry and fields to a map, with "level", "msg", "logger", "caller", and "stacktrace" keys.
+// Values are copied, so that the record is not affected by values changed by the caller after logging.
+func entryRecord(ent zapcore.Entry, contextFields, fields []zapcore.Field) map[string]any {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range contextFields {
+		field.AddTo(enc)
+	}
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	record := make(map[string]any, len(enc.Fields)+5)
+	for k, v := range enc.Fields {
+		record[k] = snapshotValue(v)
+	}
+	record["level"] = ent.Level.String()
+	record["msg"] = ent.Message
+	if ent.LoggerName != "" {
+		record["logger"] = ent.LoggerName
+	}
+	if ent.Caller.Defined {
+		record["caller"] = ent.Caller.TrimmedPath()
+	}
+	if ent.Stack != "" {
+		record["stacktrace"] = ent.Stack
+	}
+	return record
+}
+
+// snapshotValue returns a copy of given value encoded by zapcore.MapObjectEncoder.
+// Values added via AddReflected, which refer to values of the caller, are converted to JSON compatible values,
+// or to their string representation if they can not be encoded in JSON.
+func snapshotValue(v any) any {
+	switch v := v.(type) {
+	case nil, bool, string, int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8, uintptr,
+		float64, float32, complex128, complex64, time.Time, time.Duration:
+		return v
+	case []byte:
+		return append([]byte(nil), v...)
+	case []any:
+		values := make([]any, len(v))
+		for i, e := range v {
+			values[i] = snapshotValue(e)
+		}
+		return values
+	case map[string]any:
+		values := make(map[string]any, len(v))
+		for k, e := range v {
+			values[k] = snapshotValue(e)
+		}
+		return values
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		var value any
+		if err := json.Unmarshal(b, &value); err != nil {
+			return string(b)
+		}
+		return value
+	}
+}
+
+// fluentForwarder sends batches of events to the server. It is used only from the goroutine of batcher.
+type fluentForwarder struct {
+	config  FluentConfig
+	batcher *batcher[fluentEvent]
+	conn    net.Conn
+}
+
+// send encodes given events in forward mode and writes them, reconnecting with backoff if writing fails.
+func (f *fluentForwarder) send(events []fluentEvent) error {
+	msg := f.encode(events)
+
+	var err error
+	backoff := fluentBackoff
+	for attempt := 0; attempt < fluentRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		if f.conn == nil {
+			if f.conn, err = net.DialTimeout(f.config.Network, f.config.Address, fluentTimeout); err != nil {
+				continue
+			}
+		}
+		if err = f.conn.SetWriteDeadline(time.Now().Add(fluentTimeout)); err == nil {
+			if _, err = f.conn.Write(msg); err == nil {
+				return nil
+			}
+		}
+		_ = f.conn.Close()
+		f.conn = nil
+	}
+	return fmt.Errorf("logging: failed to send %d entries to fluent: %w", len(events), err)
+}
+
+// encode encodes given events as a message of forward mode, [tag, [[time, record], ...], {"size": n}].
+func (f *fluentForwarder) encode(events []fluentEvent) []byte {
+	b := make([]byte, 0, 256*len(events))
+	b = append(b, 0x93)
+	b = appendMsgpackString(b, f.config.Tag)
+	b = appendMsgpackLength(b, len(events), 0x90, 0xdc, 0xdd)
+	for _, event := range events {
+		b = append(b, 0x92)
+		b = appendEventTime(b, event.time)
+		b = appendMsgpack(b, event.record)
+	}
+	return appendMsgpack(b, map[string]any{"size": len(events)})
+}
+
+// appendEventTime appends given time as EventTime extension of the forward protocol.
+func appendEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, 0x00)
+	b = binary.BigEndian.AppendUint32(b, uint32(t.Unix()))
+	return binary.BigEndian.AppendUint32(b, uint32(t.Nanosecond()))
+}
+
+// encodeFluentEvent encodes given event for spool, as the time in nanoseconds and the record in JSON.
+// Values which can not be encoded in JSON are written as their string representation.
+func encodeFluentEvent(event fluentEvent) []byte {
+	record, err := json.Marshal(event.record)
+	if err != nil {
+		record, _ = json.Marshal(map[string]any{"msg": event.record["msg"], "spool_error": err.Error()})
+	}
+	b := make([]byte, 0, 8+len(record))
+	b = binary.BigEndian.AppendUint64(b, uint64(event.time.UnixNano()))
+	return append(b, record...)
+}
+
+// decodeFluentEvent decodes an event encoded by encodeFluentEvent.
+// Integers are decoded as int64, and other numbers as float64.
+func decodeFluentEvent(b []byte) (fluentEvent, error) {
+	if len(b) < 8 {
+		return fluentEvent{}, errors.New("logging: malformed spooled fluent event")
+	}
+	dec := json.NewDecoder(bytes.NewReader(b[8:]))
+	dec.UseNumber()
+	var record map[string]any
+	if err := dec.Decode(&record); err != nil {
+		return fluentEvent{}, fmt.Errorf("logging: malformed spooled fluent event: %w", err)
+	}
+	return fluentEvent{time: time.Unix(0, int64(binary.BigEndian.Uint64(b))), record: decodeJSONNumbers(record).(map[string]any)}, nil
+}
+
+// decodeJSONNumbers replaces json.Number in given value with int64 or float64 recursively.
+func decodeJSONNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, value := range v {
+			v[key] = decodeJSONNumbers(value)
+		}
+		return v
+	case []any:
+		for i, value := range v {
+			v[i] = decodeJSONNumbers(value)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithFluent(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		buf := make([]byte, 4096)
+		n, _ := io.ReadAtLeast(conn, buf, 1)
+		received <- buf[:n]
+	}()
+
+	logger := NewLogger(
+		WithWriteSyncer(&zaptest.Buffer{}),
+		WithFluent(FluentConfig{Address: ln.Addr().String(), Tag: "app", FlushInterval: time.Hour}),
+	)
+	logger.Debug("ignored")
+	logger.Infow("hello", "user", 42)
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		// [tag, [[time, record]], option]
+		prefix := []byte{0x93, 0xa3, 'a', 'p', 'p', 0x91, 0x92, 0xd7, 0x00}
+		if !bytes.HasPrefix(msg, prefix) {
+			t.Errorf("expect prefix %x, but received %x", prefix, msg)
+		}
+		for _, s := range []string{"hello", "user", "info"} {
+			if !bytes.Contains(msg, appendMsgpackString(nil, s)) {
+				t.Errorf("expect %q in message, but received %x", s, msg)
+			}
+		}
+		if bytes.Contains(msg, appendMsgpackString(nil, "ignored")) {
+			t.Errorf("expect no debug entry, but received %x", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expect a message, but timed out")
+	}
+}
+
+func TestFluentEventCodec(t *testing.T) {
+	t.Parallel()
+
+	event := fluentEvent{
+		time:   time.Unix(1700000000, 123),
+		record: map[string]any{"msg": "spooled", "count": int64(3), "ratio": 0.5, "tags": []any{"a", int64(1)}},
+	}
+	got, err := decodeFluentEvent(encodeFluentEvent(event))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if !got.time.Equal(event.time) {
+		t.Errorf("expect %v, but received %v", event.time, got.time)
+	}
+	if diff := cmp.Diff(event.record, got.record); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestEntryRecordCopiesValues(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		Path string `json:"path"`
+	}
+	req := &request{Path: "/users"}
+	tags := []string{"a"}
+	record := entryRecord(zapcore.Entry{Message: "handled"}, nil, []zapcore.Field{
+		zap.Reflect("request", req),
+		zap.Any("tags", tags),
+	})
+
+	// Values changed after logging must not affect the record encoded later.
+	req.Path = "/changed"
+	tags[0] = "changed"
+
+	want := map[string]any{
+		"level":   "info",
+		"msg":     "handled",
+		"request": map[string]any{"path": "/users"},
+		"tags":    []any{"a"},
+	}
+	if diff := cmp.Diff(want, record); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
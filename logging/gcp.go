@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// gcpTraceKey is a field key of the trace which Cloud Logging associates entries with.
+	gcpTraceKey = "logging.googleapis.com/trace"
+
+	// gcpSpanIDKey is a field key of the span which Cloud Logging associates entries with.
+	gcpSpanIDKey = "logging.googleapis.com/spanId"
+)
+
+// NewGCPLogger creates a logger from environment variables, which writes entries in the format of Cloud Logging.
+// See WithGCPEncoding for the format.
+func NewGCPLogger(opts ...Option) *zap.SugaredLogger {
+	return NewLoggerFromEnv(append([]Option{WithGCPEncoding()}, opts...)...)
+}
+
+// WithGCPEncoding makes the logger write JSON entries with "severity", "message", and "timestamp" keys
+// which Cloud Logging expects, and levels are mapped to Cloud Logging severities.
+// If GOOGLE_CLOUD_PROJECT is set, trace fields added by FromContext are written as
+// "logging.googleapis.com/trace" and "logging.googleapis.com/spanId", so that entries are correlated with traces.
+func WithGCPEncoding() Option {
+	project := strings.TrimSpace(os.Getenv("GOOGLE_CLOUD_PROJECT"))
+	return func(o *options) {
+		o.encoding = "json"
+		o.encoderConfigs = append(o.encoderConfigs, gcpEncoderConfig)
+		if project != "" {
+			o.scrubbers = append(o.scrubbers, gcpTraceScrubber(project))
+		}
+	}
+}
+
+// gcpEncoderConfig modifies given encoder configuration for Cloud Logging.
+func gcpEncoderConfig(config *zapcore.EncoderConfig) {
+	config.MessageKey = "message"
+	config.LevelKey = "severity"
+	config.TimeKey = "timestamp"
+	config.StacktraceKey = "stack_trace"
+	config.EncodeLevel = gcpLevelEncoder
+	config.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+}
+
+// gcpLevelEncoder encodes given level as Cloud Logging severity.
+func gcpLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(gcpSeverity(l))
+}
+
+// gcpSeverity converts given zap level to Cloud Logging severity.
+func gcpSeverity(l zapcore.Level) string {
+	switch l {
+	case zapcore.DebugLevel:
+		return "DEBUG"
+	case zapcore.InfoLevel:
+		return "INFO"
+	case zapcore.WarnLevel:
+		return "WARNING"
+	case zapcore.ErrorLevel:
+		return "ERROR"
+	case zapcore.DPanicLevel:
+		return "CRITICAL"
+	case zapcore.PanicLevel:
+		return "ALERT"
+	case zapcore.FatalLevel:
+		return "EMERGENCY"
+	default:
+		return "DEFAULT"
+	}
+}
+
+// gcpTraceScrubber returns a Scrubber which rewrites trace fields to the keys of Cloud Logging in given project.
+func gcpTraceScrubber(project string) Scrubber {
+	return ScrubberFunc(func(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+		var rewritten []zapcore.Field
+		for i, field := range fields {
+			if field.Type != zapcore.StringType || (field.Key != traceIDKey && field.Key != spanIDKey) {
+				continue
+			}
+			if rewritten == nil {
+				rewritten = append([]zapcore.Field(nil), fields...)
+			}
+			if field.Key == traceIDKey {
+				rewritten[i] = zap.String(gcpTraceKey, "projects/"+project+"/traces/"+field.String)
+			} else {
+				rewritten[i] = zap.String(gcpSpanIDKey, field.String)
+			}
+		}
+		if rewritten == nil {
+			return ent, fields
+		}
+		return ent, rewritten
+	})
+}
@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithGCPEncoding(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "my-project")
+
+	buf := &zaptest.Buffer{}
+	logger := NewStructuredLogger(WithWriteSyncer(buf), WithGCPEncoding()).
+		With(zap.String(traceIDKey, "0af7651916cd43dd8448eb211c80319c"), zap.String(spanIDKey, "b7ad6b7169203331"))
+	logger.Warn("slow")
+
+	lines := buf.Lines()
+	if len(lines) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(lines))
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	if diff := cmp.Diff("WARNING", entry["severity"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("slow", entry["message"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if _, ok := entry["timestamp"].(string); !ok {
+		t.Errorf("expect timestamp string, but received %v", entry["timestamp"])
+	}
+	if diff := cmp.Diff("projects/my-project/traces/0af7651916cd43dd8448eb211c80319c", entry[gcpTraceKey]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("b7ad6b7169203331", entry[gcpSpanIDKey]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if _, ok := entry[traceIDKey]; ok {
+		t.Errorf("expect no %s key, but found", traceIDKey)
+	}
+}
+
+func TestGCPSeverity(t *testing.T) {
+	t.Parallel()
+
+	tests := map[zapcore.Level]string{
+		zapcore.DebugLevel:  "DEBUG",
+		zapcore.InfoLevel:   "INFO",
+		zapcore.WarnLevel:   "WARNING",
+		zapcore.ErrorLevel:  "ERROR",
+		zapcore.DPanicLevel: "CRITICAL",
+		zapcore.PanicLevel:  "ALERT",
+		zapcore.FatalLevel:  "EMERGENCY",
+	}
+	for level, want := range tests {
+		if diff := cmp.Diff(want, gcpSeverity(level)); diff != "" {
+			t.Errorf("%s: (-want, +got)\n%s", level, diff)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// gelfDefaultChunkSize is a default maximum size of UDP datagrams.
+	gelfDefaultChunkSize = 8192
+
+	// gelfMaxChunks is a maximum number of chunks of a message defined by GELF.
+	gelfMaxChunks = 128
+
+	// gelfChunkHeaderSize is a size of the header of a chunk: magic bytes, message ID, sequence, and count.
+	gelfChunkHeaderSize = 12
+)
+
+// GELFConfig is a configuration of Graylog destination.
+type GELFConfig struct {
+	// Network is a network of the server, "udp" or "tcp". If empty, "udp" is used.
+	Network string
+
+	// Address is an address of the server such as "graylog:12201".
+	Address string
+
+	// Host is a host field of messages. If empty, the hostname of the machine is used.
+	Host string
+
+	// Compress reports whether UDP messages are compressed with gzip. TCP messages are never compressed.
+	Compress bool
+
+	// ChunkSize is a maximum size of UDP datagrams. Larger messages are chunked. If zero, 8192 is used.
+	ChunkSize int
+}
+
+// WithGELF ships entries to Graylog in GELF, in addition to the logger's own output.
+// Entries are filtered at the logger's level and component levels.
+func WithGELF(config GELFConfig) Option {
+	return func(o *options) {
+		o.leveledCores = append(o.leveledCores, NewGELFCore(config))
+	}
+}
+
+// parseGELFAddr parses given address such as "udp://graylog:12201" or "graylog:12201".
+// If the address has no scheme, UDP with compression is used.
+func parseGELFAddr(addr string) (GELFConfig, bool) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return GELFConfig{}, false
+	}
+	network, address, ok := strings.Cut(addr, "://")
+	if !ok {
+		network, address = "udp", addr
+	}
+	switch network {
+	case "udp", "tcp":
+	default:
+		return GELFConfig{}, false
+	}
+	return GELFConfig{Network: network, Address: address, Compress: network == "udp"}, true
+}
+
+// gelfCore is a zapcore.Core which sends entries as GELF messages.
+type gelfCore struct {
+	fields []zapcore.Field
+	w      *gelfWriter
+}
+
+// NewGELFCore creates a core which sends every entry to Graylog as a GELF message.
+// Over UDP, messages larger than the chunk size are chunked, and over TCP, messages are delimited by null bytes.
+// Fields are sent as additional fields prefixed with an underscore.
+// Connecting and writing time out in a second, and messages failed to write are dropped
+// and counted as log_entries_dropped_total{sink="gelf"}.
+func NewGELFCore(config GELFConfig) zapcore.Core {
+	if config.Network == "" {
+		config.Network = "udp"
+	}
+	if config.Host == "" {
+		config.Host, _ = os.Hostname()
+	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = gelfDefaultChunkSize
+	}
+	return &gelfCore{w: newGELFWriter(config)}
+}
+
+// Enabled reports true for every level.
+func (c *gelfCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With returns a child core with given fields.
+func (c *gelfCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &gelfCore{fields: merged, w: c.w}
+}
+
+// Check adds the core to given checked entry.
+func (c *gelfCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+// Write converts given entry to a GELF message and sends it.
+func (c *gelfCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(enc)
+	}
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	msg := make(map[string]any, len(enc.Fields)+8)
+	for k, v := range enc.Fields {
+		if k == "id" {
+			k = "id_"
+		}
+		msg["_"+k] = v
+	}
+	msg["version"] = "1.1"
+	msg["host"] = c.w.config.Host
+	msg["short_message"] = ent.Message
+	msg["timestamp"] = float64(ent.Time.UnixNano()) / float64(time.Second)
+	msg["level"] = syslogSeverity(ent.Level)
+	if ent.LoggerName != "" {
+		msg["_logger"] = ent.LoggerName
+	}
+	if ent.Caller.Defined {
+		msg["_file"] = ent.Caller.File
+		msg["_line"] = ent.Caller.Line
+	}
+	if ent.Stack != "" {
+		msg["full_message"] = ent.Message + "\n" + ent.Stack
+	}
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return c.w.write(b)
+}
+
+// Sync does nothing, because messages are written without buffering.
+func (c *gelfCore) Sync() error {
+	return nil
+}
+
+// gelfWriter writes GELF messages to a connection.
+type gelfWriter struct {
+	config GELFConfig
+	conn   *sinkConn
+}
+
+// newGELFWriter creates a writer which connects lazily.
+func newGELFWriter(config GELFConfig) *gelfWriter {
+	return &gelfWriter{config: config, conn: newSinkConn(func() (net.Conn, error) {
+		return net.DialTimeout(config.Network, config.Address, sinkDialTimeout)
+	})}
+}
+
+// write sends given message. If writing fails, the message is dropped and the connection is reestablished on next write.
+func (w *gelfWriter) write(msg []byte) error {
+	packets, err := w.packets(msg)
+	if err != nil {
+		return err
+	}
+	if err := w.conn.write(packets...); err != nil {
+		droppedEntries.WithLabelValues("gelf").Inc()
+		return fmt.Errorf("logging: failed to write to graylog: %w", err)
+	}
+	return nil
+}
+
+// packets returns given message framed for the network.
+func (w *gelfWriter) packets(msg []byte) ([][]byte, error) {
+	if w.config.Network == "tcp" {
+		return [][]byte{append(msg, 0)}, nil
+	}
+
+	if w.config.Compress {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(msg); err != nil {
+			return nil, err
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+		msg = buf.Bytes()
+	}
+	if len(msg) <= w.config.ChunkSize {
+		return [][]byte{msg}, nil
+	}
+	return gelfChunks(msg, w.config.ChunkSize)
+}
+
+// gelfChunks splits given message into chunks which fit in datagrams of given size.
+func gelfChunks(msg []byte, size int) ([][]byte, error) {
+	payload := size - gelfChunkHeaderSize
+	if payload <= 0 {
+		return nil, fmt.Errorf("logging: gelf chunk size %d is too small", size)
+	}
+	count := (len(msg) + payload - 1) / payload
+	if count > gelfMaxChunks {
+		return nil, errors.New("logging: gelf message is too large")
+	}
+
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+
+	chunks := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		end := min((i+1)*payload, len(msg))
+		chunk := make([]byte, 0, gelfChunkHeaderSize+end-i*payload)
+		chunk = append(chunk, 0x1e, 0x0f)
+		chunk = append(chunk, id[:]...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, msg[i*payload:end]...)
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
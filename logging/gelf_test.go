@@ -0,0 +1,118 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithGELF(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	defer conn.Close()
+
+	logger := NewLogger(
+		WithWriteSyncer(&zaptest.Buffer{}),
+		WithGELF(GELFConfig{Address: conn.LocalAddr().String(), Host: "host"}),
+	).Named("worker")
+	logger.Errorw("failed", "id", "j-1", "attempt", 3)
+
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	buf := make([]byte, gelfDefaultChunkSize)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	var msg map[string]any
+	if err := json.Unmarshal(buf[:n], &msg); err != nil {
+		t.Fatalf("expect JSON message, but received %q: %v", buf[:n], err)
+	}
+	want := map[string]any{
+		"version":       "1.1",
+		"host":          "host",
+		"short_message": "failed",
+		"level":         float64(3),
+		"_logger":       "worker",
+		"_id_":          "j-1",
+		"_attempt":      float64(3),
+	}
+	for key, value := range want {
+		if diff := cmp.Diff(value, msg[key]); diff != "" {
+			t.Errorf("%s: (-want, +got)\n%s", key, diff)
+		}
+	}
+	if _, ok := msg["full_message"]; !ok {
+		t.Error("expect full_message with stacktrace, but not found")
+	}
+}
+
+func TestGELFChunks(t *testing.T) {
+	t.Parallel()
+
+	msg := bytes.Repeat([]byte("a"), 25)
+	chunks, err := gelfChunks(msg, gelfChunkHeaderSize+10)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff(3, len(chunks)); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+
+	var joined []byte
+	for i, chunk := range chunks {
+		if !bytes.Equal(chunk[:2], []byte{0x1e, 0x0f}) || !bytes.Equal(chunk[2:10], chunks[0][2:10]) {
+			t.Errorf("expect chunk header with the same message ID, but received %x", chunk[:10])
+		}
+		if chunk[10] != byte(i) || chunk[11] != 3 {
+			t.Errorf("expect sequence %d of 3, but received %d of %d", i, chunk[10], chunk[11])
+		}
+		joined = append(joined, chunk[gelfChunkHeaderSize:]...)
+	}
+	if diff := cmp.Diff(msg, joined); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	if _, err := gelfChunks(bytes.Repeat([]byte("a"), gelfMaxChunks+1), gelfChunkHeaderSize+1); err == nil {
+		t.Error("expect an error, but received nil")
+	}
+}
+
+func TestParseGELFAddr(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		addr string
+		want GELFConfig
+		ok   bool
+	}{
+		"empty":     {addr: "", ok: false},
+		"no scheme": {addr: "graylog:12201", want: GELFConfig{Network: "udp", Address: "graylog:12201", Compress: true}, ok: true},
+		"tcp":       {addr: "tcp://graylog:12201", want: GELFConfig{Network: "tcp", Address: "graylog:12201"}, ok: true},
+		"unknown":   {addr: "http://graylog:12201", ok: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := parseGELFAddr(tt.addr)
+			if diff := cmp.Diff(tt.ok, ok); diff != "" {
+				t.Fatalf("(-want, +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
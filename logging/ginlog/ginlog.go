@@ -0,0 +1,38 @@
+// Package ginlog provides Gin middleware which bridges gin.Context to request-scoped loggers of package logging.
+package ginlog
+
+import (
+	"time"
+
+	"github.com/aqyuki/util/logging"
+	"github.com/aqyuki/util/logging/httplog"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Middleware returns a Gin middleware which attaches a request-scoped logger to the request context
+// and writes an access log, in the same way as httplog.Middleware.
+// Errors attached to the Gin context via c.Error are written to the access log as "errors" field.
+func Middleware(config httplog.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Request = c.Request.WithContext(httplog.RequestContext(c.Writer, c.Request, config))
+		c.Next()
+
+		if config.Skip != nil && config.Skip(c.Request) {
+			return
+		}
+		ctx := c.Request.Context()
+		if len(c.Errors) > 0 {
+			ctx = logging.WithFields(ctx, "errors", c.Errors.Errors())
+		}
+		httplog.LogAccess(ctx, c.Writer.Status(), int64(max(c.Writer.Size(), 0)), time.Since(start))
+	}
+}
+
+// FromContext returns the request-scoped logger of given Gin context.
+// If the middleware is not used, it will return a default logger.
+func FromContext(c *gin.Context) *zap.SugaredLogger {
+	return logging.FromContext(c.Request.Context())
+}
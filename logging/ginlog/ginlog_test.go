@@ -0,0 +1,64 @@
+package ginlog
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aqyuki/util/logging/httplog"
+	"github.com/gin-gonic/gin"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	r := gin.New()
+	r.Use(Middleware(httplog.Config{Logger: zap.New(core).Sugar()}))
+	r.GET("/users/:id", func(c *gin.Context) {
+		FromContext(c).Info("handling")
+		_ = c.Error(errors.New("database is down"))
+		c.String(http.StatusInternalServerError, "internal error")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if diff := cmp.Diff("req-1", rec.Header().Get("X-Request-ID")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("req-1", entries[0].ContextMap()["request_id"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	access := entries[1]
+	if diff := cmp.Diff(zapcore.ErrorLevel, access.Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	fields := access.ContextMap()
+	if diff := cmp.Diff(int64(http.StatusInternalServerError), fields["status"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(int64(len("internal error")), fields["bytes"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]any{"database is down"}, fields["errors"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// defaultGormSlowThreshold is a default duration above which queries are logged as slow, same as gorm's default.
+const defaultGormSlowThreshold = 200 * time.Millisecond
+
+// GormConfig is a configuration of the logger of gorm.
+type GormConfig struct {
+	// LogLevel is a level of gorm to filter messages. If zero, gormlogger.Warn is used, same as gorm's default.
+	LogLevel gormlogger.LogLevel
+
+	// SlowThreshold is a duration above which queries are logged as slow at warn level. If zero, 200ms is used.
+	SlowThreshold time.Duration
+
+	// IgnoreRecordNotFound reports whether queries failed with gorm.ErrRecordNotFound are not logged as errors.
+	IgnoreRecordNotFound bool
+}
+
+// gormLogger is an implementation of gormlogger.Interface backed by zap sugared logger.
+type gormLogger struct {
+	// logger is a logger to write entries. If nil, the logger from the context of each call is used.
+	logger *zap.SugaredLogger
+
+	config GormConfig
+}
+
+// NewGormLogger creates a logger of gorm which writes entries via given logger.
+// If logger is nil, the logger from the context given by gorm is used for each call, so that fields attached
+// to contexts of queries by WithFields are written. Queries are logged with "sql", "rows", and "duration" fields:
+// failed queries at error level, slow queries at warn level, and others at info level if gormlogger.Info is set.
+// Entries are annotated with the caller outside gorm as "source" field, instead of the caller of zap.
+func NewGormLogger(logger *zap.SugaredLogger, config GormConfig) gormlogger.Interface {
+	if config.LogLevel == 0 {
+		config.LogLevel = gormlogger.Warn
+	}
+	if config.SlowThreshold <= 0 {
+		config.SlowThreshold = defaultGormSlowThreshold
+	}
+	if logger != nil {
+		logger = logger.WithOptions(zap.WithCaller(false))
+	}
+	return &gormLogger{logger: logger, config: config}
+}
+
+// LogMode returns a new logger with given level of gorm.
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	config := l.config
+	config.LogLevel = level
+	return &gormLogger{logger: l.logger, config: config}
+}
+
+// Info writes given message at info level.
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...any) {
+	if l.config.LogLevel >= gormlogger.Info {
+		l.from(ctx).Infof(msg, data...)
+	}
+}
+
+// Warn writes given message at warn level.
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	if l.config.LogLevel >= gormlogger.Warn {
+		l.from(ctx).Warnf(msg, data...)
+	}
+}
+
+// Error writes given message at error level.
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...any) {
+	if l.config.LogLevel >= gormlogger.Error {
+		l.from(ctx).Errorf(msg, data...)
+	}
+}
+
+// Trace writes a query executed since begin.
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.config.LogLevel <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	fields := func() []any {
+		sql, rows := fc()
+		return []any{"sql", sql, "rows", rows, zap.Duration("duration", elapsed), "source", utils.FileWithLineNum()}
+	}
+	switch {
+	case err != nil && l.config.LogLevel >= gormlogger.Error && !(l.config.IgnoreRecordNotFound && errors.Is(err, gormlogger.ErrRecordNotFound)):
+		l.from(ctx).Errorw("query failed", append(fields(), Err(err))...)
+	case elapsed > l.config.SlowThreshold && l.config.LogLevel >= gormlogger.Warn:
+		l.from(ctx).Warnw("slow query", append(fields(), zap.Duration("threshold", l.config.SlowThreshold))...)
+	case l.config.LogLevel >= gormlogger.Info:
+		l.from(ctx).Infow("query", fields()...)
+	}
+}
+
+// from returns the logger to write entries of given context.
+func (l *gormLogger) from(ctx context.Context) *zap.SugaredLogger {
+	if l.logger != nil {
+		return l.logger
+	}
+	return FromContext(ctx).WithOptions(zap.WithCaller(false))
+}
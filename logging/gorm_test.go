@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestGormLogger(t *testing.T) {
+	t.Parallel()
+
+	query := func() (string, int64) { return "SELECT * FROM users", 2 }
+	cases := []struct {
+		name    string
+		config  GormConfig
+		begin   time.Duration
+		err     error
+		level   zapcore.Level
+		message string
+		logged  bool
+	}{
+		{name: "failed", err: errors.New("boom"), level: zapcore.ErrorLevel, message: "query failed", logged: true},
+		{name: "record not found ignored", config: GormConfig{IgnoreRecordNotFound: true}, err: gormlogger.ErrRecordNotFound},
+		{name: "slow", begin: time.Second, level: zapcore.WarnLevel, message: "slow query", logged: true},
+		{name: "fast", logged: false},
+		{name: "fast at info", config: GormConfig{LogLevel: gormlogger.Info}, level: zapcore.InfoLevel, message: "query", logged: true},
+		{name: "silent", config: GormConfig{LogLevel: gormlogger.Silent}, err: errors.New("boom")},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.name, func(t *testing.T) {
+			t.Parallel()
+
+			core, logs := observer.New(zap.DebugLevel)
+			logger := NewGormLogger(zap.New(core).Sugar(), cs.config)
+			logger.Trace(context.Background(), time.Now().Add(-cs.begin), query, cs.err)
+
+			entries := logs.AllUntimed()
+			if !cs.logged {
+				if len(entries) != 0 {
+					t.Errorf("expect no entry, but received %v", entries)
+				}
+				return
+			}
+			if len(entries) != 1 {
+				t.Fatalf("expect 1 entry, but received %d", len(entries))
+			}
+			if diff := cmp.Diff(cs.level, entries[0].Level); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(cs.message, entries[0].Message); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			fields := entries[0].ContextMap()
+			if diff := cmp.Diff("SELECT * FROM users", fields["sql"]); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(int64(2), fields["rows"]); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGormLoggerFromContext(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	ctx := WithFields(WithStructuredLogger(context.Background(), zap.New(core)), "request_id", "r1")
+	logger := NewGormLogger(nil, GormConfig{}).LogMode(gormlogger.Info)
+
+	logger.Info(ctx, "opened %s", "db")
+	logger.Warn(ctx, "retrying")
+	logger.Error(ctx, "failed")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("expect 3 entries, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("opened db", entries[0].Message); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	for _, entry := range entries {
+		if diff := cmp.Diff(map[string]any{"request_id": "r1"}, entry.ContextMap()); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+}
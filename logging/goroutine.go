@@ -0,0 +1,17 @@
+package logging
+
+import (
+	"context"
+)
+
+// Go runs given function in a new goroutine with given context, so that the goroutine logs via the logger and fields
+// of the parent, such as the request ID, instead of falling back to the default logger.
+// Panics of the function are recovered and logged like Recover, because they would crash the process otherwise.
+// The context is passed as it is, so that the function is canceled with the parent.
+// Use context.WithoutCancel to keep the logger while outliving the parent.
+func Go(ctx context.Context, fn func(ctx context.Context)) {
+	go func() {
+		defer Recover(ctx)
+		fn(ctx)
+	}()
+}
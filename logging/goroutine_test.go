@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestGo(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	written := make(chan struct{}, 2)
+	logger := zap.New(core, zap.Hooks(func(zapcore.Entry) error {
+		written <- struct{}{}
+		return nil
+	}))
+	ctx := WithFields(WithStructuredLogger(context.Background(), logger), "request_id", "r1")
+
+	Go(ctx, func(ctx context.Context) {
+		FromContext(ctx).Info("working")
+	})
+	Go(ctx, func(context.Context) {
+		panic("boom")
+	})
+	<-written
+	<-written
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, but received %d", len(entries))
+	}
+	for _, entry := range entries {
+		if diff := cmp.Diff("r1", entry.ContextMap()["request_id"]); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+	if diff := cmp.Diff(1, logs.FilterMessage("recovered from panic").Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
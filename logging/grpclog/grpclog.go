@@ -0,0 +1,231 @@
+// Package grpclog provides gRPC interceptors which attach per-RPC loggers and log completed RPCs.
+package grpclog
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aqyuki/util/errorsx"
+	"github.com/aqyuki/util/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDMetadata is a metadata key to propagate request IDs.
+const requestIDMetadata = "x-request-id"
+
+// Config is a configuration of the interceptors.
+type Config struct {
+	// Logger is a logger stored to contexts of RPCs via logging.WithLogger.
+	// If nil, the logger already stored to the context is used, or default logger.
+	Logger *zap.SugaredLogger
+
+	// CodeToLevel returns the level of logs of RPCs completed with given code. If nil, DefaultCodeToLevel is used.
+	CodeToLevel func(code codes.Code) zapcore.Level
+
+	// Skip reports whether the log of given method such as "/grpc.health.v1.Health/Check" is not written.
+	// Per-RPC loggers are attached regardless of it. If nil, every RPC is logged.
+	Skip func(fullMethod string) bool
+}
+
+// DefaultCodeToLevel returns info level for codes caused by clients, warn level for codes which may need attention,
+// and error level for codes caused by servers.
+func DefaultCodeToLevel(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.Unauthenticated:
+		return zapcore.InfoLevel
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted, codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// UnaryServerInterceptor returns an interceptor which attaches a per-RPC logger to the context,
+// with request_id, grpc.service, grpc.method, and peer.address fields, so that handlers can get it via logging.FromContext.
+// The request ID is taken from "x-request-id" metadata, or generated, and W3C traceparent and baggage are extracted.
+// When the handler returns, it writes a log with grpc.code and duration fields at the level of the code.
+// Errors of errorsx carry the gRPC code mapped from errorsx.Code, both in the log and in the response.
+// If the error has a public message attached by errorsx.WithPublicMessage, the response has only it,
+// while the log has the whole error.
+func UnaryServerInterceptor(config Config) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx = serverContext(ctx, config, info.FullMethod)
+		resp, err := handler(ctx, req)
+		logCompleted(ctx, config, info.FullMethod, "request completed", start, err)
+		return resp, publicError(err)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor which attaches a per-RPC logger to the context of the stream,
+// and logs completed streams in the same way as UnaryServerInterceptor.
+func StreamServerInterceptor(config Config) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := serverContext(ss.Context(), config, info.FullMethod)
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCompleted(ctx, config, info.FullMethod, "request completed", start, err)
+		return publicError(err)
+	}
+}
+
+// publicError returns a status error with the code of given error and its public message,
+// or given error itself if it has no public message.
+func publicError(err error) error {
+	if msg, ok := errorsx.PublicMessage(err); ok {
+		return status.Error(status.Code(err), msg)
+	}
+	return err
+}
+
+// UnaryClientInterceptor returns an interceptor which propagates the request ID of the context as "x-request-id" metadata
+// and the span and baggage of the context as W3C traceparent and baggage,
+// and writes a log of each call with grpc.service, grpc.method, grpc.code, and duration fields at the level of the code.
+func UnaryClientInterceptor(config Config) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		ctx = clientContext(ctx, config, method)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logCompleted(ctx, config, method, "call completed", start, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns an interceptor which propagates the request ID, the span, and baggage of the context,
+// and writes a log when the stream is established, in the same way as UnaryClientInterceptor.
+func StreamClientInterceptor(config Config) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		ctx = clientContext(ctx, config, method)
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		logCompleted(ctx, config, method, "stream opened", start, err)
+		return cs, err
+	}
+}
+
+// serverContext returns a context with the per-RPC logger of given method.
+func serverContext(ctx context.Context, config Config, fullMethod string) context.Context {
+	if config.Logger != nil {
+		ctx = logging.WithLogger(ctx, config.Logger)
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = ExtractMetadata(ctx, md)
+		if ids := md.Get(requestIDMetadata); len(ids) > 0 && ids[0] != "" {
+			ctx = logging.WithRequestID(ctx, ids[0])
+		}
+	}
+	ctx, _ = logging.EnsureRequestID(ctx)
+
+	fields := methodFields(fullMethod)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, "peer.address", p.Addr.String())
+	}
+	return logging.WithFields(ctx, fields...)
+}
+
+// clientContext returns a context with the request ID in outgoing metadata and the logger of given method.
+func clientContext(ctx context.Context, config Config, fullMethod string) context.Context {
+	if config.Logger != nil {
+		ctx = logging.WithLogger(ctx, config.Logger)
+	}
+	if id, ok := logging.RequestIDFromContext(ctx); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, requestIDMetadata, id)
+	}
+	ctx = InjectMetadata(ctx)
+	return logging.WithFields(ctx, methodFields(fullMethod)...)
+}
+
+// ExtractMetadata is same as logging.ExtractCorrelation, but it reads W3C traceparent and baggage of given metadata.
+// Server interceptors extract them from incoming metadata.
+func ExtractMetadata(ctx context.Context, md metadata.MD) context.Context {
+	return logging.ExtractCorrelation(ctx, metadataCarrier(md))
+}
+
+// InjectMetadata returns a context whose outgoing metadata has W3C traceparent and baggage of given context.
+// Client interceptors inject them into outgoing metadata.
+func InjectMetadata(ctx context.Context) context.Context {
+	md := metadata.MD{}
+	logging.InjectCorrelation(ctx, metadataCarrier(md))
+	if len(md) == 0 {
+		return ctx
+	}
+	pairs := make([]string, 0, 2*len(md))
+	for key, values := range md {
+		for _, value := range values {
+			pairs = append(pairs, key, value)
+		}
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// metadataCarrier is a propagation.TextMapCarrier of gRPC metadata.
+type metadataCarrier metadata.MD
+
+// Get returns the first value of given key.
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Set replaces values of given key with given value.
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys returns keys of the metadata.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// methodFields returns fields of the service and the method in given full method such as "/package.Service/Method".
+func methodFields(fullMethod string) []any {
+	service, method := path.Split(fullMethod)
+	return []any{"grpc.service", strings.Trim(service, "/"), "grpc.method", method}
+}
+
+// logCompleted writes a log of the RPC completed with given error.
+func logCompleted(ctx context.Context, config Config, fullMethod, msg string, start time.Time, err error) {
+	if config.Skip != nil && config.Skip(fullMethod) {
+		return
+	}
+	codeToLevel := config.CodeToLevel
+	if codeToLevel == nil {
+		codeToLevel = DefaultCodeToLevel
+	}
+
+	code := status.Code(err)
+	ce := logging.StructuredFromContext(ctx).Check(codeToLevel(code), msg)
+	if ce == nil {
+		return
+	}
+	fields := []zap.Field{zap.String("grpc.code", code.String()), zap.Duration("duration", time.Since(start))}
+	if err != nil {
+		fields = append(fields, logging.Err(err))
+	}
+	ce.Write(fields...)
+}
+
+// serverStream is a grpc.ServerStream whose context has the per-RPC logger.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context with the per-RPC logger.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
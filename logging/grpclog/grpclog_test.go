@@ -0,0 +1,257 @@
+package grpclog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/aqyuki/util/errorsx"
+	"github.com/aqyuki/util/logging"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	interceptor := UnaryServerInterceptor(Config{Logger: zap.New(core).Sugar()})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5000}})
+	info := &grpc.UnaryServerInfo{FullMethod: "/users.v1.UserService/GetUser"}
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+		logging.FromContext(ctx).Info("handling")
+		return nil, status.Error(codes.Internal, "boom")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expect internal error, but received %v", err)
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, but received %d", len(entries))
+	}
+	want := map[string]any{
+		"request_id":   "req-1",
+		"grpc.service": "users.v1.UserService",
+		"grpc.method":  "GetUser",
+		"peer.address": "127.0.0.1:5000",
+	}
+	if diff := cmp.Diff(want, entries[0].ContextMap()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	completed := entries[1]
+	if diff := cmp.Diff(zapcore.ErrorLevel, completed.Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	fields := completed.ContextMap()
+	if diff := cmp.Diff("Internal", fields["grpc.code"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	for _, key := range []string{"duration", "error"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("expect %s field, but not found", key)
+		}
+	}
+}
+
+func TestUnaryServerInterceptorWithErrorCode(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	interceptor := UnaryServerInterceptor(Config{Logger: zap.New(core).Sugar()})
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/users.v1.UserService/GetUser"}
+	_, err := interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, errorsx.WithCode(errorsx.New("user not found"), errorsx.NotFound)
+	})
+	if diff := cmp.Diff(codes.NotFound, status.Code(err)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	completed := logs.FilterMessage("request completed").AllUntimed()
+	if len(completed) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(completed))
+	}
+	if diff := cmp.Diff(zapcore.InfoLevel, completed[0].Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("NotFound", completed[0].ContextMap()["grpc.code"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestUnaryServerInterceptorWithPublicMessage(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	interceptor := UnaryServerInterceptor(Config{Logger: zap.New(core).Sugar()})
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/users.v1.UserService/GetUser"}
+	_, err := interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		err := errorsx.WithCode(errorsx.New("dial tcp 10.0.0.1:5432: connection refused"), errorsx.Unavailable)
+		return nil, fmt.Errorf("query failed: %w", errorsx.WithPublicMessage(err, "try again later"))
+	})
+	s := status.Convert(err)
+	if diff := cmp.Diff(codes.Unavailable, s.Code()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("try again later", s.Message()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	completed := logs.FilterMessage("request completed").AllUntimed()
+	if len(completed) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(completed))
+	}
+	logged := completed[0].ContextMap()["error"].(map[string]any)
+	if diff := cmp.Diff("query failed: dial tcp 10.0.0.1:5432: connection refused", logged["message"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+// fakeServerStream is a grpc.ServerStream which has only a context.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	interceptor := StreamServerInterceptor(Config{
+		Logger:      zap.New(core).Sugar(),
+		CodeToLevel: func(codes.Code) zapcore.Level { return zapcore.DebugLevel },
+	})
+
+	info := &grpc.StreamServerInfo{FullMethod: "/users.v1.UserService/WatchUsers"}
+	err := interceptor(nil, &fakeServerStream{ctx: context.Background()}, info, func(_ any, ss grpc.ServerStream) error {
+		logging.FromContext(ss.Context()).Info("streaming")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("WatchUsers", entries[0].ContextMap()["grpc.method"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if _, ok := entries[0].ContextMap()["request_id"]; !ok {
+		t.Error("expect generated request_id field, but not found")
+	}
+	if diff := cmp.Diff(zapcore.DebugLevel, entries[1].Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("OK", entries[1].ContextMap()["grpc.code"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	interceptor := UnaryClientInterceptor(Config{Logger: zap.New(core).Sugar()})
+
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	var outgoing metadata.MD
+	err := interceptor(ctx, "/users.v1.UserService/GetUser", nil, nil, nil, func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return status.Error(codes.NotFound, "missing")
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expect not found error, but received %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"req-1"}, outgoing.Get("x-request-id")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff(zapcore.InfoLevel, entries[0].Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("NotFound", entries[0].ContextMap()["grpc.code"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestMetadataCorrelation(t *testing.T) {
+	t.Parallel()
+
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	incoming := metadata.Pairs("traceparent", traceparent, "baggage", "tenant_id=acme")
+	ctx := ExtractMetadata(context.Background(), incoming)
+
+	outgoing, _ := metadata.FromOutgoingContext(InjectMetadata(ctx))
+	if diff := cmp.Diff([]string{traceparent}, outgoing.Get("traceparent")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"tenant_id=acme"}, outgoing.Get("baggage")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	plain := context.Background()
+	if got := InjectMetadata(plain); got != plain {
+		t.Error("expect given context without correlation, but received another")
+	}
+}
+
+func TestStreamClientInterceptorSkip(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	interceptor := StreamClientInterceptor(Config{
+		Logger: zap.New(core).Sugar(),
+		Skip:   func(method string) bool { return method == "/grpc.health.v1.Health/Watch" },
+	})
+
+	_, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/grpc.health.v1.Health/Watch", func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, errors.New("unavailable")
+	})
+	if err == nil {
+		t.Fatal("expect error, but received nil")
+	}
+	if logs.Len() != 0 {
+		t.Errorf("expect no entry, but received %d", logs.Len())
+	}
+}
+
+func TestDefaultCodeToLevel(t *testing.T) {
+	t.Parallel()
+
+	cases := map[codes.Code]zapcore.Level{
+		codes.OK:               zapcore.InfoLevel,
+		codes.NotFound:         zapcore.InfoLevel,
+		codes.DeadlineExceeded: zapcore.WarnLevel,
+		codes.Internal:         zapcore.ErrorLevel,
+		codes.Unavailable:      zapcore.ErrorLevel,
+	}
+	for code, want := range cases {
+		if diff := cmp.Diff(want, DefaultCodeToLevel(code)); diff != "" {
+			t.Errorf("%v: (-want, +got)\n%s", code, diff)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// Hook is a function called for each entry written by the logger.
+// It receives the entry and all fields of the entry, including fields added via With.
+type Hook func(ent zapcore.Entry, fields []zapcore.Field) error
+
+// WithHook registers given hooks called after entries are written.
+// Hooks are called only for entries passing level filtering, in registration order,
+// and they receive redacted and scrubbed fields. Errors of hooks are reported to the error output.
+func WithHook(hooks ...Hook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hooks...)
+	}
+}
+
+// hookCore is a zapcore.Core which calls hooks for entries written by the wrapped core.
+type hookCore struct {
+	zapcore.Core
+
+	hooks []Hook
+
+	// fields holds fields added via With, so that hooks can receive them.
+	fields []zapcore.Field
+}
+
+// newHookCore wraps given core, so that given hooks are called for written entries.
+func newHookCore(core zapcore.Core, hooks []Hook) zapcore.Core {
+	return &hookCore{Core: core, hooks: append([]Hook(nil), hooks...)}
+}
+
+// With returns a child core with given fields.
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &hookCore{Core: c.Core.With(fields), hooks: c.hooks, fields: merged}
+}
+
+// Check asks the wrapped core whether given entry should be written,
+// and if so, adds a writer which calls hooks after the wrapped core writes the entry.
+func (c *hookCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	downstream := c.Core.Check(ent, nil)
+	if downstream == nil {
+		return ce
+	}
+	w := &hookWriter{Core: c.Core, core: c, downstream: downstream}
+	w.outer = ce.AddCore(ent, w)
+	return w.outer
+}
+
+// hookWriter is a zapcore.Core added to a checked entry by hookCore.
+type hookWriter struct {
+	zapcore.Core
+
+	core *hookCore
+
+	// downstream is a checked entry of the wrapped core.
+	downstream *zapcore.CheckedEntry
+
+	// outer is a checked entry of the logger which this writer is added to.
+	outer *zapcore.CheckedEntry
+}
+
+// Write writes given entry to the wrapped core, and then calls hooks.
+func (w *hookWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	w.downstream.Entry = ent
+	w.downstream.ErrorOutput = w.outer.ErrorOutput
+	w.downstream.Write(fields...)
+
+	all := fields
+	if len(w.core.fields) > 0 {
+		all = make([]zapcore.Field, 0, len(w.core.fields)+len(fields))
+		all = append(all, w.core.fields...)
+		all = append(all, fields...)
+	}
+
+	var err error
+	for _, hook := range w.core.hooks {
+		err = multierr.Append(err, hook(ent, all))
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithHook(t *testing.T) {
+	t.Parallel()
+
+	var messages []string
+	var contexts []map[string]any
+	record := func(ent zapcore.Entry, fields []zapcore.Field) error {
+		enc := zapcore.NewMapObjectEncoder()
+		for _, field := range fields {
+			field.AddTo(enc)
+		}
+		messages = append(messages, ent.Message)
+		contexts = append(contexts, enc.Fields)
+		return nil
+	}
+	var count int
+	counter := func(zapcore.Entry, []zapcore.Field) error {
+		count++
+		return nil
+	}
+
+	buf := &zaptest.Buffer{}
+	logger := NewStructuredLogger(
+		WithLevel("info"),
+		WithWriteSyncer(buf),
+		WithRedaction(NewRedactor(nil)),
+		WithHook(record),
+		WithHook(counter),
+	).With(zap.String("service", "test"))
+
+	logger.Debug("ignored")
+	logger.Info("hooked", zap.String("token", "t-1"))
+
+	if diff := cmp.Diff([]string{"hooked"}, messages); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	want := []map[string]any{{"service": "test", "token": "[REDACTED]"}}
+	if diff := cmp.Diff(want, contexts); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(1, count); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(1, len(buf.Lines())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestWithHookError(t *testing.T) {
+	t.Parallel()
+
+	errOutput := &zaptest.Buffer{}
+	logger := NewStructuredLogger(
+		WithWriteSyncer(&zaptest.Buffer{}),
+		WithHook(func(zapcore.Entry, []zapcore.Field) error { return errors.New("hook failed") }),
+	).WithOptions(zap.ErrorOutput(errOutput))
+
+	logger.Info("message")
+
+	if len(errOutput.Lines()) != 1 {
+		t.Fatalf("expect 1 error line, but received %v", errOutput.Lines())
+	}
+}
@@ -0,0 +1,25 @@
+package httplog
+
+import (
+	"net/http"
+
+	"github.com/aqyuki/util/errorsx"
+	"github.com/aqyuki/util/logging"
+)
+
+// WriteError writes the response of given error returned by a handler, with the HTTP status mapped from errorsx.Code,
+// such as 404 for errorsx.NotFound and 500 for errors without a code, and the public message attached by
+// errorsx.WithPublicMessage as the body, or the status text if none is attached.
+// The error is logged with logging.Err via the logger of the request context, at the same level as the access log,
+// so that details of the error are not exposed to clients.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	status := errorsx.HTTPStatus(err)
+	if ce := logging.StructuredFromContext(r.Context()).Check(statusLevel(status), "request failed"); ce != nil {
+		ce.Write(logging.Err(err))
+	}
+	msg, ok := errorsx.PublicMessage(err)
+	if !ok {
+		msg = http.StatusText(status)
+	}
+	http.Error(w, msg, status)
+}
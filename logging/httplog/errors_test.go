@@ -0,0 +1,62 @@
+package httplog
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aqyuki/util/errorsx"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWriteError(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	handler := Middleware(Config{Logger: zap.New(core).Sugar()})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := errorsx.WithCode(errorsx.New("user 1 not found in shard-3"), errorsx.NotFound)
+		WriteError(w, r, fmt.Errorf("lookup: %w", err))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	if diff := cmp.Diff(http.StatusNotFound, rec.Code); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("Not Found\n", rec.Body.String()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	entries := logs.FilterMessage("request failed").AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff(zapcore.WarnLevel, entries[0].Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if _, ok := entries[0].ContextMap()["error"]; !ok {
+		t.Error("expect error field, but not found")
+	}
+}
+
+func TestWriteErrorWithPublicMessage(t *testing.T) {
+	t.Parallel()
+
+	err := errorsx.WithCode(errorsx.New("dial tcp 10.0.0.1:5432: connection refused"), errorsx.Unavailable)
+	err = errorsx.WithPublicMessage(err, "try again later")
+
+	rec := httptest.NewRecorder()
+	WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil), fmt.Errorf("query failed: %w", err))
+
+	if diff := cmp.Diff(http.StatusServiceUnavailable, rec.Code); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("try again later\n", rec.Body.String()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
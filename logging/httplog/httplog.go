@@ -0,0 +1,149 @@
+// Package httplog provides net/http middleware which attaches request-scoped loggers, writes access logs, and recovers panics.
+// The middleware is a func(http.Handler) http.Handler, so that it works with net/http, chi, and any stdlib-compatible router.
+package httplog
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/aqyuki/util/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultRequestIDHeader is a default header to propagate request IDs.
+const defaultRequestIDHeader = "X-Request-ID"
+
+// Config is a configuration of the middleware.
+type Config struct {
+	// Logger is a logger stored to request contexts via logging.WithLogger.
+	// If nil, the logger already stored to the request context is used, or default logger.
+	Logger *zap.SugaredLogger
+
+	// RequestIDHeader is a header to read request IDs from requests and write them to responses.
+	// If the request has no request ID, a new one is generated. If empty, "X-Request-ID" is used.
+	RequestIDHeader string
+
+	// Skip reports whether the access log of given request is not written, such as health checks.
+	// Request-scoped loggers are attached regardless of it. If nil, every request is logged.
+	Skip func(r *http.Request) bool
+}
+
+// Middleware returns a middleware which attaches a request-scoped logger to the request context,
+// with request_id, method, path, and remote_addr fields, so that handlers can get it via logging.FromContext.
+// W3C traceparent and baggage headers are extracted via logging.ExtractHTTP.
+// When the handler returns, it writes an access log with status, bytes, and duration fields,
+// at error level for 5xx responses, warn level for 4xx responses, and info level otherwise.
+func Middleware(config Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			r = r.WithContext(RequestContext(w, r, config))
+			rw := &responseWriter{ResponseWriter: w}
+			next.ServeHTTP(rw, r)
+
+			if config.Skip != nil && config.Skip(r) {
+				return
+			}
+			status := rw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			LogAccess(r.Context(), status, rw.bytes, time.Since(start))
+		})
+	}
+}
+
+// RequestContext returns the context of given request with the request-scoped logger attached by Middleware,
+// and writes the request ID to the response header. It is intended for adapters of other frameworks.
+func RequestContext(w http.ResponseWriter, r *http.Request, config Config) context.Context {
+	header := config.RequestIDHeader
+	if header == "" {
+		header = defaultRequestIDHeader
+	}
+
+	ctx := logging.ExtractHTTP(r.Context(), r.Header)
+	if config.Logger != nil {
+		ctx = logging.WithLogger(ctx, config.Logger)
+	}
+	if id := r.Header.Get(header); id != "" {
+		ctx = logging.WithRequestID(ctx, id)
+	}
+	ctx, id := logging.EnsureRequestID(ctx)
+	w.Header().Set(header, id)
+	return logging.WithFields(ctx, "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+}
+
+// LogAccess writes an access log written by Middleware, via the logger of given context.
+// It is intended for adapters of other frameworks.
+func LogAccess(ctx context.Context, status int, bytes int64, duration time.Duration) {
+	if ce := logging.StructuredFromContext(ctx).Check(statusLevel(status), "request completed"); ce != nil {
+		ce.Write(zap.Int("status", status), zap.Int64("bytes", bytes), zap.Duration("duration", duration))
+	}
+}
+
+// statusLevel returns the level of access logs of given status code.
+func statusLevel(status int) zapcore.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return zapcore.ErrorLevel
+	case status >= http.StatusBadRequest:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// responseWriter is a http.ResponseWriter which records the status code and the number of written bytes.
+type responseWriter struct {
+	http.ResponseWriter
+
+	// status is a status code written by the handler. It is zero until the header is written.
+	status int
+
+	// bytes is a number of bytes of the body written by the handler.
+	bytes int64
+}
+
+// WriteHeader records given status code and writes it.
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of written bytes and writes given bytes.
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush flushes the underlying writer if it supports flushing, for streaming responses.
+func (w *responseWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack hijacks the connection of the underlying writer, for protocols such as WebSocket.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the underlying writer, so that http.ResponseController can reach its features.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,118 @@
+package httplog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aqyuki/util/logging"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	handler := Middleware(Config{Logger: zap.New(core).Sugar()})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("handling")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not found"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if diff := cmp.Diff("req-1", rec.Header().Get("X-Request-ID")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, but received %d", len(entries))
+	}
+	want := map[string]any{"request_id": "req-1", "method": "GET", "path": "/users/1", "remote_addr": req.RemoteAddr}
+	if diff := cmp.Diff(want, entries[0].ContextMap()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	access := entries[1]
+	if diff := cmp.Diff(zapcore.WarnLevel, access.Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	fields := access.ContextMap()
+	if diff := cmp.Diff(int64(http.StatusNotFound), fields["status"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(int64(len("not found")), fields["bytes"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if _, ok := fields["duration"]; !ok {
+		t.Error("expect duration field, but not found")
+	}
+}
+
+func TestMiddlewareGeneratesRequestID(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	handler := Middleware(Config{Logger: zap.New(core).Sugar(), RequestIDHeader: "X-Correlation-ID"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	id := rec.Header().Get("X-Correlation-ID")
+	if id == "" {
+		t.Fatal("expect generated request ID, but received empty")
+	}
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff(zapcore.InfoLevel, entries[0].Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(id, entries[0].ContextMap()["request_id"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(int64(http.StatusOK), entries[0].ContextMap()["status"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestMiddlewareSkip(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	handler := Middleware(Config{
+		Logger: zap.New(core).Sugar(),
+		Skip:   func(r *http.Request) bool { return r.URL.Path == "/healthz" },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if logs.Len() != 0 {
+		t.Errorf("expect no entry, but received %d", logs.Len())
+	}
+}
+
+func TestResponseWriterFlush(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	w := &responseWriter{ResponseWriter: rec}
+	http.NewResponseController(w).Flush()
+
+	if !rec.Flushed {
+		t.Error("expect the underlying writer to be flushed, but not flushed")
+	}
+	if diff := cmp.Diff(http.StatusOK, w.status); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
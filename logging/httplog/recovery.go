@@ -0,0 +1,52 @@
+package httplog
+
+import (
+	"net/http"
+
+	"github.com/aqyuki/util/logging"
+	"go.uber.org/zap"
+)
+
+// RecoveryConfig is a configuration of the recovery middleware.
+type RecoveryConfig struct {
+	// Handler writes the response after a panic, if the handler has not written the header yet.
+	// If nil, 500 Internal Server Error is written with its status text.
+	Handler http.Handler
+}
+
+// Recovery returns a middleware which recovers panics of the handler, logs them at error level with the stack trace
+// via the logger of the request context, and writes the response of config.Handler.
+// Put it inside Middleware, so that the panic is logged with request-scoped fields and the access log has the status.
+// Panics with http.ErrAbortHandler are not recovered, because they are used to abort responses.
+func Recovery(config RecoveryConfig) func(http.Handler) http.Handler {
+	respond := config.Handler
+	if respond == nil {
+		respond = http.HandlerFunc(internalServerError)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := &responseWriter{ResponseWriter: w}
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				// skip this function, so that the stack trace starts from the panic.
+				logging.StructuredFromContext(r.Context()).Error("recovered from panic", zap.Any("panic", v), zap.StackSkip("panic_stack", 1))
+				if rw.status == 0 {
+					respond.ServeHTTP(rw, r)
+				}
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// internalServerError writes 500 Internal Server Error.
+func internalServerError(w http.ResponseWriter, _ *http.Request) {
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
@@ -0,0 +1,94 @@
+package httplog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecovery(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	panicking := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+	handler := Middleware(Config{Logger: zap.New(core).Sugar()})(Recovery(RecoveryConfig{})(panicking))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if diff := cmp.Diff(http.StatusInternalServerError, rec.Code); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, but received %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if diff := cmp.Diff("boom", fields["panic"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("req-1", fields["request_id"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if stack, _ := fields["panic_stack"].(string); !strings.Contains(stack, "TestRecovery") {
+		t.Errorf("expect stack trace of the panic, but received %q", stack)
+	}
+
+	access := entries[1]
+	if diff := cmp.Diff(zapcore.ErrorLevel, access.Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(int64(http.StatusInternalServerError), access.ContextMap()["status"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestRecoveryHandler(t *testing.T) {
+	t.Parallel()
+
+	core, _ := observer.New(zap.DebugLevel)
+	respond := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error":"internal"}`))
+	})
+	handler := Middleware(Config{Logger: zap.New(core).Sugar()})(Recovery(RecoveryConfig{Handler: respond})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if diff := cmp.Diff(http.StatusServiceUnavailable, rec.Code); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(`{"error":"internal"}`, rec.Body.String()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestRecoveryAbortHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := Recovery(RecoveryConfig{})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("expect %v to be re-panicked, but received %v", http.ErrAbortHandler, v)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
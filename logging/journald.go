@@ -0,0 +1,203 @@
+package logging
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// journalSocket is a path of the socket of systemd journal.
+const journalSocket = "/run/systemd/journal/socket"
+
+// WithJournald writes entries directly to systemd journal, in addition to the logger's own output.
+// Entries are filtered at the logger's level and component levels.
+func WithJournald() Option {
+	return func(o *options) {
+		o.leveledCores = append(o.leveledCores, NewJournaldCore())
+	}
+}
+
+// withoutDefaultOutput disables default paths of the mode, so that entries are written only to other destinations.
+func withoutDefaultOutput() Option {
+	return func(o *options) {
+		o.defaultOutputDisabled = true
+	}
+}
+
+// journalAvailable reports whether the process runs under systemd with its output connected to the journal.
+func journalAvailable() bool {
+	if os.Getenv("JOURNAL_STREAM") == "" {
+		return false
+	}
+	_, err := os.Stat(journalSocket)
+	return err == nil
+}
+
+// journaldCore is a zapcore.Core which writes entries to systemd journal via its native protocol.
+type journaldCore struct {
+	w      *journaldWriter
+	fields []zapcore.Field
+}
+
+// NewJournaldCore creates a core which writes every entry to systemd journal.
+// PRIORITY is set from the level of entries, and fields are written as journal fields
+// with their keys converted to upper case, such as "request_id" to "REQUEST_ID".
+// Values which are not strings are written as JSON.
+func NewJournaldCore() zapcore.Core {
+	return newJournaldCore(journalSocket)
+}
+
+// newJournaldCore creates a core which writes entries to the journal socket at given path.
+func newJournaldCore(path string) zapcore.Core {
+	return &journaldCore{w: &journaldWriter{path: path, identifier: filepath.Base(os.Args[0])}}
+}
+
+// Enabled reports true for every level.
+func (c *journaldCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With returns a child core with given fields.
+func (c *journaldCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &journaldCore{w: c.w, fields: merged}
+}
+
+// Check adds the core to given checked entry.
+func (c *journaldCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+// Write converts given entry to journal fields and sends them to the journal.
+func (c *journaldCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(enc)
+	}
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	buf := journaldPool.Get()
+	defer buf.Free()
+
+	appendJournalField(buf, "MESSAGE", ent.Message)
+	appendJournalField(buf, "PRIORITY", strconv.Itoa(syslogSeverity(ent.Level)))
+	appendJournalField(buf, "SYSLOG_IDENTIFIER", c.w.identifier)
+	if ent.LoggerName != "" {
+		appendJournalField(buf, "LOGGER", ent.LoggerName)
+	}
+	if ent.Caller.Defined {
+		appendJournalField(buf, "CODE_FILE", ent.Caller.File)
+		appendJournalField(buf, "CODE_LINE", strconv.Itoa(ent.Caller.Line))
+		appendJournalField(buf, "CODE_FUNC", ent.Caller.Function)
+	}
+	if ent.Stack != "" {
+		appendJournalField(buf, "STACKTRACE", ent.Stack)
+	}
+	for k, v := range enc.Fields {
+		key := journalFieldName(k)
+		if key == "" {
+			continue
+		}
+		appendJournalField(buf, key, journalValue(v))
+	}
+
+	return c.w.write(buf.Bytes())
+}
+
+// Sync does nothing, because entries are written without buffering.
+func (c *journaldCore) Sync() error {
+	return nil
+}
+
+// journaldPool is a pool of buffers used to format journal entries.
+var journaldPool = buffer.NewPool()
+
+// journaldWriter sends datagrams to the journal socket.
+type journaldWriter struct {
+	path       string
+	identifier string
+
+	// mu guards conn.
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// write sends given serialized fields to the journal. If sending fails, it reconnects and retries once.
+func (w *journaldWriter) write(b []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil {
+			if w.conn, err = net.Dial("unixgram", w.path); err != nil {
+				return fmt.Errorf("logging: failed to connect to journal: %w", err)
+			}
+		}
+		if _, err = w.conn.Write(b); err == nil {
+			return nil
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	return fmt.Errorf("logging: failed to write to journal: %w", err)
+}
+
+// appendJournalField serializes given field in the native journal protocol.
+// Values containing line breaks are written with their length in binary.
+func appendJournalField(buf *buffer.Buffer, key, value string) {
+	buf.AppendString(key)
+	if !strings.ContainsRune(value, '\n') {
+		buf.AppendByte('=')
+		buf.AppendString(value)
+		buf.AppendByte('\n')
+		return
+	}
+	buf.AppendByte('\n')
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	_, _ = buf.Write(size[:])
+	buf.AppendString(value)
+	buf.AppendByte('\n')
+}
+
+// journalFieldName converts given key to a journal field name,
+// which consists of upper case letters, digits, and underscores, and does not start with an underscore or digit.
+// If no valid character remains, it will return an empty string.
+func journalFieldName(key string) string {
+	b := make([]byte, 0, len(key))
+	for _, r := range strings.ToUpper(key) {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9' && len(b) > 0:
+			b = append(b, byte(r))
+		case len(b) > 0:
+			b = append(b, '_')
+		}
+	}
+	return strings.TrimRight(string(b), "_")
+}
+
+// journalValue converts a value encoded by zapcore.MapObjectEncoder to a string.
+func journalValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
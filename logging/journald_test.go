@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestJournaldCore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	defer conn.Close()
+
+	core := newJournaldCore(path).With([]zapcore.Field{zap.String("request_id", "r-1")})
+	ent := zapcore.Entry{Level: zapcore.WarnLevel, Time: time.Now(), LoggerName: "worker", Message: "line1\nline2"}
+	if err := core.Write(ent, []zapcore.Field{zap.Int("attempt", 3)}); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len("line1\nline2")))
+	want := "MESSAGE\n" + string(size[:]) + "line1\nline2\n" +
+		"PRIORITY=4\n"
+	if got := string(buf[:n]); len(got) < len(want) || got[:len(want)] != want {
+		t.Fatalf("expect prefix %q, but received %q", want, got)
+	}
+	fields := string(buf[len(want):n])
+	for _, field := range []string{"LOGGER=worker\n", "REQUEST_ID=r-1\n", "ATTEMPT=3\n"} {
+		if !strings.Contains(fields, field) {
+			t.Errorf("expect field %q, but received %q", field, fields)
+		}
+	}
+}
+
+func TestJournalFieldName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"request_id":  "REQUEST_ID",
+		"http.status": "HTTP_STATUS",
+		"_private":    "PRIVATE",
+		"1st":         "ST",
+		"":            "",
+	}
+	for key, want := range tests {
+		if diff := cmp.Diff(want, journalFieldName(key)); diff != "" {
+			t.Errorf("%q: (-want, +got)\n%s", key, diff)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package logging
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// kafkaTimeout is a timeout of writing a batch of messages.
+const kafkaTimeout = 10 * time.Second
+
+// KafkaMessage is a message published to Kafka.
+type KafkaMessage struct {
+	// Key is a key of the message used to select a partition. It is nil if no key field is found.
+	Key []byte
+
+	// Value is a JSON encoded entry.
+	Value []byte
+}
+
+// KafkaWriter publishes messages to a Kafka topic.
+// It is implemented by a thin adapter of a Kafka client, such as a wrapper of kafka-go's Writer.
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, messages ...KafkaMessage) error
+}
+
+// KafkaConfig is a configuration of Kafka destination.
+type KafkaConfig struct {
+	// Writer publishes messages to the topic.
+	Writer KafkaWriter
+
+	// KeyField is a key of the field used as the key of messages, such as "tenant_id".
+	// If empty or the field is not found, messages have no key.
+	KeyField string
+
+	// QueueSize is a maximum number of entries waiting to be published. If zero, 8192 is used.
+	QueueSize int
+
+	// BatchSize is a maximum number of entries published at once. If zero, 256 is used.
+	BatchSize int
+
+	// FlushInterval is an interval to publish queued entries. If zero, one second is used.
+	FlushInterval time.Duration
+
+	// Block reports whether logging blocks while the queue is full.
+	// If false, entries are dropped and counted as log_entries_dropped_total{sink="kafka"}.
+	Block bool
+
+	// Spool persists batches which could not be delivered to disk, and replays them when Kafka is reachable again.
+	// If nil, such batches are dropped.
+	Spool *SpoolConfig
+}
+
+// WithKafka publishes entries to Kafka as JSON, in addition to the logger's own output.
+// Entries are filtered at the logger's level and component levels.
+func WithKafka(config KafkaConfig) Option {
+	return func(o *options) {
+		o.leveledCores = append(o.leveledCores, NewKafkaCore(config))
+	}
+}
+
+// kafkaCore is a zapcore.Core which publishes entries to Kafka.
+type kafkaCore struct {
+	enc      zapcore.Encoder
+	keyField string
+
+	// key is a value of the key field given to With.
+	key []byte
+
+	batcher *batcher[KafkaMessage]
+}
+
+// NewKafkaCore creates a core which publishes every entry to Kafka as JSON.
+// Entries are queued and published in batches from a background goroutine.
+// Sync publishes queued entries and waits for it.
+func NewKafkaCore(config KafkaConfig) zapcore.Core {
+	writer := config.Writer
+	var s *spool[KafkaMessage]
+	if config.Spool != nil {
+		s = newSpool(*config.Spool, "kafka", encodeKafkaMessage, decodeKafkaMessage)
+	}
+	b := newSpoolingBatcher(batcherConfig{
+		sink:      "kafka",
+		queueSize: config.QueueSize,
+		batchSize: config.BatchSize,
+		interval:  config.FlushInterval,
+		block:     config.Block,
+	}, func(messages []KafkaMessage) error {
+		ctx, cancel := context.WithTimeout(context.Background(), kafkaTimeout)
+		defer cancel()
+		if err := writer.WriteMessages(ctx, messages...); err != nil {
+			return fmt.Errorf("logging: failed to publish %d entries to kafka: %w", len(messages), err)
+		}
+		return nil
+	}, s)
+	return &kafkaCore{
+		enc:      zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		keyField: config.KeyField,
+		batcher:  b,
+	}
+}
+
+// Enabled reports true for every level.
+func (c *kafkaCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With returns a child core with given fields.
+func (c *kafkaCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	key := c.key
+	if k, ok := c.findKey(fields); ok {
+		key = k
+	}
+	return &kafkaCore{enc: enc, keyField: c.keyField, key: key, batcher: c.batcher}
+}
+
+// Check adds the core to given checked entry.
+func (c *kafkaCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+// Write encodes given entry as JSON and queues it.
+func (c *kafkaCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	value := append([]byte(nil), trimLineEnding(buf.Bytes())...)
+	buf.Free()
+
+	key := c.key
+	if k, ok := c.findKey(fields); ok {
+		key = k
+	}
+	c.batcher.add(KafkaMessage{Key: key, Value: value})
+	return nil
+}
+
+// Sync publishes queued entries and waits for it.
+func (c *kafkaCore) Sync() error {
+	return c.batcher.flush()
+}
+
+// Close publishes queued entries and stops the background goroutine, or gives up when given context is done.
+func (c *kafkaCore) Close(ctx context.Context) error {
+	return closeWithContext(ctx, c.batcher.close)
+}
+
+// findKey returns the value of the key field in given fields.
+// If the field appears more than once, the last one wins.
+func (c *kafkaCore) findKey(fields []zapcore.Field) ([]byte, bool) {
+	if c.keyField == "" {
+		return nil, false
+	}
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key != c.keyField {
+			continue
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		fields[i].AddTo(enc)
+		if v, ok := enc.Fields[c.keyField]; ok {
+			return []byte(fmt.Sprint(v)), true
+		}
+	}
+	return nil, false
+}
+
+// encodeKafkaMessage encodes given message for spool, as the length of the key plus one, the key, and the value.
+// The length is zero if the message has no key.
+func encodeKafkaMessage(m KafkaMessage) []byte {
+	b := make([]byte, 0, binary.MaxVarintLen64+len(m.Key)+len(m.Value))
+	if m.Key == nil {
+		b = binary.AppendUvarint(b, 0)
+	} else {
+		b = binary.AppendUvarint(b, uint64(len(m.Key))+1)
+		b = append(b, m.Key...)
+	}
+	return append(b, m.Value...)
+}
+
+// decodeKafkaMessage decodes a message encoded by encodeKafkaMessage.
+func decodeKafkaMessage(b []byte) (KafkaMessage, error) {
+	n, read := binary.Uvarint(b)
+	if read <= 0 || n > uint64(len(b)-read)+1 {
+		return KafkaMessage{}, errors.New("logging: malformed spooled kafka message")
+	}
+	b = b[read:]
+	var m KafkaMessage
+	if n > 0 {
+		m.Key = make([]byte, n-1)
+		copy(m.Key, b)
+		b = b[n-1:]
+	}
+	m.Value = append([]byte(nil), b...)
+	return m, nil
+}
@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+)
+
+// kafkaRecorder is a KafkaWriter which records written messages.
+type kafkaRecorder struct {
+	mu       sync.Mutex
+	messages []KafkaMessage
+}
+
+func (r *kafkaRecorder) WriteMessages(_ context.Context, messages ...KafkaMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, messages...)
+	return nil
+}
+
+func TestWithKafka(t *testing.T) {
+	t.Parallel()
+
+	recorder := &kafkaRecorder{}
+	logger := NewLogger(WithWriteSyncer(&zaptest.Buffer{}), WithKafka(KafkaConfig{Writer: recorder, KeyField: "tenant"}))
+	logger.With("tenant", "t-1").Info("first")
+	logger.With("tenant", "t-1").Infow("second", "tenant", "t-2")
+	logger.Info("third")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.messages) != 3 {
+		t.Fatalf("expect 3 messages, but received %d", len(recorder.messages))
+	}
+
+	keys := make([]string, 0, len(recorder.messages))
+	for _, message := range recorder.messages {
+		keys = append(keys, string(message.Key))
+	}
+	if diff := cmp.Diff([]string{"t-1", "t-2", ""}, keys); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(recorder.messages[0].Value, &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	if diff := cmp.Diff("first", entry["msg"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestKafkaMessageCodec(t *testing.T) {
+	t.Parallel()
+
+	for _, m := range []KafkaMessage{
+		{Key: []byte("tenant"), Value: []byte(`{"msg":"keyed"}`)},
+		{Key: []byte{}, Value: []byte(`{"msg":"empty key"}`)},
+		{Value: []byte(`{"msg":"no key"}`)},
+	} {
+		got, err := decodeKafkaMessage(encodeKafkaMessage(m))
+		if err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+		if diff := cmp.Diff(m, got); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+	if _, err := decodeKafkaMessage([]byte{10, 'a'}); err == nil {
+		t.Error("expect an error of malformed message, but received nil")
+	}
+}
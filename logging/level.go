@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AtomicLevel returns the atomic level used by default logger.
+// Changing the returned level changes verbosity of default logger at runtime.
+func AtomicLevel() zap.AtomicLevel {
+	return loadDefaults().level
+}
+
+// Level returns the current level of default logger.
+func Level() zapcore.Level {
+	return AtomicLevel().Level()
+}
+
+// SetLevel changes the level of default logger at runtime.
+// If given level can not be parsed, it will return an error and the level is not changed.
+func SetLevel(level string) error {
+	lvl, ok := parseLevel(level)
+	if !ok {
+		return fmt.Errorf("logging: unknown level %q", level)
+	}
+	AtomicLevel().SetLevel(lvl)
+	return nil
+}
+
+// LevelHandler returns a http.Handler to get or change the level of default logger.
+// GET request returns the current level, and PUT request changes the level.
+// See zap.AtomicLevel.ServeHTTP for the request and response format.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		AtomicLevel().ServeHTTP(w, r)
+	})
+}
+
+// DebugFor switches default logger to debug level for given duration, and returns it to the current level after that.
+// Entries marking the start and the end of the window are written at info level.
+// Calling it again during a window extends the window, and SIGUSR2 handled by HandleSignals ends it early.
+func DebugFor(d time.Duration) {
+	debugToggle.mu.Lock()
+	defer debugToggle.mu.Unlock()
+
+	if !debugToggle.enabled {
+		debugToggle.enabled = true
+		debugToggle.previous = Level()
+		AtomicLevel().SetLevel(zapcore.DebugLevel)
+	}
+	if debugToggle.window != nil {
+		debugToggle.window.Stop()
+	}
+	debugToggle.generation++
+	generation := debugToggle.generation
+	debugToggle.window = time.AfterFunc(d, func() {
+		debugToggle.mu.Lock()
+		defer debugToggle.mu.Unlock()
+		if debugToggle.generation == generation {
+			restoreLevelLocked()
+		}
+	})
+	DefaultStructuredLogger().Info("debug window started", zap.Duration("duration", d), zap.Time("until", time.Now().Add(d)))
+}
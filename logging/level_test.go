@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSetLevel(t *testing.T) {
+	original := Level()
+	t.Cleanup(func() {
+		AtomicLevel().SetLevel(original)
+	})
+
+	if err := SetLevel("debug"); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff(zap.DebugLevel, Level()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if !DefaultLogger().Desugar().Core().Enabled(zap.DebugLevel) {
+		t.Error("expect default logger enables debug level, but disabled")
+	}
+
+	if err := SetLevel("error"); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if DefaultLogger().Desugar().Core().Enabled(zap.WarnLevel) {
+		t.Error("expect default logger disables warn level, but enabled")
+	}
+
+	if err := SetLevel("unknown"); err == nil {
+		t.Error("expect error, but received nil")
+	}
+	if diff := cmp.Diff(zap.ErrorLevel, Level()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	original := Level()
+	t.Cleanup(func() {
+		AtomicLevel().SetLevel(original)
+	})
+
+	handler := LevelHandler()
+
+	req := httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(`{"level":"warn"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if diff := cmp.Diff(http.StatusOK, rec.Code); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(zap.WarnLevel, Level()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/log/level", nil))
+	if diff := cmp.Diff(`{"level":"warn"}`, strings.TrimSpace(rec.Body.String())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+// TestDebugFor must not run in parallel, because it changes the level and the logger of default logger.
+func TestDebugFor(t *testing.T) {
+	original, originalLogger := Level(), DefaultLogger()
+	t.Cleanup(func() {
+		AtomicLevel().SetLevel(original)
+		SetDefault(originalLogger)
+	})
+	AtomicLevel().SetLevel(zap.WarnLevel)
+	core, logs := observer.New(zap.DebugLevel)
+	SetDefault(zap.New(core).Sugar())
+
+	DebugFor(time.Hour)
+	DebugFor(50 * time.Millisecond)
+	if diff := cmp.Diff(zap.DebugLevel, Level()); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for Level() != zap.WarnLevel && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if diff := cmp.Diff(zap.WarnLevel, Level()); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+
+	var messages []string
+	for _, entry := range logs.AllUntimed() {
+		messages = append(messages, entry.Message)
+	}
+	want := []string{"debug window started", "debug window started", "debug window ended"}
+	if diff := cmp.Diff(want, messages); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
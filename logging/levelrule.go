@@ -0,0 +1,168 @@
+package logging
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelRule changes the level of entries which have a field of given value,
+// such as demoting entries with component=healthcheck to debug.
+type LevelRule struct {
+	// Field is a key of the field to match.
+	Field string
+
+	// Value is a value of the field to match, compared with the value formatted by fmt.Sprint.
+	Value string
+
+	// Level is a level of matched entries. Levels higher than error are treated as error,
+	// because panic and fatal hooks of the logger do not run for entries whose level is changed.
+	Level zapcore.Level
+}
+
+// level returns the level of matched entries, capped at error level.
+func (r LevelRule) level() zapcore.Level {
+	return min(r.Level, zapcore.ErrorLevel)
+}
+
+// LevelRules is a list of level rules which can be changed at runtime.
+// Rules are evaluated in order, and the first matched rule wins.
+type LevelRules struct {
+	rules atomic.Pointer[[]LevelRule]
+}
+
+// NewLevelRules creates LevelRules with given rules.
+func NewLevelRules(rules ...LevelRule) *LevelRules {
+	r := &LevelRules{}
+	r.Set(rules...)
+	return r
+}
+
+// Set replaces rules at runtime. Loggers created with the rules are affected immediately.
+func (r *LevelRules) Set(rules ...LevelRule) {
+	rules = append([]LevelRule(nil), rules...)
+	r.rules.Store(&rules)
+}
+
+// Rules returns current rules.
+func (r *LevelRules) Rules() []LevelRule {
+	return append([]LevelRule(nil), r.load()...)
+}
+
+// load returns current rules without copying them.
+func (r *LevelRules) load() []LevelRule {
+	if rules := r.rules.Load(); rules != nil {
+		return *rules
+	}
+	return nil
+}
+
+// WithLevelRules changes levels of entries matched by given rules before they are filtered.
+// A demoted entry is written only if the new level is enabled, and a promoted entry is written
+// even if its original level is disabled. Entries higher than error level are never changed.
+func WithLevelRules(rules *LevelRules) Option {
+	return func(o *options) {
+		o.levelRules = rules
+	}
+}
+
+// levelRuleCore is a zapcore.Core which changes levels of entries matched by level rules.
+type levelRuleCore struct {
+	zapcore.Core
+
+	rules *LevelRules
+
+	// fields holds fields added via With, so that rules can match them.
+	fields []zapcore.Field
+}
+
+// newLevelRuleCore wraps given core, so that levels of entries are changed by given rules before the core checks them.
+func newLevelRuleCore(core zapcore.Core, rules *LevelRules) zapcore.Core {
+	return &levelRuleCore{Core: core, rules: rules}
+}
+
+// Enabled reports whether given level is enabled by the wrapped core, or entries of the level can be promoted.
+func (c *levelRuleCore) Enabled(l zapcore.Level) bool {
+	return c.Core.Enabled(l) || c.promotes(l)
+}
+
+// With returns a child core with given fields.
+func (c *levelRuleCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &levelRuleCore{Core: c.Core.With(fields), rules: c.rules, fields: merged}
+}
+
+// Check adds a writer to given checked entry if any rule exists, so that the level is decided with fields.
+// Otherwise, it asks the wrapped core as it is.
+func (c *levelRuleCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level > zapcore.ErrorLevel || len(c.rules.load()) == 0 {
+		return c.Core.Check(ent, ce)
+	}
+	if !c.Core.Enabled(ent.Level) && !c.promotes(ent.Level) {
+		return ce
+	}
+	w := &levelRuleWriter{Core: c.Core, core: c}
+	w.outer = ce.AddCore(ent, w)
+	return w.outer
+}
+
+// levelRuleWriter is a zapcore.Core added to a checked entry by levelRuleCore.
+type levelRuleWriter struct {
+	zapcore.Core
+
+	core *levelRuleCore
+
+	// outer is a checked entry of the logger which this writer is added to.
+	outer *zapcore.CheckedEntry
+}
+
+// Write changes the level of given entry by rules, and writes it if the wrapped core enables the new level.
+func (w *levelRuleWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if level, ok := w.core.match(fields); ok {
+		ent.Level = level
+	}
+	if downstream := w.core.Core.Check(ent, nil); downstream != nil {
+		downstream.ErrorOutput = w.outer.ErrorOutput
+		downstream.Write(fields...)
+	}
+	return nil
+}
+
+// promotes reports whether any rule raises entries of given level.
+func (c *levelRuleCore) promotes(l zapcore.Level) bool {
+	for _, rule := range c.rules.load() {
+		if rule.level() > l {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns the level of the first rule matched by given fields or fields added via With.
+func (c *levelRuleCore) match(fields []zapcore.Field) (zapcore.Level, bool) {
+	for _, rule := range c.rules.load() {
+		if fieldMatches(fields, rule.Field, rule.Value) || fieldMatches(c.fields, rule.Field, rule.Value) {
+			return rule.level(), true
+		}
+	}
+	return zapcore.InfoLevel, false
+}
+
+// fieldMatches reports whether given fields contain the field of given key with given value,
+// compared with the value formatted by fmt.Sprint.
+func fieldMatches(fields []zapcore.Field, key, value string) bool {
+	for _, field := range fields {
+		if field.Key != key {
+			continue
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		field.AddTo(enc)
+		if v, ok := enc.Fields[field.Key]; ok && fmt.Sprint(v) == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithLevelRules(t *testing.T) {
+	t.Parallel()
+
+	rules := NewLevelRules(
+		LevelRule{Field: "component", Value: "healthcheck", Level: zapcore.DebugLevel},
+		LevelRule{Field: "user", Value: "admin", Level: zapcore.WarnLevel},
+	)
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf), WithLevel("info"), WithLevelRules(rules))
+
+	logger.Infow("probe", "component", "healthcheck")
+	logger.With("component", "healthcheck").Info("probe")
+	logger.Debugw("login", "user", "admin")
+	logger.Debugw("login", "user", "guest")
+	logger.Infow("request", "component", "api")
+
+	rules.Set(LevelRule{Field: "component", Value: "api", Level: zapcore.ErrorLevel})
+	logger.Infow("probe", "component", "healthcheck")
+	logger.Infow("request", "component", "api")
+
+	var got [][2]any
+	for _, line := range buf.Lines() {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("expect JSON entry, but received %v", err)
+		}
+		got = append(got, [2]any{entry["level"], entry["msg"]})
+	}
+	want := [][2]any{
+		{"warn", "login"},
+		{"info", "request"},
+		{"info", "probe"},
+		{"error", "request"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]LevelRule{{Field: "component", Value: "api", Level: zapcore.ErrorLevel}}, rules.Rules()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestWithLevelRulesCapsAtError(t *testing.T) {
+	t.Parallel()
+
+	rules := NewLevelRules(LevelRule{Field: "component", Value: "api", Level: zapcore.FatalLevel})
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf), WithLevelRules(rules))
+
+	// The process keeps running, so the entry must not be written as fatal.
+	logger.Infow("request", "component", "api")
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(buf.Lines()[0]), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	if diff := cmp.Diff("error", entry["level"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	if _, err := (FileConfig{LevelRules: []FileLevelRule{{Field: "component", Value: "api", Level: "fatal"}}}).reloadable(); err == nil {
+		t.Error("expect an error for a rule higher than error level, but received nil")
+	}
+}
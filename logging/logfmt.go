@@ -0,0 +1,383 @@
+package logging
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// logfmtPool is a pool of buffers used by logfmt encoders.
+var logfmtPool = buffer.NewPool()
+
+// logfmtEncoder is a zapcore.Encoder which writes entries in logfmt, such as `level=info msg="hello world" user=42`.
+// Nested objects are flattened with dot separated keys, and arrays are written as comma separated values.
+type logfmtEncoder struct {
+	*zapcore.EncoderConfig
+
+	buf *buffer.Buffer
+
+	// namespaces is a list of namespaces opened via OpenNamespace or entered via AddObject.
+	namespaces []string
+}
+
+// NewLogfmtEncoder creates an encoder which writes entries in logfmt with given configuration.
+// Keys of the configuration are used as keys of entry fields, and empty keys are omitted.
+func NewLogfmtEncoder(config zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{EncoderConfig: &config, buf: logfmtPool.Get()}
+}
+
+// Clone returns a copy of the encoder with the same fields.
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := e.clone()
+	_, _ = clone.buf.Write(e.buf.Bytes())
+	return clone
+}
+
+// clone returns a copy of the encoder with the same namespaces and an empty buffer.
+func (e *logfmtEncoder) clone() *logfmtEncoder {
+	return &logfmtEncoder{
+		EncoderConfig: e.EncoderConfig,
+		buf:           logfmtPool.Get(),
+		namespaces:    append([]string(nil), e.namespaces...),
+	}
+}
+
+// EncodeEntry encodes given entry and fields as a line of logfmt.
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := &logfmtEncoder{EncoderConfig: e.EncoderConfig, buf: logfmtPool.Get()}
+
+	if e.TimeKey != "" {
+		final.addEncoded(e.TimeKey, func(arr zapcore.PrimitiveArrayEncoder) {
+			if e.EncodeTime != nil {
+				e.EncodeTime(ent.Time, arr)
+			} else {
+				arr.AppendString(ent.Time.Format(time.RFC3339Nano))
+			}
+		})
+	}
+	if e.LevelKey != "" {
+		final.addEncoded(e.LevelKey, func(arr zapcore.PrimitiveArrayEncoder) {
+			if e.EncodeLevel != nil {
+				e.EncodeLevel(ent.Level, arr)
+			} else {
+				arr.AppendString(ent.Level.String())
+			}
+		})
+	}
+	if e.NameKey != "" && ent.LoggerName != "" {
+		final.addEncoded(e.NameKey, func(arr zapcore.PrimitiveArrayEncoder) {
+			if e.EncodeName != nil {
+				e.EncodeName(ent.LoggerName, arr)
+			} else {
+				arr.AppendString(ent.LoggerName)
+			}
+		})
+	}
+	if e.CallerKey != "" && ent.Caller.Defined {
+		final.addEncoded(e.CallerKey, func(arr zapcore.PrimitiveArrayEncoder) {
+			if e.EncodeCaller != nil {
+				e.EncodeCaller(ent.Caller, arr)
+			} else {
+				arr.AppendString(ent.Caller.TrimmedPath())
+			}
+		})
+	}
+	if e.FunctionKey != "" && ent.Caller.Defined {
+		final.AddString(e.FunctionKey, ent.Caller.Function)
+	}
+	if e.MessageKey != "" {
+		final.AddString(e.MessageKey, ent.Message)
+	}
+
+	if e.buf.Len() > 0 {
+		final.separate()
+		_, _ = final.buf.Write(e.buf.Bytes())
+	}
+
+	final.namespaces = append([]string(nil), e.namespaces...)
+	for _, field := range fields {
+		field.AddTo(final)
+	}
+	final.namespaces = nil
+
+	if e.StacktraceKey != "" && ent.Stack != "" {
+		final.AddString(e.StacktraceKey, ent.Stack)
+	}
+
+	if e.LineEnding != "" {
+		final.buf.AppendString(e.LineEnding)
+	} else {
+		final.buf.AppendString(zapcore.DefaultLineEnding)
+	}
+	return final.buf, nil
+}
+
+func (e *logfmtEncoder) AddArray(key string, v zapcore.ArrayMarshaler) error {
+	arr := &logfmtArray{config: e.EncoderConfig}
+	err := v.MarshalLogArray(arr)
+	e.addValue(key, strings.Join(arr.values, ","))
+	return err
+}
+
+func (e *logfmtEncoder) AddObject(key string, v zapcore.ObjectMarshaler) error {
+	e.namespaces = append(e.namespaces, key)
+	err := v.MarshalLogObject(e)
+	e.namespaces = e.namespaces[:len(e.namespaces)-1]
+	return err
+}
+
+func (e *logfmtEncoder) AddBinary(key string, v []byte) {
+	e.addValue(key, base64.StdEncoding.EncodeToString(v))
+}
+
+func (e *logfmtEncoder) AddByteString(key string, v []byte) {
+	e.addValue(key, string(v))
+}
+
+func (e *logfmtEncoder) AddBool(key string, v bool) {
+	e.addValue(key, strconv.FormatBool(v))
+}
+
+func (e *logfmtEncoder) AddComplex128(key string, v complex128) {
+	e.addValue(key, strconv.FormatComplex(v, 'g', -1, 128))
+}
+
+func (e *logfmtEncoder) AddComplex64(key string, v complex64) {
+	e.addValue(key, strconv.FormatComplex(complex128(v), 'g', -1, 64))
+}
+
+func (e *logfmtEncoder) AddDuration(key string, v time.Duration) {
+	e.addEncoded(key, func(arr zapcore.PrimitiveArrayEncoder) {
+		appendDuration(e.EncoderConfig, arr, v)
+	})
+}
+
+func (e *logfmtEncoder) AddFloat64(key string, v float64) {
+	e.addValue(key, formatFloat(v, 64))
+}
+
+func (e *logfmtEncoder) AddFloat32(key string, v float32) {
+	e.addValue(key, formatFloat(float64(v), 32))
+}
+
+func (e *logfmtEncoder) AddInt(key string, v int)     { e.AddInt64(key, int64(v)) }
+func (e *logfmtEncoder) AddInt32(key string, v int32) { e.AddInt64(key, int64(v)) }
+func (e *logfmtEncoder) AddInt16(key string, v int16) { e.AddInt64(key, int64(v)) }
+func (e *logfmtEncoder) AddInt8(key string, v int8)   { e.AddInt64(key, int64(v)) }
+
+func (e *logfmtEncoder) AddInt64(key string, v int64) {
+	e.addValue(key, strconv.FormatInt(v, 10))
+}
+
+func (e *logfmtEncoder) AddString(key, v string) {
+	e.addValue(key, v)
+}
+
+func (e *logfmtEncoder) AddTime(key string, v time.Time) {
+	e.addEncoded(key, func(arr zapcore.PrimitiveArrayEncoder) {
+		appendTime(e.EncoderConfig, arr, v)
+	})
+}
+
+func (e *logfmtEncoder) AddUint(key string, v uint)       { e.AddUint64(key, uint64(v)) }
+func (e *logfmtEncoder) AddUint32(key string, v uint32)   { e.AddUint64(key, uint64(v)) }
+func (e *logfmtEncoder) AddUint16(key string, v uint16)   { e.AddUint64(key, uint64(v)) }
+func (e *logfmtEncoder) AddUint8(key string, v uint8)     { e.AddUint64(key, uint64(v)) }
+func (e *logfmtEncoder) AddUintptr(key string, v uintptr) { e.AddUint64(key, uint64(v)) }
+
+func (e *logfmtEncoder) AddUint64(key string, v uint64) {
+	e.addValue(key, strconv.FormatUint(v, 10))
+}
+
+// AddReflected writes given value as JSON.
+func (e *logfmtEncoder) AddReflected(key string, v any) error {
+	s, err := reflectedString(v)
+	if err != nil {
+		return err
+	}
+	e.addValue(key, s)
+	return nil
+}
+
+// OpenNamespace prefixes keys of following fields with given key.
+func (e *logfmtEncoder) OpenNamespace(key string) {
+	e.namespaces = append(e.namespaces, key)
+}
+
+// addEncoded writes a value appended by given function, such as the result of EncodeTime.
+func (e *logfmtEncoder) addEncoded(key string, encode func(zapcore.PrimitiveArrayEncoder)) {
+	arr := &logfmtArray{config: e.EncoderConfig}
+	encode(arr)
+	e.addValue(key, strings.Join(arr.values, ","))
+}
+
+// addValue writes given key and value, quoting the value if needed.
+func (e *logfmtEncoder) addValue(key, value string) {
+	e.separate()
+	for _, ns := range e.namespaces {
+		appendLogfmtKey(e.buf, ns)
+		e.buf.AppendByte('.')
+	}
+	appendLogfmtKey(e.buf, key)
+	e.buf.AppendByte('=')
+	if needsQuote(value) {
+		e.buf.AppendString(strconv.Quote(value))
+	} else {
+		e.buf.AppendString(value)
+	}
+}
+
+// separate writes a space if the buffer already has a field.
+func (e *logfmtEncoder) separate() {
+	if e.buf.Len() > 0 {
+		e.buf.AppendByte(' ')
+	}
+}
+
+// appendLogfmtKey writes given key replacing characters not allowed in logfmt keys with underscores.
+func appendLogfmtKey(buf *buffer.Buffer, key string) {
+	if key == "" {
+		buf.AppendByte('_')
+		return
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			buf.AppendByte('_')
+			continue
+		}
+		buf.AppendString(string(r))
+	}
+}
+
+// needsQuote reports whether given value must be quoted in logfmt.
+func needsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatFloat formats given float with the shortest representation.
+func formatFloat(v float64, bitSize int) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, bitSize)
+}
+
+// reflectedString encodes given value as JSON.
+func reflectedString(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// appendDuration appends given duration encoded with the configuration.
+func appendDuration(config *zapcore.EncoderConfig, arr zapcore.PrimitiveArrayEncoder, v time.Duration) {
+	if config.EncodeDuration != nil {
+		config.EncodeDuration(v, arr)
+		return
+	}
+	arr.AppendString(v.String())
+}
+
+// appendTime appends given time encoded with the configuration.
+func appendTime(config *zapcore.EncoderConfig, arr zapcore.PrimitiveArrayEncoder, v time.Time) {
+	if config.EncodeTime != nil {
+		config.EncodeTime(v, arr)
+		return
+	}
+	arr.AppendString(v.Format(time.RFC3339Nano))
+}
+
+// logfmtArray is a zapcore.ArrayEncoder which collects values as strings.
+// Objects and nested arrays are written as JSON.
+type logfmtArray struct {
+	config *zapcore.EncoderConfig
+	values []string
+}
+
+func (a *logfmtArray) AppendBool(v bool)         { a.append(strconv.FormatBool(v)) }
+func (a *logfmtArray) AppendByteString(v []byte) { a.append(string(v)) }
+func (a *logfmtArray) AppendComplex128(v complex128) {
+	a.append(strconv.FormatComplex(v, 'g', -1, 128))
+}
+func (a *logfmtArray) AppendComplex64(v complex64) {
+	a.append(strconv.FormatComplex(complex128(v), 'g', -1, 64))
+}
+func (a *logfmtArray) AppendFloat64(v float64)        { a.append(formatFloat(v, 64)) }
+func (a *logfmtArray) AppendFloat32(v float32)        { a.append(formatFloat(float64(v), 32)) }
+func (a *logfmtArray) AppendInt(v int)                { a.AppendInt64(int64(v)) }
+func (a *logfmtArray) AppendInt64(v int64)            { a.append(strconv.FormatInt(v, 10)) }
+func (a *logfmtArray) AppendInt32(v int32)            { a.AppendInt64(int64(v)) }
+func (a *logfmtArray) AppendInt16(v int16)            { a.AppendInt64(int64(v)) }
+func (a *logfmtArray) AppendInt8(v int8)              { a.AppendInt64(int64(v)) }
+func (a *logfmtArray) AppendString(v string)          { a.append(v) }
+func (a *logfmtArray) AppendUint(v uint)              { a.AppendUint64(uint64(v)) }
+func (a *logfmtArray) AppendUint64(v uint64)          { a.append(strconv.FormatUint(v, 10)) }
+func (a *logfmtArray) AppendUint32(v uint32)          { a.AppendUint64(uint64(v)) }
+func (a *logfmtArray) AppendUint16(v uint16)          { a.AppendUint64(uint64(v)) }
+func (a *logfmtArray) AppendUint8(v uint8)            { a.AppendUint64(uint64(v)) }
+func (a *logfmtArray) AppendUintptr(v uintptr)        { a.AppendUint64(uint64(v)) }
+func (a *logfmtArray) AppendDuration(v time.Duration) { appendDuration(a.config, a, v) }
+func (a *logfmtArray) AppendTime(v time.Time)         { appendTime(a.config, a, v) }
+
+func (a *logfmtArray) AppendArray(v zapcore.ArrayMarshaler) error {
+	nested := &logfmtArray{config: a.config}
+	err := v.MarshalLogArray(nested)
+	s, jsonErr := reflectedString(nested.values)
+	if jsonErr != nil {
+		return jsonErr
+	}
+	a.append(s)
+	return err
+}
+
+func (a *logfmtArray) AppendObject(v zapcore.ObjectMarshaler) error {
+	enc := zapcore.NewMapObjectEncoder()
+	err := v.MarshalLogObject(enc)
+	s, jsonErr := reflectedString(enc.Fields)
+	if jsonErr != nil {
+		return jsonErr
+	}
+	a.append(s)
+	return err
+}
+
+func (a *logfmtArray) AppendReflected(v any) error {
+	s, err := reflectedString(v)
+	if err != nil {
+		return err
+	}
+	a.append(s)
+	return nil
+}
+
+func (a *logfmtArray) append(s string) {
+	a.values = append(a.values, s)
+}
+
+var (
+	_ zapcore.Encoder      = (*logfmtEncoder)(nil)
+	_ zapcore.ArrayEncoder = (*logfmtArray)(nil)
+)
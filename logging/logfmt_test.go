@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestLogfmtEncoder(t *testing.T) {
+	t.Parallel()
+
+	config := zapcore.EncoderConfig{
+		TimeKey:        "ts",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		MessageKey:     "msg",
+		EncodeTime:     zapcore.RFC3339TimeEncoder,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+	}
+	enc := NewLogfmtEncoder(config)
+	enc.AddString("service", "api")
+
+	ent := zapcore.Entry{
+		Level:      zapcore.InfoLevel,
+		Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		LoggerName: "http",
+		Message:    "hello world",
+	}
+	fields := []zapcore.Field{
+		zap.Int("status", 200),
+		zap.Duration("elapsed", 1500*time.Millisecond),
+		zap.String("path", `/a "b"`),
+		zap.String("empty", ""),
+		zap.Strings("tags", []string{"a", "b"}),
+		zap.Error(errors.New("boom")),
+		zap.Object("user", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddInt("id", 42)
+			return nil
+		})),
+		zap.Namespace("req"),
+		zap.String("method", "GET"),
+	}
+
+	buf, err := enc.EncodeEntry(ent, fields)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	want := `ts=2024-01-02T03:04:05Z level=info logger=http msg="hello world" service=api status=200 elapsed=1.5s ` +
+		`path="/a \"b\"" empty="" tags=a,b error=boom user.id=42 req.method=GET` + "\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestWithEncodingLogfmt(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf), WithEncoding("logfmt")).With("user", 42)
+	logger.Info("hello")
+	logger.Info("again")
+
+	lines := buf.Lines()
+	if diff := cmp.Diff(2, len(lines)); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "level=info") || !strings.HasSuffix(line, " user=42") {
+			t.Errorf("expect logfmt entry, but received %q", line)
+		}
+	}
+}
@@ -2,86 +2,158 @@ package logging
 
 import (
 	"context"
-	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 var (
-	// defaultLogger holds a logger used to default logger.
+	// defaultState holds loggers used to default logger.
 	// If you want to get a default logger. You must get default logger from DefaultLogger function.
-	// because, defaultLogger initialized when first call DefaultLogger function.
-	defaultLogger *zap.SugaredLogger
+	// because, defaultState initialized when first call DefaultLogger function.
+	defaultState atomic.Pointer[defaults]
 
-	// defaultLoggerOnce is a sync.Once variable to create default logger once.
-	defaultLoggerOnce sync.Once
+	// defaultMu is a mutex to create default logger once.
+	defaultMu sync.Mutex
 )
 
-// NewLoggerFromEnv creates a logger with configuration from environment variables.
-// If not set environment variables, it will return a logger with production mode and info level.
-func NewLoggerFromEnv() *zap.SugaredLogger {
-	// develop is a flag variable to switch logger mode between develop mode or not develop mode.
-	// default is not develop mode.
-	develop := strings.ToLower(strings.TrimSpace(os.Getenv("LOG_MODE"))) == "develop"
+// defaults holds default loggers and the level used by them.
+type defaults struct {
+	// sugared is a default logger.
+	sugared *zap.SugaredLogger
+
+	// structured is a default structured logger. It shares the same core with sugared.
+	structured *zap.Logger
 
-	// level is a log level variable to set log level.
-	level := os.Getenv("LOG_LEVEL")
+	// level is a level used by default logger.
+	// It can be changed at runtime via SetLevel function.
+	level zap.AtomicLevel
 
-	return NewLogger(develop, level)
+	// components holds levels of named components used by default logger.
+	// They can be changed at runtime via SetComponentLevel function.
+	components *componentLevels
 }
 
-// NewLogger creates a logger with given configuration.
-// If not parse level argument, it will return a logger with info level.
-func NewLogger(develop bool, level string) *zap.SugaredLogger {
-	// config is a configuration to use base to create logger.
-	var config zap.Config
+// NewLoggerFromEnv creates a logger with configuration from environment variables.
+// If not set environment variables, it will return a logger with info level, in develop mode if stdout is a terminal
+// and in production mode otherwise. Given options are applied after the options from environment variables.
+func NewLoggerFromEnv(opts ...Option) *zap.SugaredLogger {
+	return NewStructuredLoggerFromEnv(opts...).Sugar()
+}
 
-	if develop {
-		config = zap.NewDevelopmentConfig()
-	} else {
-		config = zap.NewProductionConfig()
-	}
-	config.Level = zap.NewAtomicLevelAt(stringToZapLevel(level))
+// NewStructuredLoggerFromEnv creates a structured logger with configuration from environment variables.
+// It is same as NewLoggerFromEnv, but it returns *zap.Logger instead of *zap.SugaredLogger.
+func NewStructuredLoggerFromEnv(opts ...Option) *zap.Logger {
+	logger, _, _ := newStructuredLogger(append(envOptions(), opts...)...)
+	return logger
+}
 
-	logger, err := config.Build()
-	if err != nil {
-		logger = zap.NewNop()
-	}
-	return logger.Sugar()
+// NewLogger creates a logger with given options.
+// If not given any options, it will return a logger with production mode and info level.
+func NewLogger(opts ...Option) *zap.SugaredLogger {
+	return NewStructuredLogger(opts...).Sugar()
+}
+
+// NewStructuredLogger creates a structured logger with given options.
+// It is same as NewLogger, but it returns *zap.Logger instead of *zap.SugaredLogger.
+// Use it in hot paths to avoid the allocation cost of the sugared API.
+func NewStructuredLogger(opts ...Option) *zap.Logger {
+	logger, _, _ := newStructuredLogger(opts...)
+	return logger
 }
 
 // DefaultLogger returns a logger from configuration based on environment variables.
 // If not created default logger, it will creates  a new logger and set it to default logger.
 func DefaultLogger() *zap.SugaredLogger {
-	defaultLoggerOnce.Do(func() {
-		defaultLogger = NewLoggerFromEnv()
-	})
-	return defaultLogger
+	return loadDefaults().sugared
+}
+
+// DefaultStructuredLogger returns a structured logger from configuration based on environment variables.
+// It shares the same core with the logger returned by DefaultLogger.
+func DefaultStructuredLogger() *zap.Logger {
+	return loadDefaults().structured
+}
+
+// SetDefault replaces default logger with given logger.
+// The level returned by AtomicLevel and component levels are kept, so that SetLevel and SetComponentLevel
+// affect given logger only if it is created with WithAtomicLevel(AtomicLevel()).
+// The previous default logger is no longer flushed by Sync and Close, unless it is given logger.
+func SetDefault(logger *zap.SugaredLogger) {
+	current := loadDefaults()
+	structured := logger.Desugar()
+	if owner := loggerOwner(current.structured); owner != nil && owner != loggerOwner(structured) {
+		untrack(owner)
+	}
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultState.Store(&defaults{sugared: logger, structured: structured, level: current.level, components: current.components})
+}
+
+// ResetDefault discards default logger, so that next call of DefaultLogger creates a new one from environment variables.
+// The discarded logger is no longer flushed by Sync and Close. It is intended to be used in tests.
+func ResetDefault() {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if d := defaultState.Load(); d != nil {
+		if owner := loggerOwner(d.structured); owner != nil {
+			untrack(owner)
+		}
+	}
+	defaultState.Store(nil)
+}
+
+// loadDefaults returns default loggers.
+// If not created default loggers, it will create them from environment variables.
+func loadDefaults() *defaults {
+	if d := defaultState.Load(); d != nil {
+		return d
+	}
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if d := defaultState.Load(); d != nil {
+		return d
+	}
+
+	logger, level, components := newStructuredLogger(envOptions()...)
+	d := &defaults{sugared: logger.Sugar(), structured: logger, level: level, components: components}
+	defaultState.Store(d)
+	return d
 }
 
 // stringToZapLevel convert given string to zap level.
 // If not match, it will return info level.
 func stringToZapLevel(level string) zapcore.Level {
-	switch strings.ToLower(level) {
+	if lvl, ok := parseLevel(level); ok {
+		return lvl
+	}
+	return zapcore.InfoLevel
+}
+
+// parseLevel convert given string to zap level.
+// If not match, it will return false as second value.
+func parseLevel(level string) (zapcore.Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
 	case "debug":
-		return zapcore.DebugLevel
+		return zapcore.DebugLevel, true
 	case "info":
-		return zapcore.InfoLevel
+		return zapcore.InfoLevel, true
 	case "warn":
-		return zapcore.WarnLevel
+		return zapcore.WarnLevel, true
 	case "error":
-		return zapcore.ErrorLevel
+		return zapcore.ErrorLevel, true
 	case "dpanic":
-		return zapcore.DPanicLevel
+		return zapcore.DPanicLevel, true
 	case "panic":
-		return zapcore.PanicLevel
+		return zapcore.PanicLevel, true
 	case "fatal":
-		return zapcore.FatalLevel
+		return zapcore.FatalLevel, true
 	default:
-		return zapcore.InfoLevel
+		return zapcore.InfoLevel, false
 	}
 }
 
@@ -91,17 +163,158 @@ type contextKey string
 // loggerKey is a context key to store logger in context.
 const loggerKey = contextKey("logger")
 
+// contextLogger holds a logger and values stored in context by this package, such as fields and request ID.
+// They are kept in one value, so that lookup walks the context chain only once.
+// It is not modified after stored except for the cache, and functions storing values store a derived copy.
+type contextLogger struct {
+	// base is a logger given by caller. If nil, default logger is used.
+	base *zap.SugaredLogger
+
+	// baseStructured is a structured logger given by caller via WithStructuredLogger.
+	// It is used as it is while no field is composed, to keep options of the logger.
+	baseStructured *zap.Logger
+
+	// fields is a list of fields accumulated by WithFields.
+	fields []any
+
+	// requestID is a request ID stored by WithRequestID. If empty, no request ID is stored.
+	requestID string
+
+	// buffer is an entry buffer stored by WithBufferedContext. If nil, entries are not buffered.
+	buffer *entryBuffer
+
+	// correlated reports whether correlation headers are extracted by ExtractCorrelation.
+	correlated bool
+
+	// cache is a logger composed from values above on first lookup.
+	// It is composed again if default logger is used and replaced.
+	cache atomic.Pointer[composedLogger]
+}
+
+// composedLogger holds both forms of a logger composed from a contextLogger,
+// so that lookup does not need to convert them.
+type composedLogger struct {
+	// from is a base logger which the logger is composed from.
+	from *zap.SugaredLogger
+
+	sugared    *zap.SugaredLogger
+	structured *zap.Logger
+}
+
+// derive returns a copy of given contextLogger without the cache. If given one is nil, it will return an empty one.
+func (c *contextLogger) derive() *contextLogger {
+	if c == nil {
+		return &contextLogger{}
+	}
+	return &contextLogger{
+		base:           c.base,
+		baseStructured: c.baseStructured,
+		fields:         c.fields,
+		requestID:      c.requestID,
+		buffer:         c.buffer,
+		correlated:     c.correlated,
+	}
+}
+
+// loggers returns the logger composed from stored values, composing it if not cached.
+func (c *contextLogger) loggers() *composedLogger {
+	base := c.base
+	if base == nil {
+		base = DefaultLogger()
+	}
+	if cached := c.cache.Load(); cached != nil && cached.from == base {
+		return cached
+	}
+	composed := c.compose(base)
+	c.cache.Store(composed)
+	return composed
+}
+
+// compose composes a logger from given base logger and stored values.
+func (c *contextLogger) compose(base *zap.SugaredLogger) *composedLogger {
+	sugared := base
+	structured := c.baseStructured
+	if structured == nil || c.base != base {
+		structured = base.Desugar()
+	}
+	if len(c.fields) > 0 {
+		sugared = sugared.With(c.fields...)
+		structured = sugared.Desugar()
+	}
+	if c.requestID != "" {
+		structured = structured.With(zap.String(requestIDField, c.requestID))
+		sugared = structured.Sugar()
+	}
+	if c.buffer != nil {
+		structured = c.buffer.wrap(structured)
+		sugared = structured.Sugar()
+	}
+	return &composedLogger{from: base, sugared: sugared, structured: structured}
+}
+
+// loggerFromContext returns a contextLogger stored in given context.
+// If not contained logger from given context, it will return nil.
+func loggerFromContext(ctx context.Context) *contextLogger {
+	logger, _ := ctx.Value(loggerKey).(*contextLogger)
+	return logger
+}
+
+// withContextLogger stores a contextLogger derived from the one in given context and modified by given function.
+func withContextLogger(ctx context.Context, modify func(*contextLogger)) context.Context {
+	stored := loggerFromContext(ctx).derive()
+	modify(stored)
+	return context.WithValue(ctx, loggerKey, stored)
+}
+
 // WithLogger stores a given logger to given context.
+// Fields attached by WithFields to given context are added to the logger.
 // If context is nil, it will panic. because, this function is wrapper for context.WithValue.
 func WithLogger(ctx context.Context, logger *zap.SugaredLogger) context.Context {
-	return context.WithValue(ctx, loggerKey, logger)
+	return withContextLogger(ctx, func(c *contextLogger) {
+		c.base = logger
+		c.baseStructured = nil
+	})
+}
+
+// WithStructuredLogger stores a given structured logger to given context.
+// Fields attached by WithFields to given context are added to the logger.
+// If context is nil, it will panic. because, this function is wrapper for context.WithValue.
+func WithStructuredLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return withContextLogger(ctx, func(c *contextLogger) {
+		c.base = logger.Sugar()
+		c.baseStructured = logger
+	})
 }
 
 // FromContext returns a logger from given context.
 // If not contained logger from given context, it will return a default logger.
+// Fields derived from given context, such as trace ID, are added to the returned logger.
+// If given context is created by WithBufferedContext, entries of the returned logger are buffered.
+// The logger composed from values stored in given context is cached, so that repeated lookups do not allocate.
 func FromContext(ctx context.Context) *zap.SugaredLogger {
-	if logger, ok := ctx.Value(loggerKey).(*zap.SugaredLogger); ok {
-		return logger
+	stored := loggerFromContext(ctx)
+	logger := DefaultLogger()
+	if stored != nil {
+		logger = stored.loggers().sugared
+	}
+	if fields := contextFields(ctx, stored); len(fields) > 0 {
+		logger = logger.Desugar().With(fields...).Sugar()
+	}
+	return logger
+}
+
+// StructuredFromContext returns a structured logger from given context.
+// If not contained logger from given context, it will return a default structured logger.
+// Fields derived from given context, such as trace ID, are added to the returned logger.
+// If given context is created by WithBufferedContext, entries of the returned logger are buffered.
+func StructuredFromContext(ctx context.Context) *zap.Logger {
+	stored := loggerFromContext(ctx)
+	logger := DefaultStructuredLogger()
+	if stored != nil {
+		logger = stored.loggers().structured
+	}
+	if fields := contextFields(ctx, stored); len(fields) > 0 {
+		logger = logger.With(fields...)
 	}
-	return DefaultLogger()
+	return logger
 }
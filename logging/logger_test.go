@@ -2,12 +2,14 @@ package logging
 
 import (
 	"context"
+	"io"
 	"reflect"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestNewLoggerFromEnv(t *testing.T) {
@@ -22,12 +24,26 @@ func TestNewLoggerFromEnv(t *testing.T) {
 func TestNewLogger(t *testing.T) {
 	t.Parallel()
 
-	result1 := NewLogger(true, "debug")
+	result1 := NewLogger(WithDevelopment(true), WithLevel("debug"))
 	if result1 == nil {
 		t.Errorf("expect not nil, but received nil")
 	}
 
-	result2 := NewLogger(false, "debug")
+	result2 := NewLogger(WithDevelopment(false), WithLevel("debug"))
+	if result2 == nil {
+		t.Errorf("expect not nil, but received nil")
+	}
+}
+
+func TestNewStructuredLogger(t *testing.T) {
+	t.Parallel()
+
+	result1 := NewStructuredLogger(WithDevelopment(true), WithLevel("debug"))
+	if result1 == nil {
+		t.Errorf("expect not nil, but received nil")
+	}
+
+	result2 := NewStructuredLogger(WithDevelopment(false), WithLevel("debug"))
 	if result2 == nil {
 		t.Errorf("expect not nil, but received nil")
 	}
@@ -51,6 +67,78 @@ func TestDefaultLogger(t *testing.T) {
 	}
 }
 
+func TestDefaultStructuredLogger(t *testing.T) {
+	t.Parallel()
+
+	result1 := DefaultStructuredLogger()
+	if result1 == nil {
+		t.Errorf("expect not nil, but received nil")
+	}
+
+	result2 := DefaultStructuredLogger()
+	if result1 != result2 {
+		t.Errorf("expect same logger, but received different logger")
+	}
+
+	if result1.Core() != DefaultLogger().Desugar().Core() {
+		t.Errorf("expect same core with default logger, but received different core")
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	t.Cleanup(ResetDefault)
+
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core).Sugar()
+	SetDefault(logger)
+
+	if DefaultLogger() != logger {
+		t.Error("expect given logger, but received different logger")
+	}
+	if DefaultStructuredLogger().Core() != core {
+		t.Error("expect structured logger with given core, but received different core")
+	}
+
+	FromContext(context.Background()).Info("default")
+	if diff := cmp.Diff(1, logs.FilterMessage("default").Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	ResetDefault()
+	if DefaultLogger() == logger {
+		t.Error("expect new default logger, but received given logger")
+	}
+}
+
+// TestFromContextCache must not run in parallel, because it replaces default logger.
+func TestFromContextCache(t *testing.T) {
+	t.Cleanup(ResetDefault)
+
+	first, firstLogs := observer.New(zap.InfoLevel)
+	SetDefault(zap.New(first).Sugar())
+	ctx := WithRequestID(WithFields(context.Background(), "user_id", 1), "req-1")
+
+	if FromContext(ctx) != FromContext(ctx) {
+		t.Error("expect cached logger, but received different logger")
+	}
+	FromContext(ctx).Info("first")
+
+	second, secondLogs := observer.New(zap.InfoLevel)
+	SetDefault(zap.New(second).Sugar())
+	StructuredFromContext(ctx).Info("second")
+
+	for _, logs := range []*observer.ObservedLogs{firstLogs, secondLogs} {
+		entries := logs.AllUntimed()
+		if len(entries) != 1 {
+			t.Fatalf("expect 1 entry, but received %d", len(entries))
+		}
+		want := map[string]any{"user_id": int64(1), "request_id": "req-1"}
+		if diff := cmp.Diff(want, entries[0].ContextMap()); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+}
+
 func TestStringToZapLevel(t *testing.T) {
 	t.Parallel()
 
@@ -103,3 +191,57 @@ func TestContext(t *testing.T) {
 		t.Error("expect same logger, but received different logger")
 	}
 }
+
+func TestStructuredContext(t *testing.T) {
+	t.Parallel()
+
+	logger := StructuredFromContext(context.Background())
+	if logger == nil {
+		t.Fatal("expect logger, but received nil")
+	}
+
+	ctx := WithStructuredLogger(context.Background(), logger)
+	if ctx == nil {
+		t.Fatal("expect context, but received nil")
+	}
+
+	if logger2 := StructuredFromContext(ctx); logger2 != logger {
+		t.Error("expect same logger, but received different logger")
+	}
+
+	if sugared := FromContext(ctx); sugared.Desugar().Core() != logger.Core() {
+		t.Error("expect sugared logger with same core, but received different core")
+	}
+}
+
+func BenchmarkFromContext(b *testing.B) {
+	logger := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(io.Discard), zap.InfoLevel))
+	base := WithStructuredLogger(context.Background(), logger)
+	withFields := WithFields(base, "user_id", 1)
+	withRequestID := WithRequestID(withFields, "req-1")
+	nested := context.WithValue(context.WithValue(withRequestID, contextKey("a"), 1), contextKey("b"), 2)
+
+	benchmarks := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{name: "logger", ctx: base},
+		{name: "fields", ctx: withFields},
+		{name: "request_id", ctx: withRequestID},
+		{name: "nested", ctx: nested},
+	}
+	for _, bb := range benchmarks {
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = FromContext(bb.ctx)
+			}
+		})
+		b.Run(bb.name+"/structured", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = StructuredFromContext(bb.ctx)
+			}
+		})
+	}
+}
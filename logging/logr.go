@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logrSink is an implementation of logr.LogSink backed by zap sugared logger.
+type logrSink struct {
+	logger *zap.SugaredLogger
+}
+
+// NewLogr creates a logr.Logger which writes entries via given logger.
+// It is useful to share the package's logger with controller-runtime and Kubernetes client libraries.
+// V(0) is written at info level, and V(1) or more verbose is written at debug level.
+func NewLogr(logger *zap.SugaredLogger) logr.Logger {
+	return logr.New(&logrSink{logger: logger})
+}
+
+// Logr returns a logr.Logger backed by the logger stored in given context.
+// If not contained logger from given context, it will be backed by a default logger.
+func Logr(ctx context.Context) logr.Logger {
+	return NewLogr(FromContext(ctx))
+}
+
+// Init receives runtime information from logr.Logger to annotate the right caller.
+// The sink's own method is also skipped, in addition to the frames of logr.Logger.
+func (s *logrSink) Init(info logr.RuntimeInfo) {
+	s.logger = s.logger.WithOptions(zap.AddCallerSkip(info.CallDepth + 1))
+}
+
+// Enabled reports whether the sink writes entries at given verbosity.
+func (s *logrSink) Enabled(level int) bool {
+	return s.logger.Desugar().Core().Enabled(logrToZapLevel(level))
+}
+
+// Info writes a non-error message with given key-value pairs.
+func (s *logrSink) Info(level int, msg string, keysAndValues ...any) {
+	s.logger.Logw(logrToZapLevel(level), msg, keysAndValues...)
+}
+
+// Error writes an error message with given error and key-value pairs.
+func (s *logrSink) Error(err error, msg string, keysAndValues ...any) {
+	s.logger.Errorw(msg, append([]any{zap.Error(err)}, keysAndValues...)...)
+}
+
+// WithValues returns a new sink with given key-value pairs.
+func (s *logrSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &logrSink{logger: s.logger.With(keysAndValues...)}
+}
+
+// WithName returns a new sink with given name appended to the logger name.
+func (s *logrSink) WithName(name string) logr.LogSink {
+	return &logrSink{logger: s.logger.Named(name)}
+}
+
+// WithCallDepth returns a new sink which skips given number of additional stack frames.
+func (s *logrSink) WithCallDepth(depth int) logr.LogSink {
+	return &logrSink{logger: s.logger.WithOptions(zap.AddCallerSkip(depth))}
+}
+
+// logrToZapLevel convert given logr verbosity to zap level.
+func logrToZapLevel(level int) zapcore.Level {
+	if level > 0 {
+		return zapcore.DebugLevel
+	}
+	return zapcore.InfoLevel
+}
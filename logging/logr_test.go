@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogr(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := WithLogger(context.Background(), zap.New(core, zap.AddCaller()).Sugar())
+	logger := Logr(ctx).WithName("controller").WithValues("kind", "Pod")
+
+	logger.V(1).Info("ignored")
+	logger.Info("reconciled", "name", "web")
+	logger.Error(errors.New("boom"), "failed")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, but received %d", len(entries))
+	}
+
+	if diff := cmp.Diff("controller", entries[0].LoggerName); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"kind": "Pod", "name": "web"}, entries[0].ContextMap()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("logr_test.go", filepath.Base(entries[0].Caller.File)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	if diff := cmp.Diff(zap.ErrorLevel, entries[1].Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"kind": "Pod", "error": "boom"}, entries[1].ContextMap()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestLogrEnabled(t *testing.T) {
+	t.Parallel()
+
+	core, _ := observer.New(zap.DebugLevel)
+	logger := NewLogr(zap.New(core).Sugar())
+
+	if !logger.V(0).Enabled() || !logger.V(3).Enabled() {
+		t.Error("expect all verbosity enabled, but disabled")
+	}
+}
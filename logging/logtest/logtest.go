@@ -0,0 +1,127 @@
+// Package logtest provides helpers to verify entries written by loggers in tests.
+package logtest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aqyuki/util/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// Observer records entries written by a test logger.
+type Observer struct {
+	logs *observer.ObservedLogs
+}
+
+// NewTestLogger creates a logger which records every entry to the returned observer.
+// Entries are also written to the test log, so that they are shown when the test fails.
+func NewTestLogger(t testing.TB) (*zap.SugaredLogger, *Observer) {
+	logger, obs := NewStructuredTestLogger(t)
+	return logger.Sugar(), obs
+}
+
+// NewStructuredTestLogger is same as NewTestLogger, but it returns *zap.Logger instead of *zap.SugaredLogger.
+func NewStructuredTestLogger(t testing.TB) (*zap.Logger, *Observer) {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	testCore := zaptest.NewLogger(t, zaptest.Level(zapcore.DebugLevel)).Core()
+
+	logger := zap.New(zapcore.NewTee(core, testCore), zap.AddCaller())
+	return logger, &Observer{logs: logs}
+}
+
+// NewTestContext returns a context which stores a test logger and the observer of the logger.
+func NewTestContext(t testing.TB) (context.Context, *Observer) {
+	t.Helper()
+
+	logger, obs := NewTestLogger(t)
+	return logging.WithLogger(context.Background(), logger), obs
+}
+
+// Entries returns all recorded entries in written order.
+func (o *Observer) Entries() []observer.LoggedEntry {
+	return o.logs.All()
+}
+
+// Len returns the number of recorded entries.
+func (o *Observer) Len() int {
+	return o.logs.Len()
+}
+
+// Reset discards all recorded entries.
+func (o *Observer) Reset() {
+	o.logs.TakeAll()
+}
+
+// FilterMessage returns an observer which contains entries with given message only.
+func (o *Observer) FilterMessage(msg string) *Observer {
+	return &Observer{logs: o.logs.FilterMessage(msg)}
+}
+
+// FilterLevel returns an observer which contains entries at given level only.
+func (o *Observer) FilterLevel(level zapcore.Level) *Observer {
+	return &Observer{logs: o.logs.FilterLevelExact(level)}
+}
+
+// FilterField returns an observer which contains entries with given field only.
+func (o *Observer) FilterField(field zap.Field) *Observer {
+	return &Observer{logs: o.logs.FilterField(field)}
+}
+
+// AssertLogged reports a test error if no entry with given level and message was recorded.
+func (o *Observer) AssertLogged(t testing.TB, level zapcore.Level, msg string) {
+	t.Helper()
+
+	if o.FilterMessage(msg).FilterLevel(level).Len() == 0 {
+		t.Errorf("expect %s entry %q, but not logged", level, msg)
+	}
+}
+
+// AssertNotLogged reports a test error if any entry with given message was recorded.
+func (o *Observer) AssertNotLogged(t testing.TB, msg string) {
+	t.Helper()
+
+	if n := o.FilterMessage(msg).Len(); n > 0 {
+		t.Errorf("expect no entry %q, but logged %d times", msg, n)
+	}
+}
+
+// AssertCount reports a test error if the number of recorded entries is not equal to given count.
+func (o *Observer) AssertCount(t testing.TB, count int) {
+	t.Helper()
+
+	if n := o.Len(); n != count {
+		t.Errorf("expect %d entries, but received %d", count, n)
+	}
+}
+
+// AssertField reports a test error if no entry with given message has given field key and value.
+// The value is compared with the decoded field value, so that integers must be given as int64.
+func (o *Observer) AssertField(t testing.TB, msg string, key string, value any) {
+	t.Helper()
+
+	for _, entry := range o.FilterMessage(msg).Entries() {
+		if got, ok := entry.ContextMap()[key]; ok && reflect.DeepEqual(got, value) {
+			return
+		}
+	}
+	t.Errorf("expect entry %q with field %s=%v, but not logged", msg, key, value)
+}
+
+// AssertSchema reports a test error for each field of recorded entries which does not match given schema,
+// so that tests fail before entries break mappings of log ingestion.
+func (o *Observer) AssertSchema(t testing.TB, schema logging.Schema) {
+	t.Helper()
+
+	for _, entry := range o.Entries() {
+		for _, v := range schema.Validate(entry.Context) {
+			t.Errorf("expect entry %q to match schema %s, but %s", entry.Message, schema.Version, v)
+		}
+	}
+}
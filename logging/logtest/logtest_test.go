@@ -0,0 +1,94 @@
+package logtest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aqyuki/util/logging"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+// recorder is a testing.TB which records errors instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Helper() {}
+
+func TestNewTestLogger(t *testing.T) {
+	t.Parallel()
+
+	logger, obs := NewTestLogger(t)
+	logger.Debugw("debug", "key", "value", "count", 1)
+	logger.Info("info")
+	logger.Info("info")
+
+	obs.AssertCount(t, 3)
+	obs.AssertLogged(t, zap.DebugLevel, "debug")
+	obs.AssertField(t, "debug", "key", "value")
+	obs.AssertField(t, "debug", "count", int64(1))
+	obs.AssertNotLogged(t, "missing")
+
+	if diff := cmp.Diff(2, obs.FilterMessage("info").Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(1, obs.FilterLevel(zap.DebugLevel).Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(1, obs.FilterField(zap.String("key", "value")).Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	obs.Reset()
+	obs.AssertCount(t, 0)
+}
+
+func TestAssertionFailures(t *testing.T) {
+	t.Parallel()
+
+	logger, obs := NewTestLogger(t)
+	logger.Info("info")
+
+	r := &recorder{TB: t}
+	obs.AssertLogged(r, zap.ErrorLevel, "info")
+	obs.AssertNotLogged(r, "info")
+	obs.AssertCount(r, 2)
+	obs.AssertField(r, "info", "key", "value")
+
+	if diff := cmp.Diff(4, len(r.errors)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestNewTestContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, obs := NewTestContext(t)
+	logging.FromContext(logging.WithFields(ctx, "user", "u1")).Warn("warned")
+
+	obs.AssertLogged(t, zap.WarnLevel, "warned")
+	obs.AssertField(t, "warned", "user", "u1")
+}
+
+func TestAssertSchema(t *testing.T) {
+	t.Parallel()
+
+	logger, obs := NewStructuredTestLogger(t)
+	schema := logging.Schema{Version: "v1", Fields: map[string]logging.FieldType{"user_id": logging.FieldInt}}
+	logger.Info("valid", zap.Int("user_id", 1))
+	obs.AssertSchema(t, schema)
+
+	logger.Info("invalid", zap.String("user_id", "1"), zap.Bool("unknown", true))
+	r := &recorder{TB: t}
+	obs.AssertSchema(r, schema)
+
+	if diff := cmp.Diff(2, len(r.errors)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"os"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+)
+
+// containerIDPattern matches container IDs of Docker, containerd, and CRI-O in cgroup paths and mount points.
+var containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// containerIDFiles is a list of files searched for the container ID, in order.
+// Cgroup v1 exposes the ID in cgroup paths, and cgroup v2 exposes it in mount points such as /etc/hostname.
+var containerIDFiles = []string{"/proc/self/cgroup", "/proc/self/mountinfo"}
+
+// WithServiceMetadata adds metadata of the running process to every entry, for fleet-wide log correlation:
+// "hostname", "pid", "go_version", "build_path" and "build_version" of the main module,
+// "vcs_revision" if the binary is built with version control information, and "container_id" if running in a container.
+// Metadata which can not be detected is omitted.
+func WithServiceMetadata() Option {
+	return WithInitialFields(serviceMetadata())
+}
+
+// serviceMetadata detects metadata of the running process.
+func serviceMetadata() map[string]any {
+	fields := map[string]any{
+		"pid":        os.Getpid(),
+		"go_version": runtime.Version(),
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		fields["hostname"] = hostname
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path != "" {
+			fields["build_path"] = info.Main.Path
+		}
+		if info.Main.Version != "" {
+			fields["build_version"] = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				fields["vcs_revision"] = setting.Value
+			}
+		}
+	}
+	if id, ok := containerID(containerIDFiles); ok {
+		fields["container_id"] = id
+	}
+	return fields
+}
+
+// containerID returns the first container ID found in given files.
+func containerID(files []string) (string, bool) {
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if id := containerIDPattern.Find(b); id != nil {
+			return string(id), true
+		}
+	}
+	return "", false
+}
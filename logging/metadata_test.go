@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithServiceMetadata(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf), WithServiceMetadata())
+	logger.Info("message")
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(buf.Lines()[0]), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	if diff := cmp.Diff(float64(os.Getpid()), entry["pid"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(runtime.Version(), entry["go_version"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		if diff := cmp.Diff(hostname, entry["hostname"]); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+}
+
+func TestContainerID(t *testing.T) {
+	t.Parallel()
+
+	id := "3f4e6a1b2c3d4e5f60718293a4b5c6d7e8f90123456789abcdef0123456789ab"
+	dir := t.TempDir()
+	cgroup := filepath.Join(dir, "cgroup")
+	mountinfo := filepath.Join(dir, "mountinfo")
+	if err := os.WriteFile(cgroup, []byte("0::/\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	content := "1 2 0:1 /var/lib/docker/containers/" + id + "/hostname /etc/hostname rw - ext4 /dev/sda1 rw\n"
+	if err := os.WriteFile(mountinfo, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := containerID([]string{filepath.Join(dir, "missing"), cgroup, mountinfo})
+	if !ok {
+		t.Fatal("expect container ID to be found, but not found")
+	}
+	if diff := cmp.Diff(id, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if _, ok := containerID([]string{cgroup}); ok {
+		t.Error("expect no container ID, but found")
+	}
+}
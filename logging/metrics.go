@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zapcore"
+)
+
+// logEntries counts entries written by loggers created with WithMetrics.
+var logEntries = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "log_entries_total",
+	Help: "Number of log entries written, partitioned by level and component.",
+}, []string{"level", "component"})
+
+// droppedEntries counts entries dropped by sinks which send entries in background.
+var droppedEntries = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "log_entries_dropped_total",
+	Help: "Number of log entries dropped by sinks, because their queue was full or sending failed.",
+}, []string{"sink"})
+
+// Collector returns a prometheus.Collector which exposes log_entries_total and log_entries_dropped_total counters.
+// Register it to a prometheus registry to export counts of entries written by loggers created with WithMetrics,
+// and entries dropped by sinks such as WithFluent.
+func Collector() prometheus.Collector {
+	return collectors{logEntries, droppedEntries}
+}
+
+// collectors is a prometheus.Collector which combines multiple collectors.
+type collectors []prometheus.Collector
+
+// Describe sends descriptors of every collector.
+func (c collectors) Describe(ch chan<- *prometheus.Desc) {
+	for _, collector := range c {
+		collector.Describe(ch)
+	}
+}
+
+// Collect sends metrics of every collector.
+func (c collectors) Collect(ch chan<- prometheus.Metric) {
+	for _, collector := range c {
+		collector.Collect(ch)
+	}
+}
+
+// WithMetrics makes the logger count written entries per level and component.
+// The component label is the name of the logger, and it is empty for root loggers.
+// Counts are exposed via Collector.
+func WithMetrics() Option {
+	return WithHook(countEntry)
+}
+
+// countEntry increments log_entries_total counter for given entry. It is used as Hook.
+func countEntry(ent zapcore.Entry, _ []zapcore.Field) error {
+	logEntries.WithLabelValues(ent.Level.String(), ent.LoggerName).Inc()
+	return nil
+}
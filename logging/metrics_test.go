@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithMetrics(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(Collector()); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	errorCounter := logEntries.WithLabelValues("error", "metrics-test")
+	debugCounter := logEntries.WithLabelValues("debug", "metrics-test")
+	errorsBefore := testutil.ToFloat64(errorCounter)
+	debugBefore := testutil.ToFloat64(debugCounter)
+
+	logger := NewLogger(WithLevel("info"), WithWriteSyncer(&zaptest.Buffer{}), WithMetrics()).Named("metrics-test")
+	logger.Debug("ignored")
+	logger.Error("first")
+	logger.Error("second")
+
+	if diff := cmp.Diff(errorsBefore+2, testutil.ToFloat64(errorCounter)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(debugBefore, testutil.ToFloat64(debugCounter)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if n, err := testutil.GatherAndCount(registry, "log_entries_total"); err != nil || n == 0 {
+		t.Errorf("expect gathered metrics, but received %d, %v", n, err)
+	}
+}
@@ -0,0 +1,168 @@
+package logging
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// appendMsgpack appends given value encoded in MessagePack.
+// It supports values produced by zapcore.MapObjectEncoder, and other values are encoded as strings.
+// Keys of maps are sorted, so that the output is deterministic.
+func appendMsgpack(b []byte, v any) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if v {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case string:
+		return appendMsgpackString(b, v)
+	case []byte:
+		return appendMsgpackBinary(b, v)
+	case int:
+		return appendMsgpackInt(b, int64(v))
+	case int64:
+		return appendMsgpackInt(b, v)
+	case int32:
+		return appendMsgpackInt(b, int64(v))
+	case int16:
+		return appendMsgpackInt(b, int64(v))
+	case int8:
+		return appendMsgpackInt(b, int64(v))
+	case uint:
+		return appendMsgpackUint(b, uint64(v))
+	case uint64:
+		return appendMsgpackUint(b, v)
+	case uint32:
+		return appendMsgpackUint(b, uint64(v))
+	case uint16:
+		return appendMsgpackUint(b, uint64(v))
+	case uint8:
+		return appendMsgpackUint(b, uint64(v))
+	case uintptr:
+		return appendMsgpackUint(b, uint64(v))
+	case float64:
+		b = append(b, 0xcb)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(v))
+	case float32:
+		b = append(b, 0xca)
+		return binary.BigEndian.AppendUint32(b, math.Float32bits(v))
+	case time.Duration:
+		return appendMsgpackInt(b, int64(v))
+	case time.Time:
+		return appendMsgpackString(b, v.Format(time.RFC3339Nano))
+	case []any:
+		b = appendMsgpackLength(b, len(v), 0x90, 0xdc, 0xdd)
+		for _, e := range v {
+			b = appendMsgpack(b, e)
+		}
+		return b
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		b = appendMsgpackLength(b, len(v), 0x80, 0xde, 0xdf)
+		for _, k := range keys {
+			b = appendMsgpackString(b, k)
+			b = appendMsgpack(b, v[k])
+		}
+		return b
+	default:
+		return appendMsgpackString(b, fmt.Sprint(v))
+	}
+}
+
+// appendMsgpackString appends given string in MessagePack.
+func appendMsgpackString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, 0xda)
+		b = binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b = append(b, 0xdb)
+		b = binary.BigEndian.AppendUint32(b, uint32(n))
+	}
+	return append(b, s...)
+}
+
+// appendMsgpackBinary appends given bytes in MessagePack.
+func appendMsgpackBinary(b []byte, v []byte) []byte {
+	switch n := len(v); {
+	case n <= math.MaxUint8:
+		b = append(b, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, 0xc5)
+		b = binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b = append(b, 0xc6)
+		b = binary.BigEndian.AppendUint32(b, uint32(n))
+	}
+	return append(b, v...)
+}
+
+// appendMsgpackInt appends given signed integer in MessagePack.
+func appendMsgpackInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return appendMsgpackUint(b, uint64(v))
+	case v >= -32:
+		return append(b, byte(v))
+	case v >= math.MinInt8:
+		return append(b, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		b = append(b, 0xd1)
+		return binary.BigEndian.AppendUint16(b, uint16(v))
+	case v >= math.MinInt32:
+		b = append(b, 0xd2)
+		return binary.BigEndian.AppendUint32(b, uint32(v))
+	default:
+		b = append(b, 0xd3)
+		return binary.BigEndian.AppendUint64(b, uint64(v))
+	}
+}
+
+// appendMsgpackUint appends given unsigned integer in MessagePack.
+func appendMsgpackUint(b []byte, v uint64) []byte {
+	switch {
+	case v < 128:
+		return append(b, byte(v))
+	case v <= math.MaxUint8:
+		return append(b, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		b = append(b, 0xcd)
+		return binary.BigEndian.AppendUint16(b, uint16(v))
+	case v <= math.MaxUint32:
+		b = append(b, 0xce)
+		return binary.BigEndian.AppendUint32(b, uint32(v))
+	default:
+		b = append(b, 0xcf)
+		return binary.BigEndian.AppendUint64(b, v)
+	}
+}
+
+// appendMsgpackLength appends a header of an array or a map with given length.
+// fix is a prefix of the fixed format, and short and long are prefixes of 16 bit and 32 bit formats.
+func appendMsgpackLength(b []byte, n int, fix, short, long byte) []byte {
+	switch {
+	case n < 16:
+		return append(b, fix|byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, short)
+		return binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b = append(b, long)
+		return binary.BigEndian.AppendUint32(b, uint32(n))
+	}
+}
@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAppendMsgpack(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		value any
+		want  []byte
+	}{
+		"nil":          {value: nil, want: []byte{0xc0}},
+		"true":         {value: true, want: []byte{0xc3}},
+		"fixint":       {value: int64(5), want: []byte{0x05}},
+		"negative":     {value: int64(-1), want: []byte{0xff}},
+		"uint16":       {value: int64(300), want: []byte{0xcd, 0x01, 0x2c}},
+		"int8":         {value: int64(-100), want: []byte{0xd0, 0x9c}},
+		"fixstr":       {value: "abc", want: []byte{0xa3, 'a', 'b', 'c'}},
+		"float64":      {value: 1.5, want: []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		"array":        {value: []any{"a", int64(1)}, want: []byte{0x92, 0xa1, 'a', 0x01}},
+		"sorted map":   {value: map[string]any{"b": int64(2), "a": int64(1)}, want: []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x02}},
+		"binary":       {value: []byte{1, 2}, want: []byte{0xc4, 0x02, 1, 2}},
+		"stringer":     {value: struct{ A int }{A: 1}, want: []byte{0xa3, '{', '1', '}'}},
+		"empty string": {value: "", want: []byte{0xa0}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, appendMsgpack(nil, tt.value)); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package logging
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// networkBufferSize is a default maximum number of bytes buffered while disconnected.
+	networkBufferSize = 1 * megabyte
+
+	// networkDialTimeout is a timeout of connecting.
+	networkDialTimeout = time.Second
+
+	// networkWriteTimeout is a timeout of a write, so that a stalled peer does not hold buffered logs forever.
+	networkWriteTimeout = 5 * time.Second
+
+	// networkSyncTimeout is a maximum duration for which Sync and Close wait for buffered bytes to be written.
+	networkSyncTimeout = networkDialTimeout + 2*networkWriteTimeout
+
+	// networkMinBackoff and networkMaxBackoff are bounds of the duration to wait before reconnecting.
+	networkMinBackoff = 100 * time.Millisecond
+	networkMaxBackoff = 30 * time.Second
+)
+
+// registerNetworkSinks registers network schemes as zap sinks once, when output paths are opened first.
+// Schemes already registered by other packages are left to them, instead of panicking at init.
+var registerNetworkSinks = sync.OnceFunc(func() {
+	for _, scheme := range []string{"tcp", "udp", "unix"} {
+		_ = zap.RegisterSink(scheme, newNetworkSink)
+	}
+})
+
+// newNetworkSink creates a NetworkWriter from given URL such as "tcp://host:port" or "unix:///path/to/socket".
+// It is registered as a zap sink, so that the URL can be given to WithOutputPaths or LOG_OUTPUT.
+func newNetworkSink(u *url.URL) (zap.Sink, error) {
+	address := u.Host
+	if u.Scheme == "unix" {
+		address = u.Path
+	}
+	if address == "" {
+		return nil, fmt.Errorf("logging: no address in %q", u.String())
+	}
+	return NewNetworkWriter(u.Scheme, address), nil
+}
+
+// NetworkWriter is a zapcore.WriteSyncer which writes logs to a TCP, UDP, or Unix socket connection.
+// Logs are buffered and written from a background goroutine, so that logging does not wait for the network.
+// If the connection fails, it is reestablished with backoff, and logs are buffered while disconnected.
+// Logs exceeding the buffer, or partially written when the connection fails, are dropped
+// and counted as log_entries_dropped_total{sink="network"}.
+type NetworkWriter struct {
+	network string
+	address string
+
+	// maxBuffer is a maximum number of bytes buffered while disconnected.
+	maxBuffer int
+
+	// mu guards fields below.
+	mu      sync.Mutex
+	pending [][]byte
+	size    int
+
+	// connected reports whether the goroutine holds a connection.
+	connected bool
+
+	// running reports whether the goroutine is running. It is started on write, and stopped by Close.
+	// closing reports whether Close has asked the goroutine to stop.
+	running bool
+	closing bool
+	wake    chan struct{}
+	flushes chan chan error
+	done    chan struct{}
+	stopped chan struct{}
+
+	// conn, retryAt, and backoff are used only by the goroutine.
+	conn    net.Conn
+	retryAt time.Time
+	backoff time.Duration
+}
+
+var _ zap.Sink = (*NetworkWriter)(nil)
+
+// NewNetworkWriter creates a NetworkWriter which connects to given address on given network.
+// The connection is established lazily on first write.
+func NewNetworkWriter(network, address string) *NetworkWriter {
+	return &NetworkWriter{network: network, address: address, maxBuffer: networkBufferSize}
+}
+
+// Write buffers given bytes and wakes the goroutine to write them.
+// It never blocks on the network and never returns an error, so that logging is not interrupted by network failures.
+func (w *NetworkWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	w.buffer(p)
+	w.start()
+	wake := w.wake
+	w.mu.Unlock()
+
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+	return len(p), nil
+}
+
+// Sync waits until buffered bytes are written if connected, up to a timeout.
+func (w *NetworkWriter) Sync() error {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	flushes, stopped := w.flushes, w.stopped
+	w.mu.Unlock()
+
+	reply := make(chan error, 1)
+	timer := time.NewTimer(networkSyncTimeout)
+	defer timer.Stop()
+	select {
+	case flushes <- reply:
+	case <-stopped:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("logging: timed out syncing %s://%s", w.network, w.address)
+	}
+	select {
+	case err := <-reply:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("logging: timed out syncing %s://%s", w.network, w.address)
+	}
+}
+
+// Close writes buffered bytes if connected, up to a timeout, and closes the connection.
+// The connection is established again on next write.
+func (w *NetworkWriter) Close() error {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	if !w.closing {
+		w.closing = true
+		close(w.done)
+	}
+	stopped := w.stopped
+	w.mu.Unlock()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-time.After(networkSyncTimeout):
+		return fmt.Errorf("logging: timed out closing %s://%s", w.network, w.address)
+	}
+}
+
+// start starts the goroutine if it is not running. w.mu must be held.
+// While the goroutine is closing, bytes stay buffered until a write after it stops.
+func (w *NetworkWriter) start() {
+	if w.running {
+		return
+	}
+	w.running = true
+	w.wake = make(chan struct{}, 1)
+	w.flushes = make(chan chan error)
+	w.done = make(chan struct{})
+	w.stopped = make(chan struct{})
+	go w.run(w.wake, w.flushes, w.done, w.stopped)
+}
+
+// run writes buffered bytes when woken, and retries them with backoff while disconnected, until done is closed.
+func (w *NetworkWriter) run(wake <-chan struct{}, flushes <-chan chan error, done <-chan struct{}, stopped chan<- struct{}) {
+	defer func() {
+		w.mu.Lock()
+		w.running, w.closing = false, false
+		w.mu.Unlock()
+		close(stopped)
+	}()
+
+	var retry <-chan time.Time
+	for {
+		var err error
+		select {
+		case <-wake:
+			err = w.drain()
+		case reply := <-flushes:
+			err = w.drain()
+			reply <- err
+		case <-retry:
+			err = w.drain()
+		case <-done:
+			_ = w.drain()
+			if w.conn != nil {
+				_ = w.conn.Close()
+				w.setConn(nil)
+			}
+			return
+		}
+
+		retry = nil
+		if w.conn == nil && w.buffered() {
+			retry = time.After(time.Until(w.retryAt))
+		}
+	}
+}
+
+// drain writes buffered bytes, connecting if needed and allowed by backoff.
+// It returns an error if writing fails, but not if the connection can not be established.
+func (w *NetworkWriter) drain() error {
+	if !w.connect() {
+		return nil
+	}
+	for {
+		w.mu.Lock()
+		if len(w.pending) == 0 {
+			w.pending = nil
+			w.mu.Unlock()
+			return nil
+		}
+		p := w.pending[0]
+		w.mu.Unlock()
+
+		n, err := 0, w.conn.SetWriteDeadline(time.Now().Add(networkWriteTimeout))
+		if err == nil {
+			n, err = w.conn.Write(p)
+		}
+		// Partially written bytes are dropped instead of being written again, not to corrupt the stream.
+		if err == nil || n > 0 {
+			w.mu.Lock()
+			w.pending = w.pending[1:]
+			w.size -= len(p)
+			w.mu.Unlock()
+		}
+		if err != nil {
+			if n > 0 {
+				droppedEntries.WithLabelValues("network").Inc()
+			}
+			w.disconnect()
+			return fmt.Errorf("logging: failed to write to %s://%s: %w", w.network, w.address, err)
+		}
+	}
+}
+
+// connect establishes the connection if needed and allowed by backoff.
+// It reports whether the writer is connected.
+func (w *NetworkWriter) connect() bool {
+	if w.conn != nil {
+		return true
+	}
+	if time.Now().Before(w.retryAt) {
+		return false
+	}
+
+	conn, err := net.DialTimeout(w.network, w.address, networkDialTimeout)
+	if err != nil {
+		w.backoff = min(max(w.backoff*2, networkMinBackoff), networkMaxBackoff)
+		w.retryAt = time.Now().Add(w.backoff)
+		return false
+	}
+	w.setConn(conn)
+	w.backoff = 0
+	return true
+}
+
+// disconnect closes the broken connection and schedules reconnection.
+func (w *NetworkWriter) disconnect() {
+	_ = w.conn.Close()
+	w.setConn(nil)
+	w.backoff = networkMinBackoff
+	w.retryAt = time.Now().Add(w.backoff)
+}
+
+// setConn sets the connection and reports it via connected.
+func (w *NetworkWriter) setConn(conn net.Conn) {
+	w.conn = conn
+	w.mu.Lock()
+	w.connected = conn != nil
+	w.mu.Unlock()
+}
+
+// isConnected reports whether the goroutine holds a connection.
+func (w *NetworkWriter) isConnected() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.connected
+}
+
+// buffered reports whether any bytes are buffered.
+func (w *NetworkWriter) buffered() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.pending) > 0
+}
+
+// buffer keeps a copy of given bytes to write. w.mu must be held.
+// If the buffer is full, the bytes are dropped.
+func (w *NetworkWriter) buffer(p []byte) {
+	if w.size+len(p) > w.maxBuffer {
+		droppedEntries.WithLabelValues("network").Inc()
+		return
+	}
+	w.pending = append(w.pending, append([]byte(nil), p...))
+	w.size += len(p)
+}
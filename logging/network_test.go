@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"bufio"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// acceptLines accepts a connection on given listener and sends lines read from it.
+// The accepted connection is sent to conns.
+func acceptLines(ln net.Listener, conns chan<- net.Conn) <-chan string {
+	lines := make(chan string, 16)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if conns != nil {
+			conns <- conn
+		}
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+}
+
+// receiveLine returns a line from given channel or fails after timeout.
+func receiveLine(t *testing.T, lines <-chan string) string {
+	t.Helper()
+
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("expect a line, but timed out")
+		return ""
+	}
+}
+
+func TestWithOutputPathsNetwork(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	defer ln.Close()
+	lines := acceptLines(ln, nil)
+
+	logger := NewLogger(WithOutputPaths("tcp://" + ln.Addr().String()))
+	logger.Info("hello")
+
+	if line := receiveLine(t, lines); !strings.Contains(line, `"msg":"hello"`) {
+		t.Errorf("expect entry, but received %q", line)
+	}
+}
+
+func TestNetworkWriterReconnect(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "log.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	conns := make(chan net.Conn, 1)
+	lines := acceptLines(ln, conns)
+
+	w := NewNetworkWriter("unix", path)
+	if _, err := w.Write([]byte("first\n")); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff("first", receiveLine(t, lines)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	// The server goes away, and writes are buffered while disconnected.
+	ln.Close()
+	(<-conns).Close()
+	for i := 0; i < 100 && w.isConnected(); i++ {
+		_, _ = w.Write([]byte("second\n"))
+		time.Sleep(10 * time.Millisecond)
+	}
+	if w.isConnected() {
+		t.Fatal("expect the writer to be disconnected")
+	}
+	_, _ = w.Write([]byte("third\n"))
+	if !w.buffered() {
+		t.Fatal("expect buffered bytes, but received none")
+	}
+
+	// The writer reconnects with backoff and writes buffered bytes without further writes.
+	ln, err = net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	defer ln.Close()
+	lines = acceptLines(ln, nil)
+	defer w.Close()
+
+	var received []string
+	for len(received) == 0 || received[len(received)-1] != "third" {
+		received = append(received, receiveLine(t, lines))
+	}
+	if diff := cmp.Diff("third", received[len(received)-1]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if err := w.Sync(); err != nil {
+		t.Errorf("expect no error, but received %v", err)
+	}
+}
+
+func TestNetworkWriterStalledPeer(t *testing.T) {
+	t.Parallel()
+
+	// The server accepts the connection but never reads from it.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(10 * time.Second)
+		}
+	}()
+
+	w := NewNetworkWriter("tcp", ln.Addr().String())
+	defer w.Close()
+
+	chunk := []byte(strings.Repeat("x", 1023) + "\n")
+	start := time.Now()
+	for range 4096 {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expect writes not to wait for the peer, but took %s", elapsed)
+	}
+}
@@ -0,0 +1,22 @@
+package logging
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// nopLogger is a no-op logger shared by Nop and Discard, because it holds no state.
+var nopLogger = zap.NewNop().Sugar()
+
+// Nop returns a logger which writes nothing, so that tests and benchmarks can silence logging.
+func Nop() *zap.SugaredLogger {
+	return nopLogger
+}
+
+// Discard returns a context whose logger writes nothing.
+// Fields attached by WithFields to given context are kept, but they are never written.
+// If context is nil, it will panic. because, this function is wrapper for context.WithValue.
+func Discard(ctx context.Context) context.Context {
+	return WithLogger(ctx, nopLogger)
+}
@@ -0,0 +1,26 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestNop(t *testing.T) {
+	t.Parallel()
+
+	if Nop().Desugar().Core().Enabled(zap.FatalLevel) {
+		t.Error("expect every level disabled, but enabled")
+	}
+}
+
+func TestDiscard(t *testing.T) {
+	t.Parallel()
+
+	ctx := Discard(WithFields(context.Background(), "key", "value"))
+	if StructuredFromContext(ctx).Core().Enabled(zap.FatalLevel) {
+		t.Error("expect every level disabled, but enabled")
+	}
+	FromContext(ctx).Info("discarded")
+}
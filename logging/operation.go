@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultSlowThreshold is a default duration above which LogDuration logs at warn level.
+const defaultSlowThreshold = time.Second
+
+// slowThreshold is a duration above which LogDuration logs at warn level, in nanoseconds.
+var slowThreshold atomic.Int64
+
+func init() {
+	slowThreshold.Store(int64(defaultSlowThreshold))
+}
+
+// SetSlowThreshold changes the duration above which LogDuration logs at warn level. The default is one second.
+func SetSlowThreshold(threshold time.Duration) {
+	slowThreshold.Store(int64(threshold))
+}
+
+// LogDuration starts measuring an operation with given name, and returns a function to log the elapsed time.
+// It is intended to be deferred, such as defer logging.LogDuration(ctx, "rebuild index")().
+// The elapsed time is logged at info level, or at warn level if it exceeds the threshold set by SetSlowThreshold.
+func LogDuration(ctx context.Context, name string) func() {
+	return logDuration(ctx, name, time.Duration(slowThreshold.Load()))
+}
+
+// logDuration returns a function to log the elapsed time at warn level if it exceeds given threshold.
+func logDuration(ctx context.Context, name string, threshold time.Duration) func() {
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		logger := StructuredFromContext(ctx).WithOptions(zap.AddCallerSkip(1))
+		fields := []zap.Field{zap.String("operation", name), zap.Duration("duration", elapsed)}
+		if elapsed > threshold {
+			logger.Warn("operation is slow", append(fields, zap.Duration("threshold", threshold))...)
+			return
+		}
+		logger.Info("operation finished", fields...)
+	}
+}
+
+// StartOperation logs the start of an operation with given name at debug level, and returns a function to log its completion.
+// The returned function logs the duration and the status at a level chosen from given error:
+// info level with "success" if nil, warn level with "canceled" if the context is canceled, and error level with "error" otherwise.
+// Each field is a key-value pair or a zap.Field, as accepted by (*zap.SugaredLogger).With.
+//
+//	done := logging.StartOperation(ctx, "create user", "user_id", id)
+//	err := create(ctx, id)
+//	done(err)
+func StartOperation(ctx context.Context, name string, fields ...any) func(err error) {
+	logger := FromContext(ctx).WithOptions(zap.AddCallerSkip(1)).With("operation", name).With(fields...)
+	logger.Debug("operation started")
+
+	start := time.Now()
+	return func(err error) {
+		duration := zap.Duration("duration", time.Since(start))
+		switch {
+		case err == nil:
+			logger.Infow("operation finished", duration, "status", "success")
+		case errors.Is(err, context.Canceled):
+			logger.Warnw("operation finished", duration, "status", "canceled", Err(err))
+		default:
+			logger.Errorw("operation finished", duration, "status", "error", Err(err))
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestStartOperation(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		err    error
+		level  zapcore.Level
+		status string
+	}{
+		{name: "success", err: nil, level: zapcore.InfoLevel, status: "success"},
+		{name: "canceled", err: fmt.Errorf("query: %w", context.Canceled), level: zapcore.WarnLevel, status: "canceled"},
+		{name: "error", err: errors.New("boom"), level: zapcore.ErrorLevel, status: "error"},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.name, func(t *testing.T) {
+			t.Parallel()
+
+			core, logs := observer.New(zap.DebugLevel)
+			ctx := WithStructuredLogger(context.Background(), zap.New(core))
+
+			done := StartOperation(ctx, "create user", "user_id", "u1")
+			done(cs.err)
+
+			entries := logs.AllUntimed()
+			if len(entries) != 2 {
+				t.Fatalf("expect 2 entries, but received %d", len(entries))
+			}
+			if diff := cmp.Diff([]zapcore.Level{zapcore.DebugLevel, cs.level}, []zapcore.Level{entries[0].Level, entries[1].Level}); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			fields := entries[1].ContextMap()
+			for key, want := range map[string]any{"operation": "create user", "user_id": "u1", "status": cs.status} {
+				if diff := cmp.Diff(want, fields[key]); diff != "" {
+					t.Errorf("%s: (-want, +got)\n%s", key, diff)
+				}
+			}
+			if _, ok := fields["duration"]; !ok {
+				t.Error("expect duration field, but not found")
+			}
+			if _, ok := fields["error"]; ok != (cs.err != nil) {
+				t.Errorf("expect error field to be %v, but received %v", cs.err != nil, fields)
+			}
+		})
+	}
+}
+
+func TestLogDuration(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		threshold time.Duration
+		level     zapcore.Level
+	}{
+		{name: "fast", threshold: time.Hour, level: zapcore.InfoLevel},
+		{name: "slow", threshold: -1, level: zapcore.WarnLevel},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.name, func(t *testing.T) {
+			t.Parallel()
+
+			core, logs := observer.New(zap.DebugLevel)
+			ctx := WithStructuredLogger(context.Background(), zap.New(core))
+
+			func() {
+				defer logDuration(ctx, "rebuild index", cs.threshold)()
+			}()
+
+			entries := logs.AllUntimed()
+			if len(entries) != 1 {
+				t.Fatalf("expect 1 entry, but received %d", len(entries))
+			}
+			if diff := cmp.Diff(cs.level, entries[0].Level); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			fields := entries[0].ContextMap()
+			if diff := cmp.Diff("rebuild index", fields["operation"]); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			if _, ok := fields["duration"]; !ok {
+				t.Error("expect duration field, but not found")
+			}
+		})
+	}
+}
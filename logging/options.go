@@ -0,0 +1,342 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Option is a function to configure a logger created by NewLogger.
+type Option func(*options)
+
+// options holds configurations to create a logger.
+type options struct {
+	// develop is a flag to switch logger mode between develop mode or production mode.
+	develop bool
+
+	// level is a minimum level of the logger.
+	level zapcore.Level
+
+	// levelSet reports whether level is given explicitly via WithLevel.
+	levelSet bool
+
+	// componentLevels is a map of component names to their level names.
+	componentLevels map[string]string
+
+	// atomicLevel is an atomic level given by caller.
+	// If nil, a new atomic level is created.
+	atomicLevel *zap.AtomicLevel
+
+	// outputPaths is a list of paths to write logs.
+	// If empty, the default of selected mode is used.
+	outputPaths []string
+
+	// defaultOutputDisabled reports whether default paths of the mode are not used
+	// even if neither output paths nor writers are given.
+	defaultOutputDisabled bool
+
+	// writers is a list of writers to write logs in addition to output paths.
+	writers []zapcore.WriteSyncer
+
+	// sinks is a list of additional destinations written simultaneously.
+	sinks []SinkConfig
+
+	// stderrLevel is a minimum level of entries duplicated to stderr.
+	// If nil, entries are not duplicated.
+	stderrLevel *zapcore.Level
+
+	// stderr is a writer used to duplicate entries. It is replaced in tests.
+	stderr zapcore.WriteSyncer
+
+	// sampling is a sampling configuration. If nil while samplingSet is true, sampling is disabled.
+	sampling *zap.SamplingConfig
+
+	// samplingSet reports whether sampling is given explicitly via WithSampling or WithoutSampling.
+	// If false, the default of selected mode is used.
+	samplingSet bool
+
+	// redactor redacts entries before they are encoded. If nil, entries are not redacted.
+	redactor *Redactor
+
+	// scrubbers rewrite entries after redactor. They are applied in given order.
+	scrubbers []Scrubber
+
+	// schema validates fields of entries. If nil, fields are not validated.
+	schema *Schema
+
+	// hooks are called for each written entry.
+	hooks []Hook
+
+	// cores is a list of additional cores written simultaneously with the logger's own output.
+	cores []zapcore.Core
+
+	// leveledCores is a list of additional cores filtered at the logger's level and component levels.
+	leveledCores []zapcore.Core
+
+	// router routes entries to its destinations by their fields. If nil, entries are not routed.
+	router *Router
+
+	// levelRules change levels of entries matched by them. If nil, levels are not changed.
+	levelRules *LevelRules
+
+	// dedupWindow is a window to collapse identical consecutive entries. If zero, entries are not collapsed.
+	dedupWindow time.Duration
+
+	// messageFilters drop entries whose message matches any of them. If nil, entries are not filtered.
+	messageFilters *messageFilters
+
+	// rateLimit is a configuration of rate limiting. If nil, entries are not rate limited.
+	rateLimit *RateLimitConfig
+
+	// async is a configuration of asynchronous logging. If nil, entries are written synchronously.
+	async *AsyncConfig
+
+	// closers release resources created by options, such as rotating files.
+	closers []closeFunc
+
+	// errs holds errors occurred while applying options.
+	// They are reported to the error output, and the logger is created without failed parts.
+	errs []error
+
+	// encoding is a name of encoder such as "json", "console", or "logfmt".
+	// If empty, the default of selected mode is used.
+	encoding string
+
+	// color is a mode to decide whether levels are colored in console encoding.
+	color ColorMode
+
+	// levelEncodingSet reports whether the level encoder is given explicitly via WithLevelEncoding.
+	// If true, levels are not colored automatically.
+	levelEncodingSet bool
+
+	// encoderConfigs modify the encoder configuration of selected mode in given order.
+	encoderConfigs []func(*zapcore.EncoderConfig)
+
+	// initialFields is a set of fields added to every entry.
+	initialFields map[string]any
+
+	// callerSkip is a number of additional stack frames to skip when annotating caller.
+	callerSkip int
+
+	// caller reports whether entries are annotated with caller. If nil, caller is annotated.
+	caller *bool
+
+	// stacktraceLevel is a minimum level of entries annotated with stacktrace.
+	// If nil, the default of selected mode is used.
+	stacktraceLevel *zapcore.Level
+
+	// stacktraceDisabled reports whether stacktrace is disabled. It takes precedence over stacktraceLevel.
+	stacktraceDisabled bool
+
+	// fatalHook is called after fatal entries are written. If nil, the process exits.
+	fatalHook zapcore.CheckWriteHook
+
+	// zapOptions are zap options applied after options of this package.
+	zapOptions []zap.Option
+
+	// clock is a source of timestamps of entries. If nil, the system clock is used.
+	clock Clock
+}
+
+// newOptions creates options applied given functions in order.
+func newOptions(opts ...Option) *options {
+	o := &options{
+		level:  zapcore.InfoLevel,
+		stderr: zapcore.Lock(os.Stderr),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithDevelopment switches logger mode to develop mode.
+// Develop mode uses human friendly console encoder and debug friendly behaviors.
+func WithDevelopment(develop bool) Option {
+	return func(o *options) {
+		o.develop = develop
+	}
+}
+
+// WithLevel sets the minimum level of the logger.
+// If not parse level argument, info level is used.
+func WithLevel(level string) Option {
+	return func(o *options) {
+		o.level = stringToZapLevel(level)
+		o.levelSet = true
+	}
+}
+
+// WithComponentLevels sets levels of named components, such as {"database": "debug"}.
+// Entries of loggers named with a component, e.g. via Named, are filtered at the component level.
+// If called multiple times, levels are merged and later values win.
+func WithComponentLevels(levels map[string]string) Option {
+	return func(o *options) {
+		if o.componentLevels == nil {
+			o.componentLevels = make(map[string]string, len(levels))
+		}
+		for name, level := range levels {
+			o.componentLevels[name] = level
+		}
+	}
+}
+
+// WithAtomicLevel makes the logger use given atomic level, so that caller can change its level at runtime.
+// If WithLevel is also given, its level is set to given atomic level.
+func WithAtomicLevel(level zap.AtomicLevel) Option {
+	return func(o *options) {
+		o.atomicLevel = &level
+	}
+}
+
+// WithOutputPaths sets paths to write logs.
+// Each path is a file path or a special path such as "stdout" and "stderr".
+func WithOutputPaths(paths ...string) Option {
+	return func(o *options) {
+		o.outputPaths = append([]string(nil), paths...)
+	}
+}
+
+// WithWriteSyncer adds given writers to write logs.
+// If WithOutputPaths is not given, logs are written to given writers only.
+func WithWriteSyncer(writers ...zapcore.WriteSyncer) Option {
+	return func(o *options) {
+		o.writers = append(o.writers, writers...)
+	}
+}
+
+// WithCore adds given cores written simultaneously with the logger's own output.
+// Each core filters entries at its own level.
+func WithCore(cores ...zapcore.Core) Option {
+	return func(o *options) {
+		o.cores = append(o.cores, cores...)
+	}
+}
+
+// WithRotatingFile adds a file which is rotated when it reaches maxSizeMB megabytes.
+// At most maxBackups rotated files are kept for maxAgeDays days, and they are compressed with gzip.
+// Zero value of maxBackups or maxAgeDays means no limit. See NewRotatingFile for more control.
+func WithRotatingFile(path string, maxSizeMB, maxBackups, maxAgeDays int) Option {
+	return func(o *options) {
+		f := NewRotatingFile(RotatingFileConfig{
+			Path:       path,
+			MaxSizeMB:  maxSizeMB,
+			MaxBackups: maxBackups,
+			MaxAgeDays: maxAgeDays,
+			Compress:   true,
+		})
+		o.writers = append(o.writers, f)
+		o.closers = append(o.closers, registerReopenable(f))
+	}
+}
+
+// WithStderrDuplicate makes entries at or above given level additionally written to stderr,
+// while every entry is written to the logger's own output such as stdout or files.
+// If not parse level argument, warn level is used.
+func WithStderrDuplicate(level string) Option {
+	return func(o *options) {
+		lvl, ok := parseLevel(level)
+		if !ok {
+			lvl = zapcore.WarnLevel
+		}
+		o.stderrLevel = &lvl
+	}
+}
+
+// WithSampling makes the logger sample entries per second.
+// The first initial entries with the same level and message are written,
+// and thereafter only every thereafter-th entry is written in each second.
+func WithSampling(initial, thereafter int) Option {
+	return func(o *options) {
+		o.sampling = &zap.SamplingConfig{Initial: initial, Thereafter: thereafter}
+		o.samplingSet = true
+	}
+}
+
+// WithoutSampling disables sampling, including the sampling enabled by default in production mode.
+func WithoutSampling() Option {
+	return func(o *options) {
+		o.sampling = nil
+		o.samplingSet = true
+	}
+}
+
+// WithEncoding sets a name of encoder such as "json", "console", or "logfmt".
+func WithEncoding(encoding string) Option {
+	return func(o *options) {
+		o.encoding = encoding
+	}
+}
+
+// WithInitialFields adds given fields to every entry written by the logger.
+// If called multiple times, fields are merged and later values win.
+func WithInitialFields(fields map[string]any) Option {
+	return func(o *options) {
+		if o.initialFields == nil {
+			o.initialFields = make(map[string]any, len(fields))
+		}
+		for k, v := range fields {
+			o.initialFields[k] = v
+		}
+	}
+}
+
+// WithCaller sets whether entries are annotated with the file and line of the caller. It is enabled by default.
+func WithCaller(enabled bool) Option {
+	return func(o *options) {
+		o.caller = &enabled
+	}
+}
+
+// WithStacktraceLevel sets the minimum level of entries annotated with stacktrace.
+// The default is error level in production mode and warn level in develop mode. "off" disables stacktrace.
+// If not parse level argument, the default is kept and the error is reported to the error output.
+func WithStacktraceLevel(level string) Option {
+	return func(o *options) {
+		if strings.EqualFold(strings.TrimSpace(level), "off") {
+			o.stacktraceDisabled = true
+			return
+		}
+		lvl, ok := parseLevel(level)
+		if !ok {
+			o.errs = append(o.errs, fmt.Errorf("unknown stacktrace level %q", level))
+			return
+		}
+		o.stacktraceLevel = &lvl
+		o.stacktraceDisabled = false
+	}
+}
+
+// Clock is a source of time used by loggers, such as timestamps of entries.
+// It is the same as zapcore.Clock, so that clocks of zap can be used.
+type Clock = zapcore.Clock
+
+// WithClock makes the logger take timestamps of entries from given clock instead of the system clock,
+// so that tests can produce deterministic timestamps and simulations can use virtual time.
+// Sampling also follows given clock, because it counts entries by their timestamps.
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithZapOptions applies given zap options, such as zap.Fields, zap.WrapCore, and zap.Hooks, to the logger.
+// They are applied after options of this package, so that they can override them.
+// Cores wrapped by zap.WrapCore are not closed by Close.
+func WithZapOptions(opts ...zap.Option) Option {
+	return func(o *options) {
+		o.zapOptions = append(o.zapOptions, opts...)
+	}
+}
+
+// WithCallerSkip increases the number of stack frames to skip when annotating caller.
+// It is useful when the logger is called from wrapper functions.
+func WithCallerSkip(skip int) Option {
+	return func(o *options) {
+		o.callerSkip += skip
+	}
+}
@@ -0,0 +1,286 @@
+package logging
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+)
+
+// readEntries reads JSON entries written to given file.
+func readEntries(t *testing.T, path string) []map[string]any {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("expect JSON entry, but received %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestNewOptions(t *testing.T) {
+	t.Parallel()
+
+	o := newOptions(
+		WithDevelopment(true),
+		WithLevel("warn"),
+		WithOutputPaths("stdout", "stderr"),
+		WithEncoding("console"),
+		WithInitialFields(map[string]any{"a": 1}),
+		WithInitialFields(map[string]any{"b": 2}),
+		WithCallerSkip(1),
+		WithCallerSkip(2),
+	)
+
+	if !o.develop {
+		t.Error("expect develop mode, but received production mode")
+	}
+	if diff := cmp.Diff(zap.WarnLevel, o.level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"stdout", "stderr"}, o.outputPaths); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("console", o.encoding); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"a": 1, "b": 2}, o.initialFields); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(3, o.callerSkip); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestNewLoggerWithOptions(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger := NewLogger(
+		WithLevel("warn"),
+		WithOutputPaths(path),
+		WithEncoding("json"),
+		WithInitialFields(map[string]any{"service": "test"}),
+	)
+
+	logger.Info("ignored")
+	logger.Warn("written")
+	_ = logger.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("written", entries[0]["msg"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("test", entries[0]["service"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestWithAtomicLevel(t *testing.T) {
+	t.Parallel()
+
+	level := zap.NewAtomicLevelAt(zap.ErrorLevel)
+	logger := NewStructuredLogger(WithAtomicLevel(level))
+	if logger.Core().Enabled(zap.InfoLevel) {
+		t.Fatal("expect info level disabled, but enabled")
+	}
+
+	level.SetLevel(zap.DebugLevel)
+	if !logger.Core().Enabled(zap.DebugLevel) {
+		t.Error("expect debug level enabled, but disabled")
+	}
+
+	NewStructuredLogger(WithAtomicLevel(level), WithLevel("warn"))
+	if diff := cmp.Diff(zap.WarnLevel, level.Level()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestWithStderrDuplicate(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	stderr := &zaptest.Buffer{}
+	logger := NewLogger(
+		WithOutputPaths(path),
+		WithStderrDuplicate("warn"),
+		func(o *options) { o.stderr = stderr },
+	)
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+	_ = logger.Sync()
+
+	if diff := cmp.Diff(3, len(readEntries(t, path))); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	lines := stderr.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("expect 2 lines, but received %d", len(lines))
+	}
+	if !strings.Contains(lines[0], `"msg":"warn"`) || !strings.Contains(lines[1], `"msg":"error"`) {
+		t.Errorf("expect warn and error entries, but received %v", lines)
+	}
+}
+
+func TestWithSampling(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		opt  Option
+		want int
+	}{
+		{name: "default", opt: func(*options) {}, want: 100},
+		{name: "sampling", opt: WithSampling(2, 0), want: 2},
+		{name: "without sampling", opt: WithoutSampling(), want: 150},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &zaptest.Buffer{}
+			logger := NewStructuredLogger(WithWriteSyncer(buf), cs.opt)
+			for i := 0; i < 150; i++ {
+				logger.Info("repeated")
+			}
+
+			if diff := cmp.Diff(cs.want, len(buf.Lines())); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithCallerAndStacktrace(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		opts       []Option
+		caller     bool
+		stacktrace []bool
+	}{
+		{name: "default", caller: true, stacktrace: []bool{false, true}},
+		{name: "without caller", opts: []Option{WithCaller(false)}, caller: false, stacktrace: []bool{false, true}},
+		{name: "stacktrace at warn", opts: []Option{WithStacktraceLevel("warn")}, caller: true, stacktrace: []bool{true, true}},
+		{name: "stacktrace off", opts: []Option{WithStacktraceLevel("off")}, caller: true, stacktrace: []bool{false, false}},
+		{name: "unknown stacktrace level", opts: []Option{WithStacktraceLevel("loud")}, caller: true, stacktrace: []bool{false, true}},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &zaptest.Buffer{}
+			logger := NewStructuredLogger(append([]Option{WithWriteSyncer(buf)}, cs.opts...)...)
+			logger.Warn("warn")
+			logger.Error("error")
+
+			var stacktrace []bool
+			for _, line := range buf.Lines() {
+				var entry map[string]any
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("expect JSON entry, but received %v", err)
+				}
+				if _, ok := entry["caller"]; ok != cs.caller {
+					t.Errorf("expect caller to be %v, but received %s", cs.caller, line)
+				}
+				_, ok := entry["stacktrace"]
+				stacktrace = append(stacktrace, ok)
+			}
+			if diff := cmp.Diff(cs.stacktrace, stacktrace); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+// fixedClock is a Clock which always returns the same time.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func (c fixedClock) NewTicker(d time.Duration) *time.Ticker {
+	return time.NewTicker(d)
+}
+
+func TestWithClock(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	buf := &zaptest.Buffer{}
+	logger := NewStructuredLogger(
+		WithWriteSyncer(buf),
+		WithClock(fixedClock(now)),
+	)
+	logger.Info("first")
+	logger.Info("second")
+
+	for _, line := range buf.Lines() {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("expect JSON entry, but received %v", err)
+		}
+		if diff := cmp.Diff(float64(now.Unix()), entry["ts"]); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+}
+
+func TestWithZapOptions(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	var hooked []string
+	logger := NewStructuredLogger(
+		WithWriteSyncer(buf),
+		WithZapOptions(
+			zap.Fields(zap.String("service", "api")),
+			zap.Hooks(func(ent zapcore.Entry) error {
+				hooked = append(hooked, ent.Message)
+				return nil
+			}),
+		),
+	)
+	logger.Info("message")
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(buf.Lines()[0]), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	if diff := cmp.Diff("api", entry["service"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"message"}, hooked); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
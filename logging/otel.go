@@ -0,0 +1,203 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// otelScopeName is a name of the instrumentation scope of records emitted via OpenTelemetry.
+const otelScopeName = "github.com/aqyuki/util/logging"
+
+// otelContextKey is a key of the field which carries a context given to OTelContext.
+const otelContextKey = "otel.context"
+
+// WithOTel emits entries as log records through loggers of given provider, in addition to the logger's own output.
+// Entries are filtered at the logger's level and component levels, so that the provider receives
+// the same entries as local sinks. Use OTelContext to correlate records with spans.
+func WithOTel(provider log.LoggerProvider) Option {
+	return func(o *options) {
+		o.leveledCores = append(o.leveledCores, NewOTelCore(provider))
+	}
+}
+
+// OTelContext returns a field which carries given context to OpenTelemetry log records,
+// so that they are correlated with the span of the context. The field is not written by other encoders.
+func OTelContext(ctx context.Context) zap.Field {
+	return zap.Field{Key: otelContextKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// otelCore is a zapcore.Core which emits entries as OpenTelemetry log records.
+type otelCore struct {
+	logger log.Logger
+	fields []zapcore.Field
+}
+
+// NewOTelCore creates a core which emits every entry as a log record through a logger of given provider.
+// Fields are converted to attributes, and the name of the logger is reported as "logger" attribute.
+func NewOTelCore(provider log.LoggerProvider) zapcore.Core {
+	return &otelCore{logger: provider.Logger(otelScopeName)}
+}
+
+// Enabled reports true for every level. Records are filtered by the provider.
+func (c *otelCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With returns a child core with given fields.
+func (c *otelCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &otelCore{logger: c.logger, fields: merged}
+}
+
+// Check adds the core to given checked entry if the logger of the provider accepts its severity.
+func (c *otelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	var param log.EnabledParameters
+	param.SetSeverity(otelSeverity(ent.Level))
+	if c.logger.Enabled(context.Background(), param) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write converts given entry to a log record and emits it.
+func (c *otelCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	var r log.Record
+	r.SetTimestamp(ent.Time)
+	r.SetObservedTimestamp(time.Now())
+	r.SetSeverity(otelSeverity(ent.Level))
+	r.SetSeverityText(ent.Level.CapitalString())
+	r.SetBody(log.StringValue(ent.Message))
+
+	ctx := context.Background()
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		ctx = addOTelField(ctx, enc, field)
+	}
+	for _, field := range fields {
+		ctx = addOTelField(ctx, enc, field)
+	}
+
+	attrs := make([]log.KeyValue, 0, len(enc.Fields)+5)
+	if ent.LoggerName != "" {
+		attrs = append(attrs, log.String("logger", ent.LoggerName))
+	}
+	if ent.Caller.Defined {
+		attrs = append(attrs,
+			log.String("code.filepath", ent.Caller.File),
+			log.Int("code.lineno", ent.Caller.Line),
+			log.String("code.function", ent.Caller.Function),
+		)
+	}
+	if ent.Stack != "" {
+		attrs = append(attrs, log.String("stacktrace", ent.Stack))
+	}
+	for k, v := range enc.Fields {
+		attrs = append(attrs, log.KeyValue{Key: k, Value: otelValue(v)})
+	}
+	r.AddAttributes(attrs...)
+
+	c.logger.Emit(ctx, r)
+	return nil
+}
+
+// Sync does nothing. Records are flushed by the provider.
+func (c *otelCore) Sync() error {
+	return nil
+}
+
+// addOTelField adds given field to the encoder.
+// If the field is created by OTelContext, it returns the carried context instead.
+func addOTelField(ctx context.Context, enc *zapcore.MapObjectEncoder, field zapcore.Field) context.Context {
+	if field.Type == zapcore.SkipType && field.Key == otelContextKey {
+		if c, ok := field.Interface.(context.Context); ok {
+			return c
+		}
+		return ctx
+	}
+	field.AddTo(enc)
+	return ctx
+}
+
+// otelValue converts a value encoded by zapcore.MapObjectEncoder to a log value.
+func otelValue(v any) log.Value {
+	switch v := v.(type) {
+	case string:
+		return log.StringValue(v)
+	case bool:
+		return log.BoolValue(v)
+	case int64:
+		return log.Int64Value(v)
+	case int32:
+		return log.Int64Value(int64(v))
+	case int16:
+		return log.Int64Value(int64(v))
+	case int8:
+		return log.Int64Value(int64(v))
+	case uint64:
+		if v <= math.MaxInt64 {
+			return log.Int64Value(int64(v))
+		}
+		return log.StringValue(strconv.FormatUint(v, 10))
+	case uint32:
+		return log.Int64Value(int64(v))
+	case uint16:
+		return log.Int64Value(int64(v))
+	case uint8:
+		return log.Int64Value(int64(v))
+	case float64:
+		return log.Float64Value(v)
+	case float32:
+		return log.Float64Value(float64(v))
+	case []byte:
+		return log.BytesValue(v)
+	case time.Duration:
+		return log.Int64Value(int64(v))
+	case time.Time:
+		return log.StringValue(v.Format(time.RFC3339Nano))
+	case []any:
+		values := make([]log.Value, len(v))
+		for i, e := range v {
+			values[i] = otelValue(e)
+		}
+		return log.SliceValue(values...)
+	case map[string]any:
+		kvs := make([]log.KeyValue, 0, len(v))
+		for k, e := range v {
+			kvs = append(kvs, log.KeyValue{Key: k, Value: otelValue(e)})
+		}
+		return log.MapValue(kvs...)
+	case nil:
+		return log.Value{}
+	default:
+		return log.StringValue(fmt.Sprint(v))
+	}
+}
+
+// otelSeverity converts given zap level to OpenTelemetry severity.
+func otelSeverity(l zapcore.Level) log.Severity {
+	switch l {
+	case zapcore.DebugLevel:
+		return log.SeverityDebug
+	case zapcore.InfoLevel:
+		return log.SeverityInfo
+	case zapcore.WarnLevel:
+		return log.SeverityWarn
+	case zapcore.ErrorLevel:
+		return log.SeverityError
+	case zapcore.DPanicLevel:
+		return log.SeverityFatal1
+	case zapcore.PanicLevel:
+		return log.SeverityFatal2
+	default:
+		return log.SeverityFatal3
+	}
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/logtest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithOTel(t *testing.T) {
+	t.Parallel()
+
+	recorder := logtest.NewRecorder()
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf), WithLevel("info"), WithOTel(recorder)).Named("worker").With("job", "j-1")
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+
+	logger.Debug("ignored")
+	logger.Warnw("slow", OTelContext(ctx), "attempt", 3, "tags", []string{"a"})
+
+	if len(buf.Lines()) != 1 {
+		t.Fatalf("expect 1 local entry, but received %d", len(buf.Lines()))
+	}
+
+	scopes := recorder.Result()
+	if len(scopes) != 1 || len(scopes[0].Records) != 1 {
+		t.Fatalf("expect 1 record, but received %+v", scopes)
+	}
+	if diff := cmp.Diff(otelScopeName, scopes[0].Name); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	record := scopes[0].Records[0]
+	if diff := cmp.Diff(log.SeverityWarn, record.Severity()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("slow", record.Body().AsString()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if got := trace.SpanContextFromContext(record.Context()); !got.Equal(spanCtx) {
+		t.Errorf("expect span context %v, but received %v", spanCtx, got)
+	}
+
+	attrs := make(map[string]log.Value)
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	if diff := cmp.Diff("worker", attrs["logger"].AsString()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("j-1", attrs["job"].AsString()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(int64(3), attrs["attempt"].AsInt64()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if tags := attrs["tags"].AsSlice(); len(tags) != 1 || tags[0].AsString() != "a" {
+		t.Errorf("expect tags [a], but received %v", attrs["tags"])
+	}
+	if _, ok := attrs[otelContextKey]; ok {
+		t.Errorf("expect no %s attribute, but found", otelContextKey)
+	}
+}
+
+func TestOTelContextNotEncoded(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	logger := NewStructuredLogger(WithWriteSyncer(buf))
+	logger.Info("message", OTelContext(context.Background()), zap.String("key", "value"))
+
+	if diff := cmp.Diff(1, len(buf.Lines())); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+	if line := buf.Lines()[0]; strings.Contains(line, otelContextKey) {
+		t.Errorf("expect no %s key, but received %s", otelContextKey, line)
+	}
+}
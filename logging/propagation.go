@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// baggageKey is a field key of W3C baggage members.
+const baggageKey = "baggage"
+
+// correlationPropagator propagates W3C traceparent, tracestate, and baggage headers.
+var correlationPropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// ExtractCorrelation returns a context which carries the remote span and baggage in W3C traceparent and baggage
+// headers of given carrier. The logger returned by FromContext contains trace_id and span_id fields
+// and baggage field of the members, even if trace fields are not enabled via EnableTraceFields.
+// If the carrier has neither of them, it will return given context as it is.
+func ExtractCorrelation(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	extracted := correlationPropagator.Extract(ctx, carrier)
+	if !trace.SpanContextFromContext(extracted).IsValid() && baggage.FromContext(extracted).Len() == 0 {
+		return ctx
+	}
+	return withContextLogger(extracted, func(c *contextLogger) {
+		c.correlated = true
+	})
+}
+
+// InjectCorrelation writes W3C traceparent and baggage headers of the span and baggage in given context to given carrier,
+// so that correlation continues in the next service.
+func InjectCorrelation(ctx context.Context, carrier propagation.TextMapCarrier) {
+	correlationPropagator.Inject(ctx, carrier)
+}
+
+// ExtractHTTP is same as ExtractCorrelation, but it reads headers of incoming HTTP requests.
+func ExtractHTTP(ctx context.Context, header http.Header) context.Context {
+	return ExtractCorrelation(ctx, propagation.HeaderCarrier(header))
+}
+
+// InjectHTTP is same as InjectCorrelation, but it writes headers of outgoing HTTP requests.
+func InjectHTTP(ctx context.Context, header http.Header) {
+	InjectCorrelation(ctx, propagation.HeaderCarrier(header))
+}
+
+// baggageFields returns baggage field from the baggage in given context.
+// If the context has no members, it will return nil.
+func baggageFields(ctx context.Context) []zap.Field {
+	bag := baggage.FromContext(ctx)
+	if bag.Len() == 0 {
+		return nil
+	}
+	return []zap.Field{zap.Object(baggageKey, zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		for _, member := range bag.Members() {
+			enc.AddString(member.Key(), member.Value())
+		}
+		return nil
+	}))}
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestExtractHTTP(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	base := WithStructuredLogger(context.Background(), zap.New(core))
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	header.Set("baggage", "tenant_id=acme,user_id=42")
+	ctx := ExtractHTTP(base, header)
+	FromContext(ctx).Info("extracted")
+
+	if got := ExtractHTTP(base, http.Header{}); got != base {
+		t.Error("expect given context without correlation headers, but received another")
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	want := map[string]any{
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":  "00f067aa0ba902b7",
+		"baggage":  map[string]any{"tenant_id": "acme", "user_id": "42"},
+	}
+	if diff := cmp.Diff(want, entries[0].ContextMap()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	outgoing := http.Header{}
+	InjectHTTP(ctx, outgoing)
+	if diff := cmp.Diff(header.Get("traceparent"), outgoing.Get("traceparent")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	members := strings.Split(outgoing.Get("baggage"), ",")
+	sort.Strings(members)
+	if diff := cmp.Diff([]string{"tenant_id=acme", "user_id=42"}, members); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
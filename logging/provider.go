@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aqyuki/util/config"
+	"go.uber.org/zap"
+)
+
+// NewLoggerFromProvider creates a logger from the value of given key of given configuration, such as "log",
+// described by FileConfig. Values may come from any source of the configuration, such as files,
+// environment variables, or flags. If the key is empty, the whole configuration is used.
+// Changes of level, component levels, filters, and level rules are applied to the logger when the configuration
+// is reloaded, such as by (*config.Config).Watch. Failures of applying them are reported by the logger.
+// The subscription stops on Close.
+func NewLoggerFromProvider(c *config.Config, key string) (*zap.SugaredLogger, error) {
+	logger, err := NewStructuredLoggerFromProvider(c, key)
+	if err != nil {
+		return nil, err
+	}
+	return logger.Sugar(), nil
+}
+
+// NewStructuredLoggerFromProvider creates a structured logger from given configuration.
+// It is same as NewLoggerFromProvider, but it returns *zap.Logger instead of *zap.SugaredLogger.
+func NewStructuredLoggerFromProvider(c *config.Config, key string) (*zap.Logger, error) {
+	fc, err := readProviderConfig(c, key)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := fc.options()
+	if err != nil {
+		return nil, err
+	}
+	live, err := fc.reloadable()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &liveConfig{rules: NewLevelRules(live.rules...), filters: &messageFilters{}}
+	l.filters.set(live.filters)
+	// subscription guards unsubscribe, because the logger may be closed before it subscribes to changes.
+	var subscription struct {
+		mu          sync.Mutex
+		closed      bool
+		unsubscribe func()
+	}
+	opts = append(opts, WithLevelRules(l.rules), func(o *options) {
+		o.messageFilters = l.filters
+		o.closers = append(o.closers, func(context.Context) error {
+			subscription.mu.Lock()
+			defer subscription.mu.Unlock()
+			subscription.closed = true
+			if subscription.unsubscribe != nil {
+				subscription.unsubscribe()
+			}
+			return nil
+		})
+	})
+	l.logger, l.level, l.components, err = buildStructuredLogger(opts...)
+	if err != nil {
+		return nil, err
+	}
+	l.level.SetLevel(live.level)
+	l.components.setAll(live.components)
+
+	subscription.mu.Lock()
+	defer subscription.mu.Unlock()
+	if !subscription.closed {
+		subscription.unsubscribe = c.OnChange(key, func(_, _ any) {
+			fc, err := readProviderConfig(c, key)
+			if err == nil {
+				err = l.apply(fc)
+			}
+			if err != nil {
+				l.logger.Error("logging: failed to reload config", zap.String("key", key), zap.Error(err))
+			}
+		})
+	}
+	return l.logger, nil
+}
+
+// readProviderConfig reads FileConfig from given key of given configuration.
+func readProviderConfig(c *config.Config, key string) (FileConfig, error) {
+	var fc FileConfig
+	if err := c.Unmarshal(key, &fc); err != nil {
+		return FileConfig{}, fmt.Errorf("logging: failed to read config: %w", err)
+	}
+	return fc, nil
+}
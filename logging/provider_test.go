@@ -0,0 +1,120 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aqyuki/util/config"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+func TestNewLoggerFromProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv("TEST_PROVIDER_LOG__LEVEL", "warn,database=debug")
+
+	c, err := config.New(
+		config.Defaults(map[string]any{"log": map[string]any{
+			"level":    "info",
+			"encoding": "json",
+			"outputs":  []any{path},
+			"filters":  []any{"^ignored"},
+		}}),
+		config.Env("TEST_PROVIDER_"),
+	)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	logger, err := NewStructuredLoggerFromProvider(c, "log")
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	logger.Info("dropped by level")
+	logger.Warn("ignored by filter")
+	logger.Warn("written")
+	logger.Named("database").Debug("component")
+	_ = logger.Sync()
+
+	entries := readEntries(t, path)
+	var got []any
+	for _, entry := range entries {
+		got = append(got, entry["msg"])
+	}
+	if diff := cmp.Diff([]any{"written", "component"}, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestNewLoggerFromProviderInvalid(t *testing.T) {
+	t.Parallel()
+
+	c, err := config.New(config.Defaults(map[string]any{"log.level": "verbose"}))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if _, err := NewLoggerFromProvider(c, "log"); err == nil {
+		t.Error("expect an error, but received nil")
+	}
+	if _, err := NewLoggerFromProvider(c, ""); err != nil {
+		t.Errorf("expect no error for unknown keys, but received %v", err)
+	}
+}
+
+func TestNewLoggerFromProviderBuildError(t *testing.T) {
+	t.Parallel()
+
+	c, err := config.New(config.Defaults(map[string]any{"log": map[string]any{"outputs": []any{"unknown://sink"}}}))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if _, err := NewLoggerFromProvider(c, "log"); err == nil {
+		t.Error("expect an error of the unknown sink, but received nil")
+	}
+}
+
+func TestNewLoggerFromProviderReload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+	output := filepath.Join(dir, "app.log")
+	write := func(level string) {
+		content := "log:\n  level: " + level + "\n  outputs: [" + output + "]\n"
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+	}
+	write("info")
+
+	c, err := config.New(config.File(path))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	logger, err := NewStructuredLoggerFromProvider(c, "log")
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if logger.Core().Enabled(zap.DebugLevel) {
+		t.Fatal("expect debug level disabled, but enabled")
+	}
+
+	write("debug")
+	if err := c.Reload(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if !logger.Core().Enabled(zap.DebugLevel) {
+		t.Error("expect debug level enabled after reload, but disabled")
+	}
+
+	write("verbose")
+	if err := c.Reload(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	_ = logger.Sync()
+	entries := readEntries(t, output)
+	if len(entries) != 1 || entries[0]["msg"] != "logging: failed to reload config" {
+		t.Errorf("expect reload failure to be reported, but received %v", entries)
+	}
+}
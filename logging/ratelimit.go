@@ -0,0 +1,256 @@
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// defaultRateLimit is a default number of entries written per key in an interval.
+	defaultRateLimit = 10
+
+	// defaultRateLimitInterval is a default interval of rate limiting.
+	defaultRateLimitInterval = time.Second
+
+	// rateLimitKeyField is a key of the field which carries a key given to RateLimitKey.
+	rateLimitKeyField = "ratelimit.key"
+
+	// rateLimitMaxKeys is a maximum number of tracked keys. Entries of other keys share rateLimitOverflowKey.
+	rateLimitMaxKeys = 4096
+
+	// rateLimitOverflowKey is a key of entries whose own key can not be tracked because of rateLimitMaxKeys.
+	rateLimitOverflowKey = "ratelimit.overflow"
+)
+
+// RateLimitConfig is a configuration of rate limiting.
+type RateLimitConfig struct {
+	// Limit is a maximum number of entries written per key in an interval. If zero, 10 is used.
+	Limit int
+
+	// Interval is a length of the window in which Limit is applied. If zero, one second is used.
+	Interval time.Duration
+}
+
+// WithRateLimit limits entries sharing the same message to the configured number per interval,
+// so that tight loops do not flood sinks. Use RateLimitKey to group entries by another key.
+// Up to 4096 keys are tracked at once, and entries of other keys share a limit until tracked keys expire.
+// When an interval in which entries were suppressed ends, a summary entry with "suppressed" count is written.
+// Entries higher than error level are never suppressed.
+func WithRateLimit(config RateLimitConfig) Option {
+	return func(o *options) {
+		o.rateLimit = &config
+	}
+}
+
+// RateLimitKey returns a field which makes WithRateLimit group the entry by given key instead of its message.
+// The field is not written by encoders.
+func RateLimitKey(key string) zap.Field {
+	return zap.Field{Key: rateLimitKeyField, Type: zapcore.SkipType, String: key}
+}
+
+// rateLimitCore is a zapcore.Core which suppresses entries exceeding the limit of their key.
+type rateLimitCore struct {
+	zapcore.Core
+
+	limiter *rateLimiter
+}
+
+// newRateLimitCore wraps given core, so that entries exceeding the limit are suppressed.
+// Summary entries are written to given core.
+func newRateLimitCore(core zapcore.Core, config RateLimitConfig) zapcore.Core {
+	if config.Limit <= 0 {
+		config.Limit = defaultRateLimit
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultRateLimitInterval
+	}
+	return &rateLimitCore{Core: core, limiter: &rateLimiter{config: config, root: core, keys: make(map[string]*rateLimitState)}}
+}
+
+// With returns a child core with given fields.
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitCore{Core: c.Core.With(fields), limiter: c.limiter}
+}
+
+// Check asks the wrapped core whether given entry should be written,
+// and if so, adds a writer which suppresses the entry exceeding the limit.
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level > zapcore.ErrorLevel {
+		return c.Core.Check(ent, ce)
+	}
+	downstream := c.Core.Check(ent, nil)
+	if downstream == nil {
+		return ce
+	}
+	w := &rateLimitWriter{Core: c.Core, limiter: c.limiter, downstream: downstream}
+	w.outer = ce.AddCore(ent, w)
+	return w.outer
+}
+
+// Sync writes summaries of suppressed entries and syncs the wrapped core.
+func (c *rateLimitCore) Sync() error {
+	c.limiter.summarizeAll()
+	return c.Core.Sync()
+}
+
+// rateLimitWriter is a zapcore.Core added to a checked entry by rateLimitCore.
+type rateLimitWriter struct {
+	zapcore.Core
+
+	limiter *rateLimiter
+
+	// downstream is a checked entry of the wrapped core.
+	downstream *zapcore.CheckedEntry
+
+	// outer is a checked entry of the logger which this writer is added to.
+	outer *zapcore.CheckedEntry
+}
+
+// Write writes given entry to the wrapped core unless it exceeds the limit of its key.
+func (w *rateLimitWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	key := ent.Message
+	for _, field := range fields {
+		if field.Type == zapcore.SkipType && field.Key == rateLimitKeyField {
+			key = field.String
+		}
+	}
+	if !w.limiter.allow(key, ent) {
+		return nil
+	}
+
+	w.downstream.Entry = ent
+	w.downstream.ErrorOutput = w.outer.ErrorOutput
+	w.downstream.Write(fields...)
+	return nil
+}
+
+// rateLimiter counts entries per key in fixed windows.
+type rateLimiter struct {
+	config RateLimitConfig
+
+	// root is a core which summary entries are written to.
+	root zapcore.Core
+
+	// mu guards fields below.
+	mu   sync.Mutex
+	keys map[string]*rateLimitState
+
+	// nextPrune is the time to remove expired keys next.
+	nextPrune time.Time
+}
+
+// rateLimitState is a state of a key in the current window.
+type rateLimitState struct {
+	start      time.Time
+	count      int
+	suppressed int
+
+	// last is the last suppressed entry, used to write the summary.
+	last zapcore.Entry
+
+	// timer writes the summary at the end of the window.
+	timer *time.Timer
+}
+
+// allow reports whether given entry of given key is within the limit.
+// If not, the entry is counted to the summary written at the end of the window.
+func (l *rateLimiter) allow(key string, ent zapcore.Entry) bool {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !now.Before(l.nextPrune) {
+		l.removeExpired(now)
+		l.nextPrune = now.Add(l.config.Interval)
+	}
+
+	state, ok := l.keys[key]
+	if !ok {
+		if len(l.keys) >= rateLimitMaxKeys {
+			key = rateLimitOverflowKey
+			state, ok = l.keys[key]
+		}
+		if !ok {
+			state = &rateLimitState{start: now}
+			l.keys[key] = state
+		}
+	}
+	if now.Sub(state.start) >= l.config.Interval && state.timer == nil {
+		state.start, state.count = now, 0
+	}
+
+	state.count++
+	if state.count <= l.config.Limit {
+		return true
+	}
+	state.suppressed++
+	state.last = ent
+	if state.timer == nil {
+		state.timer = time.AfterFunc(state.start.Add(l.config.Interval).Sub(now), func() {
+			l.summarize(key)
+		})
+	}
+	return false
+}
+
+// removeExpired removes keys whose window has ended without suppressed entries. l.mu must be held.
+// It is called once per interval, so that keys seen only once do not accumulate.
+func (l *rateLimiter) removeExpired(now time.Time) {
+	for key, state := range l.keys {
+		if state.timer == nil && now.Sub(state.start) >= l.config.Interval {
+			delete(l.keys, key)
+		}
+	}
+}
+
+// summarize writes the summary of given key, and starts a new window.
+func (l *rateLimiter) summarize(key string) {
+	l.mu.Lock()
+	state, ok := l.keys[key]
+	if !ok || state.suppressed == 0 {
+		l.mu.Unlock()
+		return
+	}
+	if state.timer != nil {
+		state.timer.Stop()
+		state.timer = nil
+	}
+	suppressed, last := state.suppressed, state.last
+	state.start, state.count, state.suppressed = time.Now(), 0, 0
+	l.mu.Unlock()
+
+	l.writeSummary(key, last, suppressed)
+}
+
+// summarizeAll writes summaries of every key which has suppressed entries.
+func (l *rateLimiter) summarizeAll() {
+	l.mu.Lock()
+	keys := make([]string, 0, len(l.keys))
+	for key, state := range l.keys {
+		if state.suppressed > 0 {
+			keys = append(keys, key)
+		}
+	}
+	l.mu.Unlock()
+
+	for _, key := range keys {
+		l.summarize(key)
+	}
+}
+
+// writeSummary writes an entry reporting the number of entries suppressed for given key.
+func (l *rateLimiter) writeSummary(key string, last zapcore.Entry, suppressed int) {
+	ent := zapcore.Entry{
+		Level:      last.Level,
+		Time:       time.Now(),
+		LoggerName: last.LoggerName,
+		Message:    "rate limited: " + last.Message,
+	}
+	if ce := l.root.Check(ent, nil); ce != nil {
+		ce.Write(zap.String("ratelimit_key", key), zap.Int("suppressed", suppressed))
+	}
+}
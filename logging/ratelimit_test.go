@@ -0,0 +1,133 @@
+package logging
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+)
+
+// lockedBuffer is a zaptest.Buffer which can be read while written concurrently.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf zaptest.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Sync() error {
+	return nil
+}
+
+func (b *lockedBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Lines()
+}
+
+func TestWithRateLimit(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf), WithRateLimit(RateLimitConfig{Limit: 2, Interval: time.Hour}))
+	for i := 0; i < 5; i++ {
+		logger.Info("noisy")
+		logger.Infow("keyed", "attempt", i, RateLimitKey("retry"))
+	}
+	logger.Info("other")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	var got []map[string]any
+	for _, line := range buf.Lines() {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("expect JSON entry, but received %v", err)
+		}
+		got = append(got, map[string]any{"msg": entry["msg"], "suppressed": entry["suppressed"], "ratelimit_key": entry["ratelimit_key"]})
+	}
+	want := []map[string]any{
+		{"msg": "noisy", "suppressed": nil, "ratelimit_key": nil},
+		{"msg": "keyed", "suppressed": nil, "ratelimit_key": nil},
+		{"msg": "noisy", "suppressed": nil, "ratelimit_key": nil},
+		{"msg": "keyed", "suppressed": nil, "ratelimit_key": nil},
+		{"msg": "other", "suppressed": nil, "ratelimit_key": nil},
+	}
+	if diff := cmp.Diff(want, got[:len(want)]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	summaries := map[string]any{}
+	for _, entry := range got[len(want):] {
+		summaries[entry["ratelimit_key"].(string)] = entry["suppressed"]
+	}
+	if diff := cmp.Diff(map[string]any{"noisy": 3.0, "retry": 3.0}, summaries); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestWithRateLimitInterval(t *testing.T) {
+	t.Parallel()
+
+	buf := &lockedBuffer{}
+	logger := NewLogger(WithWriteSyncer(buf), WithRateLimit(RateLimitConfig{Limit: 1, Interval: 10 * time.Millisecond}))
+	logger.Info("noisy")
+	logger.Info("noisy")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(buf.Lines()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	lines := buf.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("expect a summary at the end of the interval, but received %v", lines)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"msg": "rate limited: noisy", "suppressed": 1.0}, map[string]any{"msg": entry["msg"], "suppressed": entry["suppressed"]}); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	logger.Info("noisy")
+	if diff := cmp.Diff(3, len(buf.Lines())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestRateLimiterBoundsKeys(t *testing.T) {
+	t.Parallel()
+
+	l := &rateLimiter{
+		config: RateLimitConfig{Limit: 1, Interval: 10 * time.Millisecond},
+		root:   zapcore.NewNopCore(),
+		keys:   make(map[string]*rateLimitState),
+	}
+	for i := 0; i < 2*rateLimitMaxKeys; i++ {
+		l.allow(strconv.Itoa(i), zapcore.Entry{})
+	}
+	l.mu.Lock()
+	if n := len(l.keys); n > rateLimitMaxKeys+1 {
+		t.Errorf("expect at most %d keys, but received %d", rateLimitMaxKeys+1, n)
+	}
+	l.mu.Unlock()
+
+	// Keys seen once expire at the next interval.
+	time.Sleep(20 * time.Millisecond)
+	l.allow("next", zapcore.Entry{})
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if n := len(l.keys); n > 2 {
+		t.Errorf("expect expired keys to be removed, but received %d keys", n)
+	}
+}
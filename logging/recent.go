@@ -0,0 +1,174 @@
+package logging
+
+import (
+	"net/http"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultRecentEntries is a default number of entries retained by WithRecentEntries.
+const defaultRecentEntries = 1000
+
+// RecentEntry is an entry retained by WithRecentEntries.
+type RecentEntry struct {
+	zapcore.Entry
+
+	// Fields holds fields of the entry, including fields added via With.
+	Fields []zapcore.Field
+}
+
+// recentEntries is a ring buffer shared by every logger created with WithRecentEntries.
+var recentEntries = &ringBuffer{entries: make([]RecentEntry, defaultRecentEntries)}
+
+// WithRecentEntries makes the logger retain the last given number of entries in memory regardless of its level,
+// so that they can be read via RecentEntries or RecentEntriesHandler for postmortem debugging.
+// Entries of every logger created with this option are retained in the same buffer.
+// If size is not positive, 1000 is used. If loggers give different sizes, the last one is used.
+func WithRecentEntries(size int) Option {
+	return func(o *options) {
+		if size <= 0 {
+			size = defaultRecentEntries
+		}
+		recentEntries.resize(size)
+		o.cores = append(o.cores, &ringCore{buf: recentEntries})
+	}
+}
+
+// RecentEntries returns entries retained by loggers created with WithRecentEntries, from oldest to newest.
+func RecentEntries() []RecentEntry {
+	return recentEntries.snapshot()
+}
+
+// RecentEntriesHandler returns a http.Handler which dumps entries returned by RecentEntries
+// as newline delimited JSON, from oldest to newest.
+func RecentEntriesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+		for _, entry := range RecentEntries() {
+			buf, err := enc.EncodeEntry(entry.Entry, entry.Fields)
+			if err != nil {
+				continue
+			}
+			_, err = w.Write(buf.Bytes())
+			buf.Free()
+			if err != nil {
+				return
+			}
+		}
+	})
+}
+
+// ringBuffer holds the last entries up to its capacity.
+type ringBuffer struct {
+	// mu guards fields below.
+	mu      sync.Mutex
+	entries []RecentEntry
+
+	// next is an index of entries to write the next entry.
+	next int
+
+	// full reports whether entries has wrapped around.
+	full bool
+}
+
+// add retains given entry, overwriting the oldest one if full.
+func (b *ringBuffer) add(entry RecentEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries[b.next] = entry
+	b.next++
+	if b.next == len(b.entries) {
+		b.next = 0
+		b.full = true
+	}
+}
+
+// snapshot returns retained entries from oldest to newest.
+func (b *ringBuffer) snapshot() []RecentEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		return append([]RecentEntry(nil), b.entries[:b.next]...)
+	}
+	entries := make([]RecentEntry, 0, len(b.entries))
+	entries = append(entries, b.entries[b.next:]...)
+	entries = append(entries, b.entries[:b.next]...)
+	return entries
+}
+
+// resize changes the capacity, keeping the newest entries.
+func (b *ringBuffer) resize(size int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if size == len(b.entries) {
+		return
+	}
+	var current []RecentEntry
+	if b.full {
+		current = append(append(current, b.entries[b.next:]...), b.entries[:b.next]...)
+	} else {
+		current = b.entries[:b.next]
+	}
+	if len(current) > size {
+		current = current[len(current)-size:]
+	}
+
+	b.entries = make([]RecentEntry, size)
+	b.next = copy(b.entries, current)
+	b.full = b.next == size
+	if b.full {
+		b.next = 0
+	}
+}
+
+// ringCore is a zapcore.Core which retains every entry in a ring buffer.
+type ringCore struct {
+	buf *ringBuffer
+
+	// fields holds fields added via With.
+	fields []zapcore.Field
+}
+
+// Enabled reports true for every level.
+func (c *ringCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With returns a child core with given fields.
+func (c *ringCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &ringCore{buf: c.buf, fields: merged}
+}
+
+// Check adds the core to given checked entry.
+func (c *ringCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+// Write retains given entry with fields.
+func (c *ringCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(all, c.fields...)
+	all = append(all, fields...)
+	c.buf.add(RecentEntry{Entry: ent, Fields: all})
+	return nil
+}
+
+// Sync does nothing, because entries are kept in memory.
+func (c *ringCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+)
+
+// TestWithRecentEntries must not run in parallel, because entries are retained in the shared buffer.
+func TestWithRecentEntries(t *testing.T) {
+	buf := &zaptest.Buffer{}
+	logger := NewLogger(WithWriteSyncer(buf), WithLevel("info"), WithRecentEntries(2))
+	logger.Debug("first")
+	logger.With("key", "value").Debug("second")
+	logger.Info("third")
+
+	if diff := cmp.Diff(1, len(buf.Lines())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	var messages []string
+	for _, entry := range RecentEntries() {
+		messages = append(messages, entry.Message)
+	}
+	if diff := cmp.Diff([]string{"second", "third"}, messages); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	rec := httptest.NewRecorder()
+	RecentEntriesHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if diff := cmp.Diff("application/x-ndjson", rec.Header().Get("Content-Type")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expect 2 lines, but received %d", len(lines))
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"level": "debug", "msg": "second", "key": "value"}, map[string]any{
+		"level": entry["level"],
+		"msg":   entry["msg"],
+		"key":   entry["key"],
+	}); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	rec = httptest.NewRecorder()
+	RecentEntriesHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	if diff := cmp.Diff(http.StatusMethodNotAllowed, rec.Code); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestRingBufferResize(t *testing.T) {
+	t.Parallel()
+
+	messages := func(b *ringBuffer) []string {
+		var messages []string
+		for _, entry := range b.snapshot() {
+			messages = append(messages, entry.Message)
+		}
+		return messages
+	}
+
+	b := &ringBuffer{entries: make([]RecentEntry, 3)}
+	for _, msg := range []string{"1", "2", "3", "4"} {
+		b.add(RecentEntry{Entry: zapcore.Entry{Message: msg}})
+	}
+	b.resize(2)
+	if diff := cmp.Diff([]string{"3", "4"}, messages(b)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	b.resize(4)
+	b.add(RecentEntry{Entry: zapcore.Entry{Message: "5"}})
+	if diff := cmp.Diff([]string{"3", "4", "5"}, messages(b)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
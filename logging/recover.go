@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Recover recovers a panic and logs it at error level with the stack trace, using the logger from given context.
+// It must be called directly by defer, such as defer logging.Recover(ctx), because recover works only there.
+// The panic is stopped, and the function returns normally with its named results as they are at the panic.
+func Recover(ctx context.Context) {
+	if r := recover(); r != nil {
+		logPanic(ctx, r)
+	}
+}
+
+// RecoverAndRepanic recovers a panic, logs it like Recover, and panics again with the same value,
+// so that the panic is recorded with context fields even if it is handled by an outer recover or crashes the process.
+// It must be called directly by defer.
+func RecoverAndRepanic(ctx context.Context) {
+	if r := recover(); r != nil {
+		logPanic(ctx, r)
+		panic(r)
+	}
+}
+
+// RecoverToError recovers a panic, logs it like Recover, and sets an error describing the panic to err,
+// so that a function with a named error result returns the panic as an error.
+// If the panic value is an error, it is wrapped and can be inspected via errors.Is and errors.As.
+// It must be called directly by defer, such as defer logging.RecoverToError(ctx, &err).
+func RecoverToError(ctx context.Context, err *error) {
+	if r := recover(); r != nil {
+		logPanic(ctx, r)
+		*err = panicError(r)
+	}
+}
+
+// logPanic logs given panic value with the stack trace of the panicking goroutine.
+func logPanic(ctx context.Context, r any) {
+	// skip logPanic and the deferred function, so that the stack trace starts from the panic.
+	StructuredFromContext(ctx).Error("recovered from panic", zap.Any("panic", r), zap.StackSkip("panic_stack", 2))
+}
+
+// panicError converts given panic value to an error.
+func panicError(r any) error {
+	if err, ok := r.(error); ok {
+		return fmt.Errorf("panic: %w", err)
+	}
+	return fmt.Errorf("panic: %v", r)
+}
@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := WithFields(WithStructuredLogger(context.Background(), zap.New(core)), "request_id", "r1")
+
+	func() {
+		defer Recover(ctx)
+		panic("boom")
+	}()
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if diff := cmp.Diff("boom", fields["panic"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("r1", fields["request_id"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if stack, _ := fields["panic_stack"].(string); !strings.Contains(stack, "TestRecover") {
+		t.Errorf("expect stack trace of the panic, but received %q", stack)
+	}
+}
+
+func TestRecoverAndRepanic(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := WithStructuredLogger(context.Background(), zap.New(core))
+
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		defer RecoverAndRepanic(ctx)
+		panic("boom")
+	}()
+
+	if diff := cmp.Diff("boom", recovered); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if logs.Len() != 1 {
+		t.Errorf("expect 1 entry, but received %d", logs.Len())
+	}
+}
+
+func TestRecoverToError(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := WithStructuredLogger(context.Background(), zap.New(core))
+	cause := errors.New("boom")
+
+	run := func(v any) (err error) {
+		defer RecoverToError(ctx, &err)
+		if v != nil {
+			panic(v)
+		}
+		return nil
+	}
+
+	if err := run(nil); err != nil {
+		t.Errorf("expect no error, but received %v", err)
+	}
+	if err := run(cause); !errors.Is(err, cause) {
+		t.Errorf("expect error wrapping %v, but received %v", cause, err)
+	}
+	if err := run(42); err == nil || err.Error() != "panic: 42" {
+		t.Errorf("expect panic: 42, but received %v", err)
+	}
+	if logs.Len() != 2 {
+		t.Errorf("expect 2 entries, but received %d", logs.Len())
+	}
+}
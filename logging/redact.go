@@ -0,0 +1,336 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedMask is a value written in place of redacted values.
+const redactedMask = "[REDACTED]"
+
+// DefaultRedactedKeys is a list of field keys redacted by default.
+var DefaultRedactedKeys = []string{"password", "token", "authorization", "api_key"}
+
+// Redactor masks secret values in entries before they are encoded.
+// Values of fields whose key contains one of configured keys are replaced entirely,
+// and substrings of messages and string values matching configured patterns are masked.
+type Redactor struct {
+	keys []string
+
+	// masks are functions applied in order to messages and string values.
+	masks []func(string) string
+}
+
+// NewRedactor creates a Redactor with given keys and patterns.
+// Keys are matched case-insensitively as substrings of field keys, e.g. "token" matches "refresh_token".
+// If keys is nil, DefaultRedactedKeys is used.
+func NewRedactor(keys []string, patterns ...*regexp.Regexp) *Redactor {
+	if keys == nil {
+		keys = DefaultRedactedKeys
+	}
+	lowered := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			lowered = append(lowered, key)
+		}
+	}
+
+	masks := make([]func(string) string, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern := pattern
+		masks = append(masks, func(s string) string {
+			return pattern.ReplaceAllString(s, redactedMask)
+		})
+	}
+	return &Redactor{keys: lowered, masks: masks}
+}
+
+// WithRedaction makes the logger redact entries with given redactor before they are encoded.
+// Redaction is applied before scrubbers given via WithScrubbers.
+func WithRedaction(r *Redactor) Option {
+	return func(o *options) {
+		o.redactor = r
+	}
+}
+
+// NewRedactionCore wraps given core, so that entries are redacted with given redactor before written.
+func NewRedactionCore(core zapcore.Core, r *Redactor) zapcore.Core {
+	return NewScrubCore(core, r)
+}
+
+// String masks substrings of given string matching configured patterns.
+func (r *Redactor) String(s string) string {
+	for _, mask := range r.masks {
+		s = mask(s)
+	}
+	return s
+}
+
+// Fields returns redacted copy of given fields.
+func (r *Redactor) Fields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = r.Field(field)
+	}
+	return redacted
+}
+
+// Field returns redacted copy of given field.
+func (r *Redactor) Field(field zapcore.Field) zapcore.Field {
+	switch field.Type {
+	case zapcore.NamespaceType, zapcore.SkipType:
+		return field
+	}
+	if r.secretKey(field.Key) {
+		return zap.String(field.Key, redactedMask)
+	}
+
+	switch field.Type {
+	case zapcore.StringType:
+		field.String = r.String(field.String)
+	case zapcore.ByteStringType:
+		if b, ok := field.Interface.([]byte); ok {
+			return zap.ByteString(field.Key, []byte(r.String(string(b))))
+		}
+	case zapcore.ErrorType:
+		if err, ok := field.Interface.(error); ok {
+			if msg := err.Error(); r.String(msg) != msg {
+				return zap.String(field.Key, r.String(msg))
+			}
+		}
+	case zapcore.StringerType:
+		if s, ok := field.Interface.(fmt.Stringer); ok {
+			if msg := s.String(); r.String(msg) != msg {
+				return zap.String(field.Key, r.String(msg))
+			}
+		}
+	case zapcore.ObjectMarshalerType:
+		if m, ok := field.Interface.(zapcore.ObjectMarshaler); ok {
+			return zap.Object(field.Key, redactedObject{m: m, r: r})
+		}
+	case zapcore.ReflectType:
+		switch v := field.Interface.(type) {
+		case map[string]any:
+			return zap.Object(field.Key, redactedMap[any]{m: v, r: r})
+		case map[string]string:
+			return zap.Object(field.Key, redactedMap[string]{m: v, r: r})
+		}
+	}
+	return field
+}
+
+// Scrub redacts given entry and fields. It implements Scrubber.
+func (r *Redactor) Scrub(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	ent.Message = r.String(ent.Message)
+	return ent, r.Fields(fields)
+}
+
+// secretKey reports whether values of given key should be redacted.
+func (r *Redactor) secretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range r.keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactedObject is a zapcore.ObjectMarshaler which redacts nested fields of wrapped marshaler.
+type redactedObject struct {
+	m zapcore.ObjectMarshaler
+	r *Redactor
+}
+
+// MarshalLogObject encodes wrapped marshaler via redacting encoder.
+func (o redactedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.m.MarshalLogObject(&redactEncoder{ObjectEncoder: enc, r: o.r})
+}
+
+// redactedMap is a zapcore.ObjectMarshaler which redacts values of a map.
+type redactedMap[V any] struct {
+	m map[string]V
+	r *Redactor
+}
+
+// MarshalLogObject encodes entries of the map via redacting encoder.
+func (m redactedMap[V]) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for k, v := range m.m {
+		m.r.Field(zap.Any(k, v)).AddTo(enc)
+	}
+	return nil
+}
+
+// redactEncoder is a zapcore.ObjectEncoder which redacts values before passing them to wrapped encoder.
+// Each method writes the mask instead of the value if its key is secret.
+type redactEncoder struct {
+	zapcore.ObjectEncoder
+	r *Redactor
+}
+
+func (e *redactEncoder) AddArray(key string, v zapcore.ArrayMarshaler) error {
+	if e.masked(key) {
+		return nil
+	}
+	return e.ObjectEncoder.AddArray(key, v)
+}
+
+func (e *redactEncoder) AddObject(key string, v zapcore.ObjectMarshaler) error {
+	if e.masked(key) {
+		return nil
+	}
+	return e.ObjectEncoder.AddObject(key, redactedObject{m: v, r: e.r})
+}
+
+func (e *redactEncoder) AddBinary(key string, v []byte) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddBinary(key, v)
+	}
+}
+
+func (e *redactEncoder) AddByteString(key string, v []byte) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddByteString(key, []byte(e.r.String(string(v))))
+	}
+}
+
+func (e *redactEncoder) AddBool(key string, v bool) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddBool(key, v)
+	}
+}
+
+func (e *redactEncoder) AddComplex128(key string, v complex128) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddComplex128(key, v)
+	}
+}
+
+func (e *redactEncoder) AddComplex64(key string, v complex64) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddComplex64(key, v)
+	}
+}
+
+func (e *redactEncoder) AddDuration(key string, v time.Duration) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddDuration(key, v)
+	}
+}
+
+func (e *redactEncoder) AddFloat64(key string, v float64) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddFloat64(key, v)
+	}
+}
+
+func (e *redactEncoder) AddFloat32(key string, v float32) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddFloat32(key, v)
+	}
+}
+
+func (e *redactEncoder) AddInt(key string, v int) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddInt(key, v)
+	}
+}
+
+func (e *redactEncoder) AddInt64(key string, v int64) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddInt64(key, v)
+	}
+}
+
+func (e *redactEncoder) AddInt32(key string, v int32) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddInt32(key, v)
+	}
+}
+
+func (e *redactEncoder) AddInt16(key string, v int16) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddInt16(key, v)
+	}
+}
+
+func (e *redactEncoder) AddInt8(key string, v int8) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddInt8(key, v)
+	}
+}
+
+func (e *redactEncoder) AddString(key, v string) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddString(key, e.r.String(v))
+	}
+}
+
+func (e *redactEncoder) AddTime(key string, v time.Time) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddTime(key, v)
+	}
+}
+
+func (e *redactEncoder) AddUint(key string, v uint) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddUint(key, v)
+	}
+}
+
+func (e *redactEncoder) AddUint64(key string, v uint64) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddUint64(key, v)
+	}
+}
+
+func (e *redactEncoder) AddUint32(key string, v uint32) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddUint32(key, v)
+	}
+}
+
+func (e *redactEncoder) AddUint16(key string, v uint16) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddUint16(key, v)
+	}
+}
+
+func (e *redactEncoder) AddUint8(key string, v uint8) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddUint8(key, v)
+	}
+}
+
+func (e *redactEncoder) AddUintptr(key string, v uintptr) {
+	if !e.masked(key) {
+		e.ObjectEncoder.AddUintptr(key, v)
+	}
+}
+
+func (e *redactEncoder) AddReflected(key string, v any) error {
+	if e.masked(key) {
+		return nil
+	}
+	switch m := v.(type) {
+	case map[string]any:
+		return e.ObjectEncoder.AddObject(key, redactedMap[any]{m: m, r: e.r})
+	case map[string]string:
+		return e.ObjectEncoder.AddObject(key, redactedMap[string]{m: m, r: e.r})
+	}
+	return e.ObjectEncoder.AddReflected(key, v)
+}
+
+// masked writes the mask for given key and reports true if the key is secret.
+func (e *redactEncoder) masked(key string) bool {
+	if e.r.secretKey(key) {
+		e.ObjectEncoder.AddString(key, redactedMask)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// credentials is a zapcore.ObjectMarshaler used to test nested redaction.
+type credentials struct {
+	user     string
+	password string
+}
+
+func (c credentials) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("user", c.user)
+	enc.AddString("password", c.password)
+	return nil
+}
+
+func TestWithRedaction(t *testing.T) {
+	t.Parallel()
+
+	buf := &zaptest.Buffer{}
+	card := regexp.MustCompile(`\b\d{16}\b`)
+	logger := NewLogger(WithWriteSyncer(buf), WithRedaction(NewRedactor(nil, card))).With("api_key", "k-1")
+
+	logger.Infow("charged 4111111111111111",
+		"password", "p-1",
+		"refresh_token", "t-1",
+		"user", "u-1",
+		"credentials", credentials{user: "u-2", password: "p-2"},
+		"headers", map[string]string{"Authorization": "Bearer t-2", "Accept": "*/*"},
+		"error", errors.New("card 4111111111111111 declined"),
+	)
+	_ = logger.Sync()
+
+	lines := buf.Lines()
+	if len(lines) != 1 {
+		t.Fatalf("expect 1 line, but received %d", len(lines))
+	}
+	for _, secret := range []string{"k-1", "p-1", "t-1", "p-2", "t-2", "4111111111111111"} {
+		if strings.Contains(lines[0], secret) {
+			t.Errorf("expect %q redacted, but received %s", secret, lines[0])
+		}
+	}
+	for _, visible := range []string{`"user":"u-1"`, `"user":"u-2"`, `"Accept":"*/*"`, `"msg":"charged [REDACTED]"`} {
+		if !strings.Contains(lines[0], visible) {
+			t.Errorf("expect %s, but received %s", visible, lines[0])
+		}
+	}
+}
+
+func TestNewRedactionCore(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(NewRedactionCore(core, NewRedactor([]string{"secret"})))
+
+	logger.Debug("ignored", zap.String("secret", "s-1"))
+	logger.With(zap.String("Secret", "s-2")).Info("message", zap.String("password", "p-1"))
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	want := map[string]any{"Secret": "[REDACTED]", "password": "p-1"}
+	if diff := cmp.Diff(want, entries[0].ContextMap()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// RedisLogger is a logger which formats messages with context.
+// It satisfies the logger of github.com/redis/go-redis, given to redis.SetLogger.
+type RedisLogger interface {
+	Printf(ctx context.Context, format string, v ...any)
+}
+
+// redisLogger is an implementation of RedisLogger backed by zap sugared logger.
+type redisLogger struct {
+	// logger is a logger to write entries. If nil, the logger from the context of each call is used.
+	logger *zap.SugaredLogger
+}
+
+// NewRedisLogger creates a RedisLogger which writes entries via given logger at warn level,
+// because go-redis logs only failures such as dial errors and discarded connections.
+// If logger is nil, the logger from the context given by go-redis is used for each call.
+func NewRedisLogger(logger *zap.SugaredLogger) RedisLogger {
+	if logger != nil {
+		logger = logger.WithOptions(zap.AddCallerSkip(1))
+	}
+	return &redisLogger{logger: logger}
+}
+
+// Printf writes given message at warn level.
+func (l *redisLogger) Printf(ctx context.Context, format string, v ...any) {
+	logger := l.logger
+	if logger == nil {
+		logger = FromContext(ctx).WithOptions(zap.AddCallerSkip(1))
+	}
+	logger.Warnf(format, v...)
+}
@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// reopenableFiles holds files opened by loggers, so that ReopenFiles can reopen them.
+var reopenableFiles struct {
+	mu    sync.Mutex
+	files map[*RotatingFile]struct{}
+}
+
+// ReopenFiles reopens every file written by loggers created by the package, including files added via WithRotatingFile.
+// Call it after an external tool such as logrotate renames the files. HandleSignals calls it on SIGHUP.
+func ReopenFiles() error {
+	reopenableFiles.mu.Lock()
+	files := make([]*RotatingFile, 0, len(reopenableFiles.files))
+	for f := range reopenableFiles.files {
+		files = append(files, f)
+	}
+	reopenableFiles.mu.Unlock()
+
+	var err error
+	for _, f := range files {
+		err = multierr.Append(err, f.Reopen())
+	}
+	return err
+}
+
+// registerReopenable registers given file to be reopened by ReopenFiles,
+// and returns a function which unregisters and closes the file.
+func registerReopenable(f *RotatingFile) closeFunc {
+	reopenableFiles.mu.Lock()
+	if reopenableFiles.files == nil {
+		reopenableFiles.files = make(map[*RotatingFile]struct{})
+	}
+	reopenableFiles.files[f] = struct{}{}
+	reopenableFiles.mu.Unlock()
+
+	return func(context.Context) error {
+		reopenableFiles.mu.Lock()
+		delete(reopenableFiles.files, f)
+		reopenableFiles.mu.Unlock()
+		return f.Close()
+	}
+}
+
+// openPaths opens given output paths as zap.Open does, except that files are opened as reopenable files.
+// It also returns a function to close opened paths, or nil if only stdout and stderr are opened,
+// which are never closed.
+func openPaths(paths []string) (zapcore.WriteSyncer, closeFunc, error) {
+	var writers []zapcore.WriteSyncer
+	var closers []closeFunc
+	closeAll := func(ctx context.Context) error {
+		var err error
+		for _, fn := range closers {
+			err = multierr.Append(err, fn(ctx))
+		}
+		return err
+	}
+
+	var others []string
+	for _, path := range paths {
+		file, ok := filePath(path)
+		if !ok {
+			others = append(others, path)
+			continue
+		}
+		f := NewRotatingFile(RotatingFileConfig{Path: file})
+		if err := f.Reopen(); err != nil {
+			_ = closeAll(context.Background())
+			return nil, nil, err
+		}
+		writers = append(writers, f)
+		closers = append(closers, registerReopenable(f))
+	}
+
+	if len(others) > 0 {
+		registerNetworkSinks()
+		ws, closeOthers, err := zap.Open(others...)
+		if err != nil {
+			_ = closeAll(context.Background())
+			return nil, nil, err
+		}
+		writers = append(writers, ws)
+		if !stdStreams(others) {
+			closers = append(closers, closeOpened(closeOthers))
+		}
+	}
+	if len(closers) == 0 {
+		return zap.CombineWriteSyncers(writers...), nil, nil
+	}
+	return zap.CombineWriteSyncers(writers...), closeAll, nil
+}
+
+// stdStreams reports whether every given output path is stdout or stderr.
+func stdStreams(paths []string) bool {
+	for _, path := range paths {
+		if path != "stdout" && path != "stderr" {
+			return false
+		}
+	}
+	return true
+}
+
+// filePath returns the path of the file if given output path refers to a file,
+// such as "/var/log/app.log" or "file:///var/log/app.log".
+func filePath(path string) (string, bool) {
+	switch path {
+	case "stdout", "stderr":
+		return "", false
+	}
+	if strings.HasPrefix(path, "file://") {
+		u, err := url.Parse(path)
+		if err != nil || u.Path == "" || u.RawQuery != "" || u.Fragment != "" {
+			return "", false
+		}
+		return u.Path, true
+	}
+	if strings.Contains(path, "://") {
+		return "", false
+	}
+	return path, true
+}
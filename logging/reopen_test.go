@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReopenFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	logger := NewLogger(WithOutputPaths("file://" + filepath.ToSlash(path)))
+	logger.Info("before")
+
+	rotated := filepath.Join(dir, "app.log.1")
+	if err := os.Rename(path, rotated); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReopenFiles(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	logger.Info("after")
+
+	for file, want := range map[string]string{rotated: "before", path: "after"} {
+		entries := readEntries(t, file)
+		if len(entries) != 1 {
+			t.Fatalf("expect 1 entry in %s, but received %d", file, len(entries))
+		}
+		if diff := cmp.Diff(want, entries[0]["msg"]); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+}
+
+func TestFilePath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{path: "stdout"},
+		{path: "stderr"},
+		{path: "tcp://localhost:5000"},
+		{path: "/var/log/app.log", want: "/var/log/app.log", ok: true},
+		{path: "app.log", want: "app.log", ok: true},
+		{path: "file:///var/log/app.log", want: "/var/log/app.log", ok: true},
+	}
+	for _, tt := range tests {
+		got, ok := filePath(tt.path)
+		if diff := cmp.Diff(tt.ok, ok); diff != "" {
+			t.Errorf("%s: (-want, +got)\n%s", tt.path, diff)
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("%s: (-want, +got)\n%s", tt.path, diff)
+		}
+	}
+}
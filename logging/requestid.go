@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// requestIDField is a field key of request ID.
+const requestIDField = "request_id"
+
+// WithRequestID stores a given request ID to given context.
+// The logger returned by FromContext contains request_id field.
+// If context is nil, it will panic. because, this function is wrapper for context.WithValue.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return withContextLogger(ctx, func(c *contextLogger) {
+		c.requestID = id
+	})
+}
+
+// RequestIDFromContext returns a request ID stored in given context.
+// If not contained request ID from given context, it will return false as second value.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	stored := loggerFromContext(ctx)
+	if stored == nil || stored.requestID == "" {
+		return "", false
+	}
+	return stored.requestID, true
+}
+
+// EnsureRequestID returns a context which has a request ID and the request ID.
+// If given context already has a request ID, it will return given context as it is.
+// Otherwise, it will generate a new request ID and store it to the returned context.
+func EnsureRequestID(ctx context.Context) (context.Context, string) {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return ctx, id
+	}
+	id := NewRequestID()
+	return WithRequestID(ctx, id), id
+}
+
+// NewRequestID generates a new random request ID formatted as UUID version 4.
+func NewRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand never fails on supported platforms, but keep request ID non-empty just in case.
+		return "00000000-0000-4000-8000-000000000000"
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
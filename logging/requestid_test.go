@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithRequestID(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := WithLogger(context.Background(), zap.New(core).Sugar())
+	ctx = WithRequestID(ctx, "req-1")
+
+	id, ok := RequestIDFromContext(ctx)
+	if !ok {
+		t.Fatal("expect request ID, but not found")
+	}
+	if diff := cmp.Diff("req-1", id); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	FromContext(ctx).Info("sugared")
+	StructuredFromContext(ctx).Info("structured")
+
+	if diff := cmp.Diff(2, logs.FilterField(zap.String("request_id", "req-1")).Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestEnsureRequestID(t *testing.T) {
+	t.Parallel()
+
+	ctx, id := EnsureRequestID(context.Background())
+	if id == "" {
+		t.Fatal("expect generated request ID, but received empty")
+	}
+
+	ctx2, id2 := EnsureRequestID(ctx)
+	if ctx2 != ctx {
+		t.Error("expect same context, but received different context")
+	}
+	if diff := cmp.Diff(id, id2); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestNewRequestID(t *testing.T) {
+	t.Parallel()
+
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	id1 := NewRequestID()
+	if !pattern.MatchString(id1) {
+		t.Errorf("expect UUID version 4, but received %q", id1)
+	}
+	if id1 == NewRequestID() {
+		t.Error("expect different request IDs, but received same one")
+	}
+}
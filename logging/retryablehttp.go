@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+)
+
+// LeveledLogger is a logger with a method per level, which accepts key-value pairs.
+// It satisfies retryablehttp.LeveledLogger of github.com/hashicorp/go-retryablehttp.
+type LeveledLogger interface {
+	Error(msg string, keysAndValues ...any)
+	Info(msg string, keysAndValues ...any)
+	Debug(msg string, keysAndValues ...any)
+	Warn(msg string, keysAndValues ...any)
+}
+
+// leveledLogger is an implementation of LeveledLogger backed by zap sugared logger.
+type leveledLogger struct {
+	logger *zap.SugaredLogger
+}
+
+// NewLeveledLogger creates a LeveledLogger which writes entries via given logger,
+// such as for retryablehttp.Client.Logger. If logger is nil, default logger is used.
+func NewLeveledLogger(logger *zap.SugaredLogger) LeveledLogger {
+	if logger == nil {
+		logger = DefaultLogger()
+	}
+	return &leveledLogger{logger: logger.WithOptions(zap.AddCallerSkip(1))}
+}
+
+// Error writes given message at error level.
+func (l *leveledLogger) Error(msg string, keysAndValues ...any) {
+	l.logger.Errorw(msg, keysAndValues...)
+}
+
+// Info writes given message at info level.
+func (l *leveledLogger) Info(msg string, keysAndValues ...any) {
+	l.logger.Infow(msg, keysAndValues...)
+}
+
+// Debug writes given message at debug level.
+func (l *leveledLogger) Debug(msg string, keysAndValues ...any) {
+	l.logger.Debugw(msg, keysAndValues...)
+}
+
+// Warn writes given message at warn level.
+func (l *leveledLogger) Warn(msg string, keysAndValues ...any) {
+	l.logger.Warnw(msg, keysAndValues...)
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLeveledLogger(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	logger := NewLeveledLogger(zap.New(core, zap.AddCaller()).Sugar())
+	logger.Debug("debug", "attempt", 1)
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	var levels []zapcore.Level
+	for _, entry := range logs.AllUntimed() {
+		levels = append(levels, entry.Level)
+		if diff := cmp.Diff("retryablehttp_test.go", filepath.Base(entry.Caller.File)); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+	want := []zapcore.Level{zapcore.DebugLevel, zapcore.InfoLevel, zapcore.WarnLevel, zapcore.ErrorLevel}
+	if diff := cmp.Diff(want, levels); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"attempt": int64(1)}, logs.AllUntimed()[0].ContextMap()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
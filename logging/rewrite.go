@@ -0,0 +1,65 @@
+package logging
+
+import "go.uber.org/zap/zapcore"
+
+// rewriteFunc rewrites an entry and its fields before they are written.
+type rewriteFunc func(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field)
+
+// rewriteCore is a zapcore.Core which rewrites entries and fields before the wrapped core writes them.
+// The wrapped core still decides which entries are written, so that level filtering of tee and
+// component aware cores is kept as it is.
+type rewriteCore struct {
+	zapcore.Core
+
+	// rewrite rewrites entries and fields given to Write.
+	rewrite rewriteFunc
+
+	// rewriteWith rewrites fields given to With.
+	rewriteWith func(fields []zapcore.Field) []zapcore.Field
+}
+
+// newRewriteCore wraps given core, so that entries and fields are rewritten before written.
+func newRewriteCore(core zapcore.Core, rewrite rewriteFunc, rewriteWith func([]zapcore.Field) []zapcore.Field) zapcore.Core {
+	return &rewriteCore{Core: core, rewrite: rewrite, rewriteWith: rewriteWith}
+}
+
+// With returns a child core with given fields rewritten.
+func (c *rewriteCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rewriteCore{Core: c.Core.With(c.rewriteWith(fields)), rewrite: c.rewrite, rewriteWith: c.rewriteWith}
+}
+
+// Check asks the wrapped core whether given entry should be written,
+// and if so, adds a writer which rewrites the entry before passing it to the wrapped core.
+func (c *rewriteCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	downstream := c.Core.Check(ent, nil)
+	if downstream == nil {
+		return ce
+	}
+	w := &rewriteWriter{Core: c.Core, downstream: downstream, rewrite: c.rewrite}
+	w.outer = ce.AddCore(ent, w)
+	return w.outer
+}
+
+// rewriteWriter is a zapcore.Core added to a checked entry by rewriteCore.
+// It writes rewritten entry via the checked entry of the wrapped core.
+type rewriteWriter struct {
+	zapcore.Core
+
+	// downstream is a checked entry of the wrapped core.
+	downstream *zapcore.CheckedEntry
+
+	// outer is a checked entry of the logger which this writer is added to.
+	outer *zapcore.CheckedEntry
+
+	rewrite rewriteFunc
+}
+
+// Write rewrites given entry and fields, and writes them to the wrapped core.
+// Errors of the wrapped core are reported to the error output of the logger.
+func (w *rewriteWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent, fields = w.rewrite(ent, fields)
+	w.downstream.Entry = ent
+	w.downstream.ErrorOutput = w.outer.ErrorOutput
+	w.downstream.Write(fields...)
+	return nil
+}
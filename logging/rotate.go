@@ -0,0 +1,345 @@
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// megabyte is a number of bytes in a megabyte.
+	megabyte = 1024 * 1024
+
+	// backupTimeFormat is a time format used in the name of rotated files.
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+
+	// compressSuffix is a suffix of compressed rotated files.
+	compressSuffix = ".gz"
+)
+
+// RotatingFileConfig is a configuration of RotatingFile.
+type RotatingFileConfig struct {
+	// Path is a path of the file to write logs.
+	Path string
+
+	// MaxSizeMB is a maximum size in megabytes of the file before it gets rotated.
+	// Zero value means no size based rotation.
+	MaxSizeMB int
+
+	// MaxBackups is a maximum number of rotated files to keep.
+	// Zero value means all rotated files are kept.
+	MaxBackups int
+
+	// MaxAgeDays is a maximum number of days to keep rotated files.
+	// Zero value means rotated files are not removed based on age.
+	MaxAgeDays int
+
+	// Compress reports whether rotated files are compressed with gzip.
+	Compress bool
+}
+
+// RotatingFile is a zapcore.WriteSyncer which writes logs to a file and rotates it.
+// Rotated files are renamed with the time of rotation, such as "app-2006-01-02T15-04-05.000.log".
+type RotatingFile struct {
+	config RotatingFileConfig
+
+	// now returns current time. It is replaced in tests.
+	now func() time.Time
+
+	// mu guards file and size.
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	// millMu serializes cleanup of rotated files.
+	millMu sync.Mutex
+
+	// millWg tracks running cleanup of rotated files.
+	millWg sync.WaitGroup
+}
+
+var _ zapcore.WriteSyncer = (*RotatingFile)(nil)
+
+// NewRotatingFile creates a RotatingFile with given configuration.
+// The file is opened lazily on first write, and parent directories are created if needed.
+func NewRotatingFile(config RotatingFileConfig) *RotatingFile {
+	return &RotatingFile{config: config, now: time.Now}
+}
+
+// Write writes given bytes to the file.
+// If the file would exceed its maximum size, it is rotated before writing.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if limit := f.maxSize(); limit > 0 && f.size > 0 && f.size+int64(len(p)) > limit {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Sync flushes the file to the disk.
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+// Rotate rotates the file immediately.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return err
+		}
+	}
+	return f.rotate()
+}
+
+// Reopen closes the file and opens the file of the same path again,
+// so that the file renamed by an external tool such as logrotate is released.
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.close(); err != nil {
+		return fmt.Errorf("logging: failed to close log file: %w", err)
+	}
+	return f.open()
+}
+
+// Close closes the file and waits for running cleanup of rotated files.
+// The file is opened again on next write.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	err := f.close()
+	f.mu.Unlock()
+
+	f.millWg.Wait()
+	return err
+}
+
+// Path returns the path of the file.
+func (f *RotatingFile) Path() string {
+	return f.config.Path
+}
+
+// maxSize returns the maximum size of the file in bytes.
+func (f *RotatingFile) maxSize() int64 {
+	return int64(f.config.MaxSizeMB) * megabyte
+}
+
+// open opens the file in append mode. f.mu must be held.
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.config.Path), 0o755); err != nil {
+		return fmt.Errorf("logging: failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(f.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("logging: failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("logging: failed to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// close closes the file. f.mu must be held.
+func (f *RotatingFile) close() error {
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	f.size = 0
+	return err
+}
+
+// rotate renames the current file to a backup name and opens a new file. f.mu must be held.
+func (f *RotatingFile) rotate() error {
+	if err := f.close(); err != nil {
+		return fmt.Errorf("logging: failed to close log file: %w", err)
+	}
+	if err := os.Rename(f.config.Path, f.backupName(f.now())); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("logging: failed to rename log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.millWg.Add(1)
+	go func() {
+		defer f.millWg.Done()
+		f.mill()
+	}()
+	return nil
+}
+
+// backupName returns a name of rotated file at given time.
+// If a rotated file of the same time exists, a counter is added to the name, such as "app-<time>-1.log",
+// because names have only millisecond resolution.
+func (f *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := f.nameParts()
+	stamp := t.UTC().Format(backupTimeFormat)
+	name := filepath.Join(dir, prefix+stamp+ext)
+	for i := 1; fileExists(name) || fileExists(name+compressSuffix); i++ {
+		name = filepath.Join(dir, prefix+stamp+"-"+strconv.Itoa(i)+ext)
+	}
+	return name
+}
+
+// fileExists reports whether a file exists at given path.
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// nameParts splits the path of the file into directory, prefix of rotated files, and extension.
+func (f *RotatingFile) nameParts() (string, string, string) {
+	dir := filepath.Dir(f.config.Path)
+	base := filepath.Base(f.config.Path)
+	ext := filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// backup is a rotated file and its rotation time.
+type backup struct {
+	path string
+	time time.Time
+
+	// seq is the counter of files rotated at the same time, or zero.
+	seq int
+}
+
+// mill compresses rotated files and removes ones exceeding the limits of count or age.
+func (f *RotatingFile) mill() {
+	f.millMu.Lock()
+	defer f.millMu.Unlock()
+
+	backups, err := f.backups()
+	if err != nil {
+		return
+	}
+
+	var cutoff time.Time
+	if f.config.MaxAgeDays > 0 {
+		cutoff = f.now().Add(-time.Duration(f.config.MaxAgeDays) * 24 * time.Hour)
+	}
+
+	for i, b := range backups {
+		if (f.config.MaxBackups > 0 && i >= f.config.MaxBackups) || (!cutoff.IsZero() && b.time.Before(cutoff)) {
+			_ = os.Remove(b.path)
+			continue
+		}
+		if f.config.Compress && !strings.HasSuffix(b.path, compressSuffix) {
+			_ = compressFile(b.path)
+		}
+	}
+}
+
+// backups returns rotated files sorted by rotation time in descending order.
+func (f *RotatingFile) backups() ([]backup, error) {
+	dir, prefix, ext := f.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), compressSuffix), ext)
+		if len(stamp) < len(backupTimeFormat) {
+			continue
+		}
+		t, err := time.Parse(backupTimeFormat, stamp[:len(backupTimeFormat)])
+		if err != nil {
+			continue
+		}
+		var seq int
+		if counter := stamp[len(backupTimeFormat):]; counter != "" {
+			if seq, err = strconv.Atoi(strings.TrimPrefix(counter, "-")); err != nil || !strings.HasPrefix(counter, "-") || seq <= 0 {
+				continue
+			}
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), time: t, seq: seq})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].time.Equal(backups[j].time) {
+			return backups[i].time.After(backups[j].time)
+		}
+		return backups[i].seq > backups[j].seq
+	})
+	return backups, nil
+}
+
+// compressFile compresses given file with gzip and removes the original file.
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(path + compressSuffix)
+		}
+	}()
+
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	_ = src.Close()
+	return os.Remove(path)
+}
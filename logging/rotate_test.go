@@ -0,0 +1,185 @@
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// listDir returns sorted names of files in given directory.
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRotatingFileSize(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	f := NewRotatingFile(RotatingFileConfig{Path: filepath.Join(dir, "app.log"), MaxSizeMB: 1})
+	f.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+	t.Cleanup(func() { _ = f.Close() })
+
+	chunk := bytes.Repeat([]byte("a"), megabyte/2+1)
+	for i := 0; i < 2; i++ {
+		if _, err := f.Write(chunk); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"app-2024-01-02T03-04-05.000.log", "app.log"}, listDir(t, dir)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff(int64(len(chunk)), info.Size()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestRotatingFileBackups(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	f := NewRotatingFile(RotatingFileConfig{Path: filepath.Join(dir, "app.log"), MaxBackups: 2, Compress: true})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { return now }
+	t.Cleanup(func() { _ = f.Close() })
+
+	for i := 0; i < 3; i++ {
+		if _, err := f.Write([]byte("line\n")); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+		if err := f.Rotate(); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+		f.millWg.Wait()
+		now = now.Add(time.Hour)
+	}
+
+	want := []string{
+		"app-2024-01-01T01-00-00.000.log.gz",
+		"app-2024-01-01T02-00-00.000.log.gz",
+		"app.log",
+	}
+	if diff := cmp.Diff(want, listDir(t, dir)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	file, err := os.Open(filepath.Join(dir, want[0]))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff("line\n", string(data)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestRotatingFileSameTime(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	f := NewRotatingFile(RotatingFileConfig{Path: filepath.Join(dir, "app.log"), MaxBackups: 2})
+	f.now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { _ = f.Close() })
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+		if err := f.Rotate(); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+		f.millWg.Wait()
+	}
+
+	want := []string{
+		"app-2024-01-01T00-00-00.000-1.log",
+		"app-2024-01-01T00-00-00.000-2.log",
+		"app.log",
+	}
+	if diff := cmp.Diff(want, listDir(t, dir)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, want[1]))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff("third\n", string(data)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestRotatingFileMaxAge(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	f := NewRotatingFile(RotatingFileConfig{Path: filepath.Join(dir, "app.log"), MaxAgeDays: 1})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { return now }
+	t.Cleanup(func() { _ = f.Close() })
+
+	if err := f.Rotate(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	f.millWg.Wait()
+
+	now = now.Add(48 * time.Hour)
+	if err := f.Rotate(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	f.millWg.Wait()
+
+	if diff := cmp.Diff([]string{"app-2024-01-03T00-00-00.000.log", "app.log"}, listDir(t, dir)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestWithRotatingFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	logger := NewLogger(WithRotatingFile(path, 10, 1, 1))
+	logger.Info("rotating")
+	_ = logger.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("rotating", entries[0]["msg"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,184 @@
+package logging
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Route sends entries which have a field of given value to a destination of Router,
+// such as entries with tenant=premium to a dedicated sink.
+type Route struct {
+	// Field is a key of the field to match.
+	Field string
+
+	// Value is a value of the field to match, compared with the value formatted by fmt.Sprint.
+	Value string
+
+	// Destination is a name of the destination given to NewRouter.
+	Destination string
+
+	// Exclusive reports whether matched entries are written only to the destination,
+	// instead of the logger's own output and other sinks.
+	Exclusive bool
+}
+
+// Router holds named destinations and routes to select them, which can be changed at runtime.
+// Every matched route receives the entry, and an entry matched by no route is written as usual.
+type Router struct {
+	destinations map[string]zapcore.Core
+	routes       atomic.Pointer[[]Route]
+}
+
+// NewRouter creates a Router with given destinations and routes.
+// Destinations are fixed, and routes refer to them by name. It fails if a route refers to an unknown destination.
+func NewRouter(destinations map[string]zapcore.Core, routes ...Route) (*Router, error) {
+	r := &Router{destinations: make(map[string]zapcore.Core, len(destinations))}
+	for name, core := range destinations {
+		r.destinations[name] = core
+	}
+	if err := r.Set(routes...); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Set replaces routes at runtime. Loggers created with the router are affected immediately.
+// If a route refers to an unknown destination, it will return an error and routes are not changed.
+func (r *Router) Set(routes ...Route) error {
+	for _, route := range routes {
+		if _, ok := r.destinations[route.Destination]; !ok {
+			return fmt.Errorf("logging: unknown route destination %q", route.Destination)
+		}
+	}
+	routes = append([]Route(nil), routes...)
+	r.routes.Store(&routes)
+	return nil
+}
+
+// Routes returns current routes.
+func (r *Router) Routes() []Route {
+	return append([]Route(nil), r.load()...)
+}
+
+// load returns current routes without copying them.
+func (r *Router) load() []Route {
+	if routes := r.routes.Load(); routes != nil {
+		return *routes
+	}
+	return nil
+}
+
+// WithRouter routes entries to destinations of given router by their fields, in addition to the logger's own output.
+// Destinations are filtered at the logger's level and component levels, and closed by Close.
+func WithRouter(router *Router) Option {
+	return func(o *options) {
+		o.router = router
+	}
+}
+
+// routeCore is a zapcore.Core which writes entries to destinations selected by routes.
+type routeCore struct {
+	zapcore.Core
+
+	router *Router
+
+	// destinations are destinations of the router filtered at the logger's level.
+	destinations map[string]zapcore.Core
+
+	// fields holds fields added via With, so that routes can match them and destinations receive them.
+	fields []zapcore.Field
+}
+
+// newRouteCore wraps given core, so that entries are routed by given router to given destinations.
+func newRouteCore(core zapcore.Core, router *Router, destinations map[string]zapcore.Core) zapcore.Core {
+	return &routeCore{Core: core, router: router, destinations: destinations}
+}
+
+// Enabled reports whether given level is enabled by the wrapped core or any destination.
+func (c *routeCore) Enabled(l zapcore.Level) bool {
+	if c.Core.Enabled(l) {
+		return true
+	}
+	for _, d := range c.destinations {
+		if d.Enabled(l) {
+			return true
+		}
+	}
+	return false
+}
+
+// With returns a child core with given fields.
+func (c *routeCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &routeCore{Core: c.Core.With(fields), router: c.router, destinations: c.destinations, fields: merged}
+}
+
+// Check adds a writer to given checked entry if any route exists, so that destinations are selected with fields.
+// Otherwise, it asks the wrapped core as it is.
+func (c *routeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if len(c.router.load()) == 0 {
+		return c.Core.Check(ent, ce)
+	}
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	w := &routeWriter{Core: c.Core, core: c, downstream: c.Core.Check(ent, nil)}
+	w.outer = ce.AddCore(ent, w)
+	return w.outer
+}
+
+// routeWriter is a zapcore.Core added to a checked entry by routeCore.
+type routeWriter struct {
+	zapcore.Core
+
+	core *routeCore
+
+	// downstream is a checked entry of the wrapped core. It is nil if the wrapped core does not write the entry.
+	downstream *zapcore.CheckedEntry
+
+	// outer is a checked entry of the logger which this writer is added to.
+	outer *zapcore.CheckedEntry
+}
+
+// Write writes given entry to destinations of matched routes,
+// and to the wrapped core unless an exclusive route is matched.
+func (w *routeWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	var all []zapcore.Field
+	var written []string
+	exclusive := false
+	for _, route := range w.core.router.load() {
+		if !w.matches(fields, route) {
+			continue
+		}
+		exclusive = exclusive || route.Exclusive
+		if slices.Contains(written, route.Destination) {
+			continue
+		}
+		written = append(written, route.Destination)
+
+		if all == nil {
+			all = make([]zapcore.Field, 0, len(w.core.fields)+len(fields))
+			all = append(append(all, w.core.fields...), fields...)
+		}
+		if d := w.core.destinations[route.Destination].Check(ent, nil); d != nil {
+			d.ErrorOutput = w.outer.ErrorOutput
+			d.Write(all...)
+		}
+	}
+	if !exclusive && w.downstream != nil {
+		w.downstream.Entry = ent
+		w.downstream.ErrorOutput = w.outer.ErrorOutput
+		w.downstream.Write(fields...)
+	}
+	return nil
+}
+
+// matches reports whether given fields or fields added via With match given route.
+func (w *routeWriter) matches(fields []zapcore.Field, route Route) bool {
+	return fieldMatches(fields, route.Field, route.Value) || fieldMatches(w.core.fields, route.Field, route.Value)
+}
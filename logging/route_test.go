@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewRouter(t *testing.T) {
+	t.Parallel()
+
+	core, _ := observer.New(zapcore.DebugLevel)
+	if _, err := NewRouter(map[string]zapcore.Core{"premium": core}, Route{Field: "tenant", Value: "acme", Destination: "missing"}); err == nil {
+		t.Error("expect an error of unknown destination, but received nil")
+	}
+
+	r, err := NewRouter(map[string]zapcore.Core{"premium": core})
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	routes := []Route{{Field: "tenant", Value: "acme", Destination: "premium"}}
+	if err := r.Set(routes...); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if err := r.Set(Route{Destination: "missing"}); err == nil {
+		t.Error("expect an error of unknown destination, but received nil")
+	}
+	if diff := cmp.Diff(routes, r.Routes()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestWithRouter(t *testing.T) {
+	t.Parallel()
+
+	premium, premiumLogs := observer.New(zapcore.DebugLevel)
+	secret, secretLogs := observer.New(zapcore.DebugLevel)
+	router, err := NewRouter(
+		map[string]zapcore.Core{"premium": premium, "secret": secret},
+		Route{Field: "tenant", Value: "acme", Destination: "premium"},
+		Route{Field: "plan", Value: "enterprise", Destination: "premium"},
+		Route{Field: "tenant", Value: "bank", Destination: "secret", Exclusive: true},
+	)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	own := &zaptest.Buffer{}
+	logger := NewStructuredLogger(WithOutputPaths(), WithWriteSyncer(own), WithRouter(router))
+	logger.With(zap.String("tenant", "acme")).Info("premium", zap.String("plan", "enterprise"))
+	logger.Info("secret", zap.String("tenant", "bank"))
+	logger.Info("other", zap.String("tenant", "shop"))
+	logger.Debug("filtered", zap.String("tenant", "acme"))
+
+	if err := router.Set(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	logger.Info("unrouted", zap.String("tenant", "acme"))
+
+	if diff := cmp.Diff(3, len(own.Lines())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	entries := premiumLogs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 premium entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff(map[string]any{"tenant": "acme", "plan": "enterprise"}, entries[0].ContextMap()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(1, secretLogs.FilterMessage("secret").Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,18 @@
+package logging
+
+import (
+	"log"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewSaramaLogger creates a logger which writes entries via given logger at debug level,
+// to be set to sarama.Logger of github.com/IBM/sarama, because sarama logs verbose progress of clients.
+// The returned *log.Logger satisfies sarama.StdLogger. If logger is nil, default logger is used.
+func NewSaramaLogger(logger *zap.SugaredLogger) *log.Logger {
+	if logger == nil {
+		logger = DefaultLogger()
+	}
+	return newStdLogAt(logger.Desugar(), zapcore.DebugLevel)
+}
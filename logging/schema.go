@@ -0,0 +1,176 @@
+package logging
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// schemaVersionKey is a key of the field holding the version of the schema.
+const schemaVersionKey = "schema_version"
+
+// FieldType is a type of field values declared in a schema.
+type FieldType string
+
+// Types of fields which can be declared in a schema.
+const (
+	FieldString   FieldType = "string"
+	FieldInt      FieldType = "int"
+	FieldFloat    FieldType = "float"
+	FieldBool     FieldType = "bool"
+	FieldDuration FieldType = "duration"
+	FieldTime     FieldType = "time"
+	FieldError    FieldType = "error"
+	FieldObject   FieldType = "object"
+	FieldArray    FieldType = "array"
+
+	// FieldAny accepts values of every type.
+	FieldAny FieldType = "any"
+)
+
+// Schema is a declared set of fields, so that entries keep matching mappings of log ingestion.
+type Schema struct {
+	// Version is written as schema_version field of every entry.
+	Version string
+
+	// Fields is a map of field keys to their types. schema_version is declared implicitly.
+	Fields map[string]FieldType
+}
+
+// SchemaViolation is a field which does not match a schema.
+type SchemaViolation struct {
+	// Key is a key of the field.
+	Key string
+
+	// Want is a declared type of the field. It is empty if the field is not declared.
+	Want FieldType
+
+	// Got is a type of the field value.
+	Got FieldType
+}
+
+// String returns a description of the violation.
+func (v SchemaViolation) String() string {
+	if v.Want == "" {
+		return fmt.Sprintf("unknown field %q of type %s", v.Key, v.Got)
+	}
+	return fmt.Sprintf("field %q is %s, but declared as %s", v.Key, v.Got, v.Want)
+}
+
+// Validate returns violations of given fields. Fields following a namespace are nested in it, so they are not validated.
+func (s Schema) Validate(fields []zapcore.Field) []SchemaViolation {
+	var violations []SchemaViolation
+	for _, field := range fields {
+		got, ok := fieldTypeOf(field)
+		if !ok {
+			continue
+		}
+		if field.Key == schemaVersionKey {
+			if got != FieldString {
+				violations = append(violations, SchemaViolation{Key: field.Key, Want: FieldString, Got: got})
+			}
+			continue
+		}
+		want, declared := s.Fields[field.Key]
+		switch {
+		case !declared:
+			violations = append(violations, SchemaViolation{Key: field.Key, Got: got})
+		case want != FieldAny && want != got:
+			violations = append(violations, SchemaViolation{Key: field.Key, Want: want, Got: got})
+		}
+		if field.Type == zapcore.NamespaceType {
+			break
+		}
+	}
+	return violations
+}
+
+// WithSchema makes the logger add schema_version field to every entry, and validate fields against given schema.
+// Unknown fields and type mismatches are reported to the error output once per key and type, and entries are still written.
+// Fields are validated after scrubbers, so that types written to the output are validated.
+// Use logtest's AssertSchema to fail tests on violations instead.
+func WithSchema(schema Schema) Option {
+	return func(o *options) {
+		o.schema = &schema
+		WithInitialFields(map[string]any{schemaVersionKey: schema.Version})(o)
+	}
+}
+
+// newSchemaCore wraps given core, so that fields are validated against given schema before written.
+// Violations are reported to given writer once per key and type.
+func newSchemaCore(core zapcore.Core, schema Schema, errOutput zapcore.WriteSyncer) zapcore.Core {
+	var reported sync.Map
+	validate := func(fields []zapcore.Field) {
+		for _, v := range schema.Validate(fields) {
+			if _, loaded := reported.LoadOrStore(v, struct{}{}); !loaded {
+				fmt.Fprintf(errOutput, "%v logging: schema %s violation: %s\n", time.Now(), schema.Version, v)
+			}
+		}
+	}
+	return newRewriteCore(core, func(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+		validate(fields)
+		return ent, fields
+	}, func(fields []zapcore.Field) []zapcore.Field {
+		validate(fields)
+		return fields
+	})
+}
+
+// fieldTypeOf returns a schema type of given field. It reports false for fields which write nothing.
+func fieldTypeOf(field zapcore.Field) (FieldType, bool) {
+	switch field.Type {
+	case zapcore.SkipType:
+		return "", false
+	case zapcore.StringType, zapcore.ByteStringType, zapcore.StringerType:
+		return FieldString, true
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type,
+		zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type, zapcore.UintptrType:
+		return FieldInt, true
+	case zapcore.Float64Type, zapcore.Float32Type:
+		return FieldFloat, true
+	case zapcore.BoolType:
+		return FieldBool, true
+	case zapcore.DurationType:
+		return FieldDuration, true
+	case zapcore.TimeType, zapcore.TimeFullType:
+		return FieldTime, true
+	case zapcore.ErrorType:
+		return FieldError, true
+	case zapcore.ObjectMarshalerType, zapcore.InlineMarshalerType, zapcore.NamespaceType:
+		return FieldObject, true
+	case zapcore.ArrayMarshalerType, zapcore.BinaryType:
+		return FieldArray, true
+	case zapcore.ReflectType:
+		return reflectTypeOf(field.Interface), true
+	default:
+		return FieldAny, true
+	}
+}
+
+// reflectTypeOf returns a schema type of given value encoded via reflection.
+func reflectTypeOf(v any) FieldType {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		return FieldString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return FieldInt
+	case reflect.Float32, reflect.Float64:
+		return FieldFloat
+	case reflect.Bool:
+		return FieldBool
+	case reflect.Struct, reflect.Map:
+		return FieldObject
+	case reflect.Slice, reflect.Array:
+		return FieldArray
+	default:
+		return FieldAny
+	}
+}
@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	t.Parallel()
+
+	schema := Schema{
+		Version: "v1",
+		Fields: map[string]FieldType{
+			"user_id":  FieldInt,
+			"elapsed":  FieldDuration,
+			"request":  FieldObject,
+			"tags":     FieldArray,
+			"error":    FieldError,
+			"extra":    FieldAny,
+			"accepted": FieldBool,
+		},
+	}
+	tests := []struct {
+		name   string
+		fields []zapcore.Field
+		want   []SchemaViolation
+	}{
+		{
+			name: "match",
+			fields: []zapcore.Field{
+				zap.String("schema_version", "v1"),
+				zap.Int("user_id", 1),
+				zap.Duration("elapsed", time.Second),
+				zap.Any("request", map[string]string{"path": "/"}),
+				zap.Strings("tags", []string{"a"}),
+				zap.Error(errors.New("failed")),
+				zap.Float64("extra", 1.5),
+				zap.Bool("accepted", true),
+				zap.Skip(),
+			},
+		},
+		{
+			name:   "unknown",
+			fields: []zapcore.Field{zap.String("unknown", "value")},
+			want:   []SchemaViolation{{Key: "unknown", Got: FieldString}},
+		},
+		{
+			name:   "mismatch",
+			fields: []zapcore.Field{zap.String("user_id", "1"), zap.Int("schema_version", 1)},
+			want: []SchemaViolation{
+				{Key: "user_id", Want: FieldInt, Got: FieldString},
+				{Key: "schema_version", Want: FieldString, Got: FieldInt},
+			},
+		},
+		{
+			name:   "namespace",
+			fields: []zapcore.Field{zap.Namespace("request"), zap.String("path", "/")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, schema.Validate(tt.fields)); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewSchemaCore(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	errOutput := &zaptest.Buffer{}
+	schema := Schema{Version: "v2", Fields: map[string]FieldType{"user_id": FieldInt}}
+	logger := zap.New(newSchemaCore(core, schema, errOutput)).With(zap.String("user_id", "1"))
+
+	logger.Info("first", zap.Bool("unknown", true))
+	logger.Info("second", zap.Bool("unknown", true))
+
+	if diff := cmp.Diff(2, logs.Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	lines := errOutput.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("expect 2 violations reported once, but received %q", lines)
+	}
+	for i, want := range []string{
+		`schema v2 violation: field "user_id" is string, but declared as int`,
+		`schema v2 violation: unknown field "unknown" of type bool`,
+	} {
+		if !strings.HasSuffix(lines[i], want) {
+			t.Errorf("expect %q, but received %q", want, lines[i])
+		}
+	}
+}
+
+func TestWithSchema(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger := NewStructuredLogger(
+		WithOutputPaths(path),
+		WithSchema(Schema{Version: "2024-05", Fields: map[string]FieldType{"user_id": FieldInt}}),
+	)
+	logger.Info("written", zap.Int("user_id", 1))
+	_ = logger.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("2024-05", entries[0]["schema_version"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,137 @@
+package logging
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Scrubber rewrites an entry and its fields before they are written.
+// Fields given to With are scrubbed with zero value of zapcore.Entry, and the returned entry is ignored.
+// Implementations must not modify given fields slice in place, because it may be shared.
+type Scrubber interface {
+	Scrub(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field)
+}
+
+// ScrubberFunc is an adapter to use ordinary functions as Scrubber.
+type ScrubberFunc func(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field)
+
+// Scrub calls f(ent, fields).
+func (f ScrubberFunc) Scrub(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	return f(ent, fields)
+}
+
+// WithScrubbers makes the logger rewrite entries with given scrubbers before they are encoded.
+// Scrubbers are applied in given order, after the redactor given via WithRedaction.
+func WithScrubbers(scrubbers ...Scrubber) Option {
+	return func(o *options) {
+		o.scrubbers = append(o.scrubbers, scrubbers...)
+	}
+}
+
+// allScrubbers returns the redactor and scrubbers in the order to be applied.
+func (o *options) allScrubbers() []Scrubber {
+	var scrubbers []Scrubber
+	if o.redactor != nil {
+		scrubbers = append(scrubbers, o.redactor)
+	}
+	return append(scrubbers, o.scrubbers...)
+}
+
+// NewScrubCore wraps given core, so that entries are rewritten with given scrubbers in order before written.
+func NewScrubCore(core zapcore.Core, scrubbers ...Scrubber) zapcore.Core {
+	chain := append([]Scrubber(nil), scrubbers...)
+	rewrite := func(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+		for _, s := range chain {
+			ent, fields = s.Scrub(ent, fields)
+		}
+		return ent, fields
+	}
+	rewriteWith := func(fields []zapcore.Field) []zapcore.Field {
+		for _, s := range chain {
+			_, fields = s.Scrub(zapcore.Entry{}, fields)
+		}
+		return fields
+	}
+	return newRewriteCore(core, rewrite, rewriteWith)
+}
+
+var (
+	// emailPattern matches email addresses.
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+	// cardPattern matches candidates of credit card numbers, optionally separated by spaces or hyphens.
+	cardPattern = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+
+	// ipv4Pattern matches candidates of IPv4 addresses.
+	ipv4Pattern = regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}\b`)
+
+	// ipv6Pattern matches candidates of IPv6 addresses.
+	ipv6Pattern = regexp.MustCompile(`[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`)
+)
+
+// EmailScrubber returns a Scrubber which replaces email addresses with "[EMAIL]".
+func EmailScrubber() Scrubber {
+	return newStringScrubber(func(s string) string {
+		return emailPattern.ReplaceAllString(s, "[EMAIL]")
+	})
+}
+
+// CreditCardScrubber returns a Scrubber which replaces credit card numbers with "[CARD]".
+// Only numbers passing the Luhn check are replaced, to avoid masking other long numbers.
+func CreditCardScrubber() Scrubber {
+	return newStringScrubber(func(s string) string {
+		return cardPattern.ReplaceAllStringFunc(s, func(candidate string) string {
+			if luhn(candidate) {
+				return "[CARD]"
+			}
+			return candidate
+		})
+	})
+}
+
+// IPScrubber returns a Scrubber which replaces IPv4 and IPv6 addresses with "[IP]".
+func IPScrubber() Scrubber {
+	replace := func(candidate string) string {
+		if net.ParseIP(candidate) != nil {
+			return "[IP]"
+		}
+		return candidate
+	}
+	return newStringScrubber(func(s string) string {
+		s = ipv4Pattern.ReplaceAllStringFunc(s, replace)
+		if strings.Contains(s, ":") {
+			s = ipv6Pattern.ReplaceAllStringFunc(s, replace)
+		}
+		return s
+	})
+}
+
+// newStringScrubber creates a Scrubber which rewrites messages and string values with given function,
+// including nested values of objects and maps.
+func newStringScrubber(mask func(string) string) Scrubber {
+	return &Redactor{masks: []func(string) string{mask}}
+}
+
+// luhn reports whether digits in given string pass the Luhn check.
+// Characters other than digits are ignored.
+func luhn(s string) bool {
+	var sum, n int
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
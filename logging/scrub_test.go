@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestBuiltinScrubbers(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		scrubber Scrubber
+		input    string
+		want     string
+	}{
+		{name: "email", scrubber: EmailScrubber(), input: "sent to alice@example.com", want: "sent to [EMAIL]"},
+		{name: "card", scrubber: CreditCardScrubber(), input: "card 4111 1111 1111 1111 charged", want: "card [CARD] charged"},
+		{name: "not card", scrubber: CreditCardScrubber(), input: "order 1234567890123", want: "order 1234567890123"},
+		{name: "ipv4", scrubber: IPScrubber(), input: "from 192.168.0.1:8080", want: "from [IP]:8080"},
+		{name: "ipv6", scrubber: IPScrubber(), input: "from 2001:db8::1 at 12:30:45", want: "from [IP] at 12:30:45"},
+		{name: "not ip", scrubber: IPScrubber(), input: "version 999.1.2.3", want: "version 999.1.2.3"},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.name, func(t *testing.T) {
+			t.Parallel()
+
+			ent, fields := cs.scrubber.Scrub(zapcore.Entry{Message: cs.input}, []zapcore.Field{zap.String("value", cs.input)})
+			if diff := cmp.Diff(cs.want, ent.Message); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(cs.want, fields[0].String); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithScrubbers(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	marker := ScrubberFunc(func(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+		return ent, append(append([]zapcore.Field(nil), fields...), zap.Bool("scrubbed", true))
+	})
+	o := newOptions(WithRedaction(NewRedactor(nil)), WithScrubbers(EmailScrubber(), marker))
+	logger := zap.New(NewScrubCore(core, o.allScrubbers()...))
+
+	logger.Info("user bob@example.com", zap.String("password", "p-1"), zap.String("contact", "bob@example.com"))
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("user [EMAIL]", entries[0].Message); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	want := map[string]any{"password": "[REDACTED]", "contact": "[EMAIL]", "scrubbed": true}
+	if diff := cmp.Diff(want, entries[0].ContextMap()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestLuhn(t *testing.T) {
+	t.Parallel()
+
+	if !luhn("4111-1111-1111-1111") {
+		t.Error("expect valid number, but invalid")
+	}
+	if luhn("4111-1111-1111-1112") {
+		t.Error("expect invalid number, but valid")
+	}
+}
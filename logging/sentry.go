@@ -0,0 +1,140 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// sentryFlushTimeout is a maximum duration to wait for Sentry to send buffered events.
+	sentryFlushTimeout = 2 * time.Second
+
+	// sentryMaxErrorDepth is a maximum depth of wrapped errors reported to Sentry.
+	sentryMaxErrorDepth = 10
+)
+
+// WithSentry forwards error and higher entries to Sentry with given DSN.
+// If failed to create Sentry client, it is reported to the error output and entries are not forwarded.
+func WithSentry(dsn string) Option {
+	return func(o *options) {
+		client, err := sentry.NewClient(sentry.ClientOptions{Dsn: dsn})
+		if err != nil {
+			o.errs = append(o.errs, fmt.Errorf("failed to create sentry client: %w", err))
+			return
+		}
+		o.cores = append(o.cores, NewSentryCore(sentry.NewHub(client, sentry.NewScope())))
+	}
+}
+
+// WithSentryHub forwards error and higher entries to Sentry via given hub.
+func WithSentryHub(hub *sentry.Hub) Option {
+	return WithCore(NewSentryCore(hub))
+}
+
+// sentryCore is a zapcore.Core which forwards entries to Sentry.
+// Events are sent by the transport of the hub's client, which buffers them in background,
+// so that logging is never blocked by network failures.
+type sentryCore struct {
+	hub    *sentry.Hub
+	fields []zapcore.Field
+}
+
+// NewSentryCore creates a core which forwards error and higher entries to Sentry via given hub.
+// Fields are attached to events as extras, error fields are reported as exceptions,
+// and stack traces of entries are attached as "stacktrace" extra.
+func NewSentryCore(hub *sentry.Hub) zapcore.Core {
+	return &sentryCore{hub: hub}
+}
+
+// Enabled reports whether given level is error or higher.
+func (c *sentryCore) Enabled(l zapcore.Level) bool {
+	return l >= zapcore.ErrorLevel
+}
+
+// With returns a child core with given fields.
+func (c *sentryCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &sentryCore{hub: c.hub, fields: merged}
+}
+
+// Check adds the core to given checked entry if the entry is error or higher.
+func (c *sentryCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write converts given entry to a Sentry event and captures it.
+// If the entry is higher than error level, buffered events are flushed because the process may exit.
+func (c *sentryCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	event := sentry.NewEvent()
+	event.Level = sentryLevel(ent.Level)
+	event.Message = ent.Message
+	event.Logger = ent.LoggerName
+	event.Timestamp = ent.Time
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		c.addField(event, enc, field)
+	}
+	for _, field := range fields {
+		c.addField(event, enc, field)
+	}
+	for k, v := range enc.Fields {
+		event.Extra[k] = v
+	}
+	if ent.Stack != "" {
+		event.Extra["stacktrace"] = ent.Stack
+	}
+	if ent.Caller.Defined {
+		event.Extra["caller"] = ent.Caller.TrimmedPath()
+	}
+
+	c.hub.CaptureEvent(event)
+
+	if ent.Level > zapcore.ErrorLevel {
+		return c.Sync()
+	}
+	return nil
+}
+
+// addField adds given field to extras, or to exceptions if it is an error.
+func (c *sentryCore) addField(event *sentry.Event, enc *zapcore.MapObjectEncoder, field zapcore.Field) {
+	if field.Type == zapcore.ErrorType {
+		if err, ok := field.Interface.(error); ok && len(event.Exception) == 0 {
+			event.SetException(err, sentryMaxErrorDepth)
+		}
+	}
+	field.AddTo(enc)
+}
+
+// Sync waits for Sentry to send buffered events.
+func (c *sentryCore) Sync() error {
+	if !c.hub.Flush(sentryFlushTimeout) {
+		return errors.New("logging: timed out flushing sentry events")
+	}
+	return nil
+}
+
+// sentryLevel convert given zap level to Sentry level.
+func sentryLevel(l zapcore.Level) sentry.Level {
+	switch l {
+	case zapcore.DebugLevel:
+		return sentry.LevelDebug
+	case zapcore.InfoLevel:
+		return sentry.LevelInfo
+	case zapcore.WarnLevel:
+		return sentry.LevelWarning
+	case zapcore.ErrorLevel:
+		return sentry.LevelError
+	default:
+		return sentry.LevelFatal
+	}
+}
@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+// sentryTransport is a sentry.Transport which records sent events.
+type sentryTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *sentryTransport) Configure(sentry.ClientOptions) {}
+
+func (t *sentryTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *sentryTransport) Flush(time.Duration) bool { return true }
+
+func (t *sentryTransport) Close() {}
+
+func TestWithSentryHub(t *testing.T) {
+	t.Parallel()
+
+	transport := &sentryTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: transport})
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	logger := NewLogger(WithWriteSyncer(&zaptest.Buffer{}), WithSentryHub(hub)).Named("worker").With("job", "j-1")
+	logger.Warn("ignored")
+	logger.Errorw("failed", zap.Error(errors.New("boom")), "attempt", 3)
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	if len(transport.events) != 1 {
+		t.Fatalf("expect 1 event, but received %d", len(transport.events))
+	}
+	event := transport.events[0]
+	if diff := cmp.Diff(sentry.LevelError, event.Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("failed", event.Message); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("worker", event.Logger); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("j-1", event.Extra["job"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(int64(3), event.Extra["attempt"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if len(event.Exception) == 0 || event.Exception[0].Value != "boom" {
+		t.Errorf("expect exception boom, but received %+v", event.Exception)
+	}
+	if _, ok := event.Extra["stacktrace"]; !ok {
+		t.Error("expect stacktrace extra, but not found")
+	}
+}
+
+func TestWithSentryInvalidDSN(t *testing.T) {
+	t.Parallel()
+
+	o := newOptions(WithSentry("invalid dsn"))
+	if len(o.errs) != 1 {
+		t.Fatalf("expect 1 error, but received %v", o.errs)
+	}
+	if len(o.cores) != 0 {
+		t.Errorf("expect no cores, but received %d", len(o.cores))
+	}
+}
@@ -0,0 +1,180 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"syscall"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// closeFunc releases a resource owned by a logger, or gives up when given context is done.
+type closeFunc func(context.Context) error
+
+// appendCloser appends given closeFunc to closers unless it is nil, which means there is nothing to release.
+func appendCloser(closers []closeFunc, fn closeFunc) []closeFunc {
+	if fn == nil {
+		return closers
+	}
+	return append(closers, fn)
+}
+
+// closeOpened converts a close function returned by zap.Open to a closeFunc.
+func closeOpened(fn func()) closeFunc {
+	return func(context.Context) error {
+		fn()
+		return nil
+	}
+}
+
+// appendCoreCloser appends Close of given core to closers if the core shipping entries in background has it.
+func appendCoreCloser(closers []closeFunc, core zapcore.Core) []closeFunc {
+	if c, ok := core.(interface{ Close(context.Context) error }); ok {
+		return append(closers, c.Close)
+	}
+	return closers
+}
+
+// closeWithContext calls given function, and gives up waiting for it when given context is done.
+func closeWithContext(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// trackedLogger is a logger created by the package, with resources to release at shutdown.
+type trackedLogger struct {
+	logger  *zap.Logger
+	closers []closeFunc
+}
+
+// close flushes the logger and releases its resources, or gives up when given context is done.
+func (l *trackedLogger) close(ctx context.Context) error {
+	err := syncLogger(l.logger)
+	for _, fn := range l.closers {
+		err = multierr.Append(err, fn(ctx))
+	}
+	return err
+}
+
+// ownedCore is the outermost core of a logger created by the package, which remembers the logger owning it,
+// so that CloseLogger can find resources of the logger and its children.
+type ownedCore struct {
+	zapcore.Core
+
+	owner *trackedLogger
+}
+
+// With returns a child core with given fields, owned by the same logger.
+func (c *ownedCore) With(fields []zapcore.Field) zapcore.Core {
+	return &ownedCore{Core: c.Core.With(fields), owner: c.owner}
+}
+
+// trackedLoggers holds loggers created by the package which own resources to release, until they are closed,
+// so that Sync and Close can flush them. Other loggers are not retained, so that loggers created per request
+// or per test are garbage collected.
+var trackedLoggers struct {
+	mu      sync.Mutex
+	loggers map[*trackedLogger]struct{}
+}
+
+// track registers given logger to be flushed by Sync and Close.
+func track(l *trackedLogger) {
+	trackedLoggers.mu.Lock()
+	if trackedLoggers.loggers == nil {
+		trackedLoggers.loggers = make(map[*trackedLogger]struct{})
+	}
+	trackedLoggers.loggers[l] = struct{}{}
+	trackedLoggers.mu.Unlock()
+}
+
+// loggerOwner returns the logger created by the package owning the core of given logger, or nil if there is none.
+func loggerOwner(logger *zap.Logger) *trackedLogger {
+	if owned, ok := logger.Core().(*ownedCore); ok {
+		return owned.owner
+	}
+	return nil
+}
+
+// untrack unregisters given logger, and reports whether it was registered.
+func untrack(l *trackedLogger) bool {
+	trackedLoggers.mu.Lock()
+	defer trackedLoggers.mu.Unlock()
+	if _, ok := trackedLoggers.loggers[l]; !ok {
+		return false
+	}
+	delete(trackedLoggers.loggers, l)
+	return true
+}
+
+// Sync flushes every logger created by the package which owns resources, such as files, async queues,
+// and network sinks. Loggers writing only to stdout, stderr, or writers given via options are not flushed.
+// Errors of syncing stdout and stderr which do not support it, such as terminals, are ignored.
+func Sync() error {
+	trackedLoggers.mu.Lock()
+	loggers := make([]*trackedLogger, 0, len(trackedLoggers.loggers))
+	for l := range trackedLoggers.loggers {
+		loggers = append(loggers, l)
+	}
+	trackedLoggers.mu.Unlock()
+
+	var err error
+	for _, l := range loggers {
+		err = multierr.Append(err, syncLogger(l.logger))
+	}
+	return err
+}
+
+// Close flushes every logger created by the package and releases their resources, such as files,
+// connections, and background goroutines, or gives up when given context is done.
+// It is meant to be called once at shutdown. Loggers must not be used after Close.
+func Close(ctx context.Context) error {
+	trackedLoggers.mu.Lock()
+	loggers := trackedLoggers.loggers
+	trackedLoggers.loggers = nil
+	trackedLoggers.mu.Unlock()
+
+	var err error
+	for l := range loggers {
+		err = multierr.Append(err, l.close(ctx))
+	}
+	return err
+}
+
+// CloseLogger flushes given logger created by the package and releases its resources, or gives up when
+// given context is done. The logger is no longer flushed by Sync and Close, so that loggers created per request
+// or per test are not retained. Children of the logger, such as ones created by With or Named, close the same logger.
+// Other loggers are ignored. The logger and its children must not be used after CloseLogger.
+func CloseLogger(ctx context.Context, logger *zap.SugaredLogger) error {
+	return CloseStructuredLogger(ctx, logger.Desugar())
+}
+
+// CloseStructuredLogger closes given structured logger.
+// It is same as CloseLogger, but it takes *zap.Logger instead of *zap.SugaredLogger.
+func CloseStructuredLogger(ctx context.Context, logger *zap.Logger) error {
+	owner := loggerOwner(logger)
+	if owner == nil || !untrack(owner) {
+		return nil
+	}
+	return owner.close(ctx)
+}
+
+// syncLogger syncs given logger, ignoring errors of syncing files which do not support it.
+func syncLogger(logger *zap.Logger) error {
+	var errs []error
+	for _, err := range multierr.Errors(logger.Sync()) {
+		if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EBADF) {
+			continue
+		}
+		errs = append(errs, err)
+	}
+	return multierr.Combine(errs...)
+}
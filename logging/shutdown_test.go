@@ -0,0 +1,168 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+// failingSyncer is a zapcore.WriteSyncer which fails to sync.
+type failingSyncer struct {
+	zaptest.Buffer
+}
+
+func (s *failingSyncer) Sync() error {
+	return errors.New("sync failed")
+}
+
+// Close affects every logger created by the package, so tests below must not run in parallel.
+
+func TestSync(t *testing.T) {
+	ws := &failingSyncer{}
+	logger := NewLogger(WithWriteSyncer(ws), WithAsync(AsyncConfig{}))
+	t.Cleanup(func() {
+		_ = Close(context.Background())
+	})
+	logger.Info("message")
+
+	if err := Sync(); err == nil {
+		t.Error("expect an error of the failing writer, but received nil")
+	}
+	if diff := cmp.Diff(1, len(ws.Lines())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestClose(t *testing.T) {
+	buf := &zaptest.Buffer{}
+	recorder := &kafkaRecorder{}
+	logger := NewLogger(
+		WithWriteSyncer(buf),
+		WithAsync(AsyncConfig{}),
+		WithKafka(KafkaConfig{Writer: recorder, FlushInterval: time.Hour}),
+	)
+	logger.Info("first")
+	logger.Info("second")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := Close(ctx); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff(2, len(buf.Lines())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if diff := cmp.Diff(2, len(recorder.messages)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	if err := Sync(); err != nil {
+		t.Errorf("expect no logger to be tracked after Close, but received %v", err)
+	}
+}
+
+func TestCloseLogger(t *testing.T) {
+	ws := &failingSyncer{}
+	logger := NewLogger(WithWriteSyncer(ws), WithAsync(AsyncConfig{}))
+	t.Cleanup(func() {
+		_ = Close(context.Background())
+	})
+	asyncQueues.mu.Lock()
+	q := asyncQueues.queues[len(asyncQueues.queues)-1]
+	asyncQueues.mu.Unlock()
+	logger.With("request_id", "abc").Info("message")
+
+	if err := CloseLogger(context.Background(), logger.With("request_id", "abc")); err == nil {
+		t.Error("expect an error of the failing writer, but received nil")
+	}
+	if diff := cmp.Diff(1, len(ws.Lines())); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	if err := Sync(); err != nil {
+		t.Errorf("expect the logger not to be tracked after CloseLogger, but received %v", err)
+	}
+	asyncQueues.mu.Lock()
+	for _, tracked := range asyncQueues.queues {
+		if tracked == q {
+			t.Error("expect the async queue not to be tracked after CloseLogger, but found")
+		}
+	}
+	asyncQueues.mu.Unlock()
+	if err := CloseLogger(context.Background(), logger); err != nil {
+		t.Errorf("expect closing twice to be ignored, but received %v", err)
+	}
+}
+
+// tracked reports whether given logger is flushed by Sync and Close.
+func tracked(logger *zap.Logger) bool {
+	trackedLoggers.mu.Lock()
+	defer trackedLoggers.mu.Unlock()
+	_, ok := trackedLoggers.loggers[loggerOwner(logger)]
+	return ok
+}
+
+func TestTrackOnlyLoggersWithResources(t *testing.T) {
+	t.Cleanup(func() {
+		_ = Close(context.Background())
+	})
+
+	if tracked(NewStructuredLogger(WithWriteSyncer(&zaptest.Buffer{}), WithOutputPaths("stderr"))) {
+		t.Error("expect a logger without resources not to be tracked")
+	}
+	if !tracked(NewStructuredLogger(WithOutputPaths(filepath.Join(t.TempDir(), "app.log")))) {
+		t.Error("expect a logger writing to a file to be tracked")
+	}
+}
+
+func TestSetDefaultUntracksPrevious(t *testing.T) {
+	t.Cleanup(func() {
+		ResetDefault()
+		_ = Close(context.Background())
+	})
+
+	first := NewLogger(WithWriteSyncer(&zaptest.Buffer{}), WithAsync(AsyncConfig{}))
+	SetDefault(first)
+	SetDefault(first)
+	if !tracked(first.Desugar()) {
+		t.Error("expect the default logger to be tracked")
+	}
+
+	second := NewLogger(WithWriteSyncer(&zaptest.Buffer{}), WithAsync(AsyncConfig{}))
+	SetDefault(second)
+	if tracked(first.Desugar()) {
+		t.Error("expect the previous default logger not to be tracked")
+	}
+
+	ResetDefault()
+	if tracked(second.Desugar()) {
+		t.Error("expect the discarded default logger not to be tracked")
+	}
+}
+
+func TestBuildErrorReleasesResources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	_, _, _, err := buildStructuredLogger(
+		WithOutputPaths(path),
+		WithTee(SinkConfig{OutputPaths: []string{"unknown://sink"}}),
+	)
+	if err == nil {
+		t.Fatal("expect an error of the unknown sink, but received nil")
+	}
+
+	reopenableFiles.mu.Lock()
+	defer reopenableFiles.mu.Unlock()
+	for f := range reopenableFiles.files {
+		if f.config.Path == path {
+			t.Error("expect the opened file to be closed, but it is still registered")
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// debugToggle holds the level of default logger before debug level is enabled by a signal.
+var debugToggle struct {
+	mu       sync.Mutex
+	enabled  bool
+	previous zapcore.Level
+
+	// window is a timer ending the debug window started by DebugFor. If nil, no window is active.
+	window *time.Timer
+
+	// generation identifies the latest window, so that timers of extended windows are ignored.
+	generation uint64
+}
+
+// HandleSignals handles signals for logging until given context is done.
+// On SIGHUP, files are reopened via ReopenFiles for logrotate compatibility.
+// On SIGUSR1, default logger switches to debug level, and on SIGUSR2, it returns to the level before SIGUSR1.
+// Signals other than SIGHUP are not handled on Windows.
+func HandleSignals(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, handledSignals...)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case sig := <-signals:
+				handleSignal(sig)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// reopenOnSignal reopens files, and reports failures to default logger.
+func reopenOnSignal() {
+	if err := ReopenFiles(); err != nil {
+		DefaultStructuredLogger().Error("logging: failed to reopen files", zap.Error(err))
+	}
+}
+
+// enableDebug switches default logger to debug level, remembering the current level.
+func enableDebug() {
+	debugToggle.mu.Lock()
+	defer debugToggle.mu.Unlock()
+
+	if debugToggle.enabled {
+		return
+	}
+	debugToggle.enabled = true
+	debugToggle.previous = Level()
+	AtomicLevel().SetLevel(zapcore.DebugLevel)
+}
+
+// restoreLevel returns default logger to the level before enableDebug or DebugFor.
+func restoreLevel() {
+	debugToggle.mu.Lock()
+	defer debugToggle.mu.Unlock()
+
+	restoreLevelLocked()
+}
+
+// restoreLevelLocked returns default logger to the level before debug level is enabled,
+// ending the debug window if active. debugToggle.mu must be held.
+func restoreLevelLocked() {
+	if !debugToggle.enabled {
+		return
+	}
+	if debugToggle.window != nil {
+		debugToggle.window.Stop()
+		debugToggle.window = nil
+		DefaultStructuredLogger().Info("debug window ended", zap.Stringer("level", debugToggle.previous))
+	}
+	debugToggle.enabled = false
+	AtomicLevel().SetLevel(debugToggle.previous)
+}
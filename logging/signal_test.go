@@ -0,0 +1,48 @@
+//go:build !windows
+
+package logging
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestHandleSignals must not run in parallel, because it changes the level of default logger.
+func TestHandleSignals(t *testing.T) {
+	original := Level()
+	t.Cleanup(func() {
+		AtomicLevel().SetLevel(original)
+	})
+	AtomicLevel().SetLevel(zap.WarnLevel)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	HandleSignals(ctx)
+
+	waitLevel := func(want zapcore.Level) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for Level() != want && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if diff := cmp.Diff(want, Level()); diff != "" {
+			t.Fatalf("(-want, +got)\n%s", diff)
+		}
+	}
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	waitLevel(zap.DebugLevel)
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	waitLevel(zap.WarnLevel)
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+
+package logging
+
+import (
+	"os"
+	"syscall"
+)
+
+// handledSignals is a list of signals handled by HandleSignals.
+var handledSignals = []os.Signal{syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2}
+
+// handleSignal reopens files or changes the level of default logger on given signal.
+func handleSignal(sig os.Signal) {
+	switch sig {
+	case syscall.SIGHUP:
+		reopenOnSignal()
+	case syscall.SIGUSR1:
+		enableDebug()
+	case syscall.SIGUSR2:
+		restoreLevel()
+	}
+}
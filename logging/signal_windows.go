@@ -0,0 +1,18 @@
+//go:build windows
+
+package logging
+
+import (
+	"os"
+	"syscall"
+)
+
+// handledSignals is a list of signals handled by HandleSignals.
+var handledSignals = []os.Signal{syscall.SIGHUP}
+
+// handleSignal reopens files on given signal.
+func handleSignal(sig os.Signal) {
+	if sig == syscall.SIGHUP {
+		reopenOnSignal()
+	}
+}
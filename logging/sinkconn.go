@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// sinkDialTimeout is a timeout of connecting sinks which write on the logging goroutine.
+	sinkDialTimeout = time.Second
+
+	// sinkWriteTimeout is a timeout of a write of such sinks.
+	sinkWriteTimeout = time.Second
+)
+
+// errSinkBackoff is returned while a sink waits to reconnect after a failed connection.
+var errSinkBackoff = errors.New("logging: waiting to reconnect")
+
+// sinkConn is a connection of a sink which writes messages on the logging goroutine, such as syslog and GELF.
+// It bounds the time a log call is blocked: connecting and writing have short timeouts, connecting is retried
+// with backoff instead of on every call, and a message is not written again after a failed write,
+// so that the framing of stream connections is not corrupted by partially written messages.
+type sinkConn struct {
+	dial func() (net.Conn, error)
+
+	// writeTimeout is a timeout of a write. It is replaced in tests.
+	writeTimeout time.Duration
+
+	// mu guards fields below.
+	mu      sync.Mutex
+	conn    net.Conn
+	retryAt time.Time
+	backoff time.Duration
+}
+
+// newSinkConn creates a sinkConn which connects via given function lazily.
+func newSinkConn(dial func() (net.Conn, error)) *sinkConn {
+	return &sinkConn{dial: dial, writeTimeout: sinkWriteTimeout}
+}
+
+// write writes given packets of a message in order, connecting if needed.
+// If writing fails, the connection is closed and the message is dropped, to be reconnected on next write.
+func (c *sinkConn) write(packets ...[]byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if time.Now().Before(c.retryAt) {
+			return errSinkBackoff
+		}
+		conn, err := c.dial()
+		if err != nil {
+			c.backoff = min(max(c.backoff*2, networkMinBackoff), networkMaxBackoff)
+			c.retryAt = time.Now().Add(c.backoff)
+			return err
+		}
+		c.conn, c.backoff = conn, 0
+	}
+
+	for _, p := range packets {
+		err := c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+		if err == nil {
+			_, err = c.conn.Write(p)
+		}
+		if err != nil {
+			_ = c.conn.Close()
+			c.conn = nil
+			return err
+		}
+	}
+	return nil
+}
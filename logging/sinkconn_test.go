@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSinkConnDropsFailedMessage(t *testing.T) {
+	t.Parallel()
+
+	// The first connection is never read, and later ones send what they read.
+	received := make(chan string, 4)
+	dials := 0
+	c := newSinkConn(func() (net.Conn, error) {
+		client, server := net.Pipe()
+		if dials++; dials > 1 {
+			go func() {
+				b := make([]byte, 64)
+				for {
+					n, err := server.Read(b)
+					if err != nil {
+						return
+					}
+					received <- string(b[:n])
+				}
+			}()
+		}
+		return client, nil
+	})
+	c.writeTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	if err := c.write([]byte("first")); err == nil {
+		t.Fatal("expect an error, but received nil")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expect the write to time out, but took %s", elapsed)
+	}
+
+	if err := c.write([]byte("second")); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff("second", <-received); diff != "" {
+		t.Errorf("expect the failed message not to be written again (-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(2, dials); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestSinkConnBackoff(t *testing.T) {
+	t.Parallel()
+
+	dials := 0
+	c := newSinkConn(func() (net.Conn, error) {
+		dials++
+		return nil, errors.New("connection refused")
+	})
+
+	if err := c.write([]byte("first")); err == nil {
+		t.Fatal("expect an error, but received nil")
+	}
+	if err := c.write([]byte("second")); !errors.Is(err, errSinkBackoff) {
+		t.Errorf("expect %v, but received %v", errSinkBackoff, err)
+	}
+	if diff := cmp.Diff(1, dials); diff != "" {
+		t.Errorf("expect no connection attempt during backoff (-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// slogCallerSkip is a number of frames of Handle and *slog.Logger between callers of *slog.Logger and Check,
+// so that stack traces start from the callers.
+const slogCallerSkip = 3
+
+// slogHandler is an implementation of slog.Handler backed by zap logger.
+type slogHandler struct {
+	// logger is a zap logger to write entries. Its options, such as stack traces and error output,
+	// apply to records as same as entries logged via the logger.
+	logger *zap.Logger
+}
+
+// NewSlogHandler creates a slog.Handler which writes records via given logger.
+// It is useful to pass the package's logger to libraries accepting only *slog.Logger.
+func NewSlogHandler(logger *zap.SugaredLogger) slog.Handler {
+	return &slogHandler{logger: logger.Desugar().WithOptions(zap.AddCallerSkip(slogCallerSkip))}
+}
+
+// Slog returns a *slog.Logger backed by the logger stored in given context.
+// If not contained logger from given context, it will be backed by a default logger.
+func Slog(ctx context.Context) *slog.Logger {
+	return slog.New(NewSlogHandler(FromContext(ctx)))
+}
+
+// Enabled reports whether the handler handles records at given level.
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.Core().Enabled(slogToZapLevel(level))
+}
+
+// Handle writes given record via zap logger. The time and the caller of the entry are taken from the record.
+func (h *slogHandler) Handle(_ context.Context, record slog.Record) error {
+	ce := h.logger.Check(slogToZapLevel(record.Level), record.Message)
+	if ce == nil {
+		return nil
+	}
+	ce.Time = record.Time
+	ce.Caller = zapcore.EntryCaller{}
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		ce.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+	}
+
+	fields := make([]zap.Field, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, attr)
+		return true
+	})
+	ce.Write(fields...)
+	return nil
+}
+
+// WithAttrs returns a new handler whose entries contain given attributes.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]zap.Field, 0, len(attrs))
+	for _, attr := range attrs {
+		fields = appendAttr(fields, attr)
+	}
+	return &slogHandler{logger: h.logger.With(fields...)}
+}
+
+// WithGroup returns a new handler whose following attributes are nested under given name.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{logger: h.logger.With(zap.Namespace(name))}
+}
+
+// slogToZapLevel convert given slog level to zap level.
+// Levels between standard levels are rounded down to the nearest one.
+func slogToZapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// appendAttr converts given slog attribute to zap fields and appends them to given fields.
+// Empty attributes are ignored, and groups without key are inlined as slog specifies.
+func appendAttr(fields []zap.Field, attr slog.Attr) []zap.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return append(fields, zap.String(attr.Key, attr.Value.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(attr.Key, attr.Value.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(attr.Key, attr.Value.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(attr.Key, attr.Value.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(attr.Key, attr.Value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(attr.Key, attr.Value.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(attr.Key, attr.Value.Time()))
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		if len(group) == 0 {
+			return fields
+		}
+		if attr.Key == "" {
+			for _, a := range group {
+				fields = appendAttr(fields, a)
+			}
+			return fields
+		}
+		return append(fields, zap.Object(attr.Key, groupMarshaler(group)))
+	default:
+		return append(fields, zap.Any(attr.Key, attr.Value.Any()))
+	}
+}
+
+// groupMarshaler is a zapcore.ObjectMarshaler to encode slog group attributes.
+type groupMarshaler []slog.Attr
+
+// MarshalLogObject encodes attributes of the group to given encoder.
+func (g groupMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, attr := range g {
+		for _, field := range appendAttr(nil, attr) {
+			field.AddTo(enc)
+		}
+	}
+	return nil
+}
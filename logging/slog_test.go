@@ -0,0 +1,115 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSlogHandler(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	logger := slog.New(NewSlogHandler(zap.New(core).Sugar()))
+
+	logger.Debug("ignored")
+	logger.With("service", "test").WithGroup("req").Info("handled",
+		"status", 200,
+		slog.Duration("latency", time.Second),
+		slog.Group("user", "id", "u1"),
+		slog.Group("", "inline", true),
+	)
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("handled", entries[0].Message); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	want := map[string]any{
+		"service": "test",
+		"req": map[string]any{
+			"status":  int64(200),
+			"latency": time.Second,
+			"user":    map[string]any{"id": "u1"},
+			"inline":  true,
+		},
+	}
+	if diff := cmp.Diff(want, entries[0].ContextMap()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestSlog(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	ctx := WithLogger(context.Background(), zap.New(core).Sugar())
+
+	Slog(ctx).WarnContext(ctx, "from context")
+
+	if diff := cmp.Diff(1, logs.FilterMessage("from context").FilterLevelExact(zap.WarnLevel).Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestSlogHandlerOptions(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	var hooked int
+	base := zap.New(core, zap.AddStacktrace(zap.ErrorLevel), zap.Hooks(func(zapcore.Entry) error {
+		hooked++
+		return nil
+	}))
+	logger := slog.New(NewSlogHandler(base.Sugar()))
+
+	logger.Error("failed")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if stack := entries[0].Stack; !strings.HasPrefix(stack, "github.com/aqyuki/util/logging.TestSlogHandlerOptions\n") {
+		t.Errorf("expect the stack trace from the caller, but received %q", stack)
+	}
+	if diff := cmp.Diff(1, hooked); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestSlogToZapLevel(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		input slog.Level
+		want  zapcore.Level
+	}{
+		{input: slog.LevelDebug, want: zap.DebugLevel},
+		{input: slog.LevelInfo, want: zap.InfoLevel},
+		{input: slog.LevelInfo + 1, want: zap.InfoLevel},
+		{input: slog.LevelWarn, want: zap.WarnLevel},
+		{input: slog.LevelError, want: zap.ErrorLevel},
+		{input: slog.LevelError + 4, want: zap.ErrorLevel},
+	}
+
+	for _, cs := range cases {
+		cs := cs
+		t.Run(cs.input.String(), func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(cs.want, slogToZapLevel(cs.input)); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultSpoolMaxBytes is a default maximum total size of spooled batches.
+	defaultSpoolMaxBytes = 64 * megabyte
+
+	// spoolExt is an extension of files holding spooled batches.
+	spoolExt = ".spool"
+
+	// spoolHeaderSize is a size of the header of each record: magic, length, and CRC-32 of the payload.
+	spoolHeaderSize = 12
+
+	// spoolReplayFiles is a maximum number of files replayed at once, so that new entries are not delayed too long.
+	spoolReplayFiles = 16
+)
+
+// spoolMagic marks the start of each record, so that replay can find the next record after corrupted bytes.
+var spoolMagic = []byte("LSP1")
+
+// SpoolConfig is a configuration of a disk spool which persists batches that could not be delivered,
+// and replays them when the destination becomes reachable again.
+type SpoolConfig struct {
+	// Dir is a directory to persist batches. It is created if not exists.
+	// Each destination must have its own directory. Batches left by the previous process are replayed.
+	Dir string
+
+	// MaxBytes is a maximum total size of persisted batches. If zero, 64 MiB is used.
+	// Batches exceeding it are dropped and counted as log_entries_dropped_total.
+	MaxBytes int64
+}
+
+// spool persists batches of items to files. It is used only from the goroutine of batcher.
+type spool[T any] struct {
+	config SpoolConfig
+	sink   string
+
+	encode func(T) []byte
+	decode func([]byte) (T, error)
+
+	// files is a list of names of spooled files in replay order, and size is their total size.
+	files []string
+	size  int64
+
+	// seq distinguishes files created at the same time.
+	seq uint64
+}
+
+// newSpool creates a spool persisting items of given sink with given codec.
+// Files left in the directory are replayed before new ones.
+func newSpool[T any](config SpoolConfig, sink string, encode func(T) []byte, decode func([]byte) (T, error)) *spool[T] {
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = defaultSpoolMaxBytes
+	}
+	s := &spool[T]{config: config, sink: sink, encode: encode, decode: decode}
+	entries, _ := os.ReadDir(config.Dir)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), spoolExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		s.files = append(s.files, entry.Name())
+		s.size += info.Size()
+	}
+	sort.Strings(s.files)
+	return s
+}
+
+// pending reports whether spooled batches are waiting to be replayed.
+func (s *spool[T]) pending() bool {
+	return len(s.files) > 0
+}
+
+// store persists given batch. It fails if the total size would exceed the limit.
+// The file is renamed after written, so that partially written files are never replayed.
+func (s *spool[T]) store(batch []T) error {
+	var data []byte
+	for _, item := range batch {
+		data = appendSpoolRecord(data, s.encode(item))
+	}
+	if s.size+int64(len(data)) > s.config.MaxBytes {
+		return fmt.Errorf("logging: %s spool is full", s.sink)
+	}
+	if err := os.MkdirAll(s.config.Dir, 0o755); err != nil {
+		return fmt.Errorf("logging: failed to create %s spool: %w", s.sink, err)
+	}
+
+	s.seq++
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), s.seq%1_000_000, spoolExt)
+	tmp := filepath.Join(s.config.Dir, name+".tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("logging: failed to write %s spool: %w", s.sink, err)
+	}
+	if err := os.Rename(tmp, filepath.Join(s.config.Dir, name)); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("logging: failed to write %s spool: %w", s.sink, err)
+	}
+	s.files = append(s.files, name)
+	s.size += int64(len(data))
+	return nil
+}
+
+// rewrite replaces the first spooled file of given size with given batch, keeping its place in replay order.
+// If it fails, the file is removed.
+func (s *spool[T]) rewrite(size int64, batch []T) error {
+	var data []byte
+	for _, item := range batch {
+		data = appendSpoolRecord(data, s.encode(item))
+	}
+	path := filepath.Join(s.config.Dir, s.files[0])
+	tmp := path + ".tmp"
+	err := os.WriteFile(tmp, data, 0o600)
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		_ = os.Remove(path)
+		s.files = s.files[1:]
+		s.size -= size
+		return fmt.Errorf("logging: failed to write %s spool: %w", s.sink, err)
+	}
+	s.size += int64(len(data)) - size
+	return nil
+}
+
+// replay sends spooled batches in order via given function, and removes them once sent.
+// It stops at the first failure, leaving the failed batch and later ones for the next replay.
+// If the failure is *partialSendError, only its unsent items are left.
+// Records which are corrupted or can not be decoded are skipped and counted as dropped.
+func (s *spool[T]) replay(send func([]T) error) error {
+	for n := 0; n < spoolReplayFiles && len(s.files) > 0; n++ {
+		path := filepath.Join(s.config.Dir, s.files[0])
+		data, err := os.ReadFile(path)
+		if err == nil {
+			records, corrupted := readSpoolRecords(data)
+			batch := make([]T, 0, len(records))
+			for _, record := range records {
+				item, err := s.decode(record)
+				if err != nil {
+					corrupted = true
+					continue
+				}
+				batch = append(batch, item)
+			}
+			if corrupted {
+				droppedEntries.WithLabelValues(s.sink).Inc()
+			}
+			if len(batch) > 0 {
+				if err := send(batch); err != nil {
+					// Items already sent are not replayed again.
+					var partial *partialSendError[T]
+					if errors.As(err, &partial) && s.rewrite(int64(len(data)), partial.unsent) != nil {
+						droppedEntries.WithLabelValues(s.sink).Add(float64(len(partial.unsent)))
+					}
+					return err
+				}
+			}
+		}
+		_ = os.Remove(path)
+		s.files = s.files[1:]
+		s.size -= int64(len(data))
+	}
+	if len(s.files) == 0 {
+		s.size = 0
+	}
+	return nil
+}
+
+// appendSpoolRecord appends given payload framed with the magic, its length, and its CRC-32.
+func appendSpoolRecord(b, payload []byte) []byte {
+	b = append(b, spoolMagic...)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(payload)))
+	b = binary.BigEndian.AppendUint32(b, crc32.ChecksumIEEE(payload))
+	return append(b, payload...)
+}
+
+// readSpoolRecords returns payloads of valid records in given data.
+// Corrupted bytes are skipped by searching the next magic, and it reports whether any bytes are skipped.
+func readSpoolRecords(data []byte) (records [][]byte, corrupted bool) {
+	for len(data) > 0 {
+		i := bytes.Index(data, spoolMagic)
+		if i < 0 {
+			return records, true
+		}
+		if i > 0 {
+			corrupted = true
+			data = data[i:]
+		}
+		if len(data) < spoolHeaderSize {
+			return records, true
+		}
+		n := binary.BigEndian.Uint32(data[4:8])
+		if uint64(n) > uint64(len(data)-spoolHeaderSize) || crc32.ChecksumIEEE(data[spoolHeaderSize:spoolHeaderSize+int(n)]) != binary.BigEndian.Uint32(data[8:12]) {
+			corrupted = true
+			data = data[len(spoolMagic):]
+			continue
+		}
+		records = append(records, data[spoolHeaderSize:spoolHeaderSize+int(n)])
+		data = data[spoolHeaderSize+int(n):]
+	}
+	return records, corrupted
+}
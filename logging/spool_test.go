@@ -0,0 +1,177 @@
+package logging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// newStringSpool creates a spool of strings in given directory.
+func newStringSpool(dir, sink string, maxBytes int64) *spool[string] {
+	return newSpool(SpoolConfig{Dir: dir, MaxBytes: maxBytes}, sink,
+		func(s string) []byte { return []byte(s) },
+		func(b []byte) (string, error) { return string(b), nil })
+}
+
+func TestSpool(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "spool")
+	s := newStringSpool(dir, "spool-test", 0)
+	if s.pending() {
+		t.Fatal("expect no pending batch, but pending")
+	}
+	for _, batch := range [][]string{{"a", "b"}, {"c"}} {
+		if err := s.store(batch); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+	}
+
+	// A spool created later replays batches left by the previous one.
+	restarted := newStringSpool(dir, "spool-test", 0)
+	failing := errors.New("unavailable")
+	if err := restarted.replay(func([]string) error { return failing }); !errors.Is(err, failing) {
+		t.Fatalf("expect send error, but received %v", err)
+	}
+
+	var sent [][]string
+	if err := restarted.replay(func(batch []string) error {
+		sent = append(sent, batch)
+		return nil
+	}); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff([][]string{{"a", "b"}, {"c"}}, sent); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if restarted.pending() {
+		t.Error("expect no pending batch after replay, but pending")
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("expect no spooled file, but received %d", len(files))
+	}
+}
+
+func TestSpoolPartialReplay(t *testing.T) {
+	t.Parallel()
+
+	s := newStringSpool(t.TempDir(), "spool-partial-test", 0)
+	for _, batch := range [][]string{{"a", "b", "c"}, {"d"}} {
+		if err := s.store(batch); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+	}
+
+	failing := errors.New("unavailable")
+	if err := s.replay(func([]string) error {
+		return &partialSendError[string]{unsent: []string{"c"}, err: failing}
+	}); !errors.Is(err, failing) {
+		t.Fatalf("expect send error, but received %v", err)
+	}
+
+	var sent [][]string
+	if err := s.replay(func(batch []string) error {
+		sent = append(sent, batch)
+		return nil
+	}); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff([][]string{{"c"}, {"d"}}, sent); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestSpoolMaxBytes(t *testing.T) {
+	t.Parallel()
+
+	s := newStringSpool(t.TempDir(), "spool-max-test", 2*spoolHeaderSize+2)
+	if err := s.store([]string{"a", "b"}); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if err := s.store([]string{"c"}); err == nil {
+		t.Error("expect an error of full spool, but received nil")
+	}
+}
+
+func TestSpoolCorruption(t *testing.T) {
+	t.Parallel()
+
+	counter := droppedEntries.WithLabelValues("spool-corrupt-test")
+	before := testutil.ToFloat64(counter)
+
+	dir := t.TempDir()
+	s := newStringSpool(dir, "spool-corrupt-test", 0)
+	if err := s.store([]string{"first", "second", "third"}); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	// Corrupt the payload of the first record and truncate the last one.
+	path := filepath.Join(dir, s.files[0])
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[spoolHeaderSize] ^= 0xff
+	data = data[:len(data)-2]
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var sent []string
+	if err := s.replay(func(batch []string) error {
+		sent = append(sent, batch...)
+		return nil
+	}); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff([]string{"second"}, sent); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(before+1, testutil.ToFloat64(counter)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestSpoolingBatcher(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var sent [][]string
+	available := false
+	send := func(batch []string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !available {
+			return errors.New("unavailable")
+		}
+		sent = append(sent, append([]string(nil), batch...))
+		return nil
+	}
+
+	s := newStringSpool(t.TempDir(), "spool-batcher-test", 0)
+	b := newSpoolingBatcher(batcherConfig{sink: "spool-batcher-test", batchSize: 10, interval: time.Hour}, send, s)
+	b.add("spooled")
+	if err := b.flush(); err == nil {
+		t.Error("expect an error while unavailable, but received nil")
+	}
+
+	mu.Lock()
+	available = true
+	mu.Unlock()
+	b.add("live")
+	if err := b.close(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if diff := cmp.Diff([][]string{{"live"}, {"spooled"}}, sent); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
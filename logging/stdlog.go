@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"log"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RedirectStdLog redirects output of the standard log package to given logger at info level,
+// so that third-party code using the standard log package is written to the same sinks.
+// Entries are annotated with the caller of the standard log package if the logger annotates caller.
+// It returns a function to restore the original output.
+func RedirectStdLog(logger *zap.SugaredLogger) func() {
+	return RedirectStdLogAt(logger, zapcore.InfoLevel)
+}
+
+// RedirectStdLogAt is same as RedirectStdLog, but entries are logged at given level.
+// If given level is not supported, info level is used.
+func RedirectStdLogAt(logger *zap.SugaredLogger, level zapcore.Level) func() {
+	restore, err := zap.RedirectStdLogAt(logger.Desugar(), level)
+	if err != nil {
+		return zap.RedirectStdLog(logger.Desugar())
+	}
+	return restore
+}
+
+// NewStdLogAt returns a *log.Logger which writes to default logger at given level,
+// to be given to APIs which require a *log.Logger.
+// If given level is not supported, info level is used.
+func NewStdLogAt(level zapcore.Level) *log.Logger {
+	return newStdLogAt(DefaultStructuredLogger(), level)
+}
+
+// newStdLogAt returns a *log.Logger which writes to given logger at given level.
+func newStdLogAt(logger *zap.Logger, level zapcore.Level) *log.Logger {
+	std, err := zap.NewStdLogAt(logger, level)
+	if err != nil {
+		return zap.NewStdLog(logger)
+	}
+	return std
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestRedirectStdLog is not parallel, because it replaces the output of the standard log package.
+func TestRedirectStdLog(t *testing.T) {
+	original := log.Writer()
+	core, logs := observer.New(zap.DebugLevel)
+	restore := RedirectStdLogAt(zap.New(core, zap.AddCaller()).Sugar(), zapcore.WarnLevel)
+	log.Print("from std log")
+	restore()
+
+	if log.Writer() != original {
+		t.Error("expect the output to be restored, but not restored")
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("from std log", entries[0].Message); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(zapcore.WarnLevel, entries[0].Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if !strings.HasSuffix(entries[0].Caller.File, "stdlog_test.go") {
+		t.Errorf("expect caller to be stdlog_test.go, but received %s", entries[0].Caller.File)
+	}
+}
+
+func TestNewStdLogAt(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	std := newStdLogAt(zap.New(core), zapcore.ErrorLevel)
+	std.Print("from std logger")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff(zapcore.ErrorLevel, entries[0].Level); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
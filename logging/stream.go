@@ -0,0 +1,231 @@
+package logging
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// liveStreamBufferSize is a number of entries buffered for each client of LiveStreamHandler.
+const liveStreamBufferSize = 256
+
+// liveStream is a broadcaster shared by every logger created with WithLiveStream.
+var liveStream = newLiveBroadcaster(liveStreamBufferSize)
+
+// WithLiveStream makes the logger send entries to clients of LiveStreamHandler, regardless of its level.
+// Entries are encoded only while clients are connected, and only at levels requested by them.
+func WithLiveStream() Option {
+	return func(o *options) {
+		o.cores = append(o.cores, newLiveCore(liveStream))
+	}
+}
+
+// LiveStreamHandler returns a http.Handler which streams entries of loggers created with WithLiveStream
+// to connected clients as server-sent events, each of which has an entry encoded as JSON in its data.
+// Query parameter "level" sets the minimum level, debug by default, and "component" limits entries to loggers
+// named with the component or its children. Clients which do not keep up are disconnected with an "evicted" event,
+// and their entries are counted as log_entries_dropped_total{sink="stream"}.
+func LiveStreamHandler() http.Handler {
+	return liveStreamHandler(liveStream)
+}
+
+// liveStreamHandler returns a http.Handler which streams entries of given broadcaster.
+func liveStreamHandler(b *liveBroadcaster) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		level := zapcore.DebugLevel
+		if l := r.URL.Query().Get("level"); l != "" {
+			var ok bool
+			if level, ok = parseLevel(l); !ok {
+				http.Error(w, fmt.Sprintf("unknown level %q", l), http.StatusBadRequest)
+				return
+			}
+		}
+
+		rc := http.NewResponseController(w)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		sub := b.subscribe(level, r.URL.Query().Get("component"))
+		defer b.unsubscribe(sub)
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-sub.evicted:
+				_, _ = fmt.Fprint(w, "event: evicted\ndata: {}\n\n")
+				_ = rc.Flush()
+				return
+			case entry := <-sub.entries:
+				if _, err := fmt.Fprintf(w, "data: %s\n\n", entry); err != nil {
+					return
+				}
+				if err := rc.Flush(); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+// noSubscribers is a minimum level of a broadcaster without subscribers, which disables every level.
+const noSubscribers = math.MaxInt32
+
+// liveBroadcaster fans out encoded entries to subscribers.
+type liveBroadcaster struct {
+	bufferSize int
+
+	// minLevel is the minimum level requested by subscribers, or noSubscribers.
+	minLevel atomic.Int32
+
+	// mu guards subs.
+	mu   sync.RWMutex
+	subs map[*liveSubscriber]struct{}
+}
+
+// newLiveBroadcaster creates a broadcaster which buffers given number of entries for each subscriber.
+func newLiveBroadcaster(bufferSize int) *liveBroadcaster {
+	b := &liveBroadcaster{bufferSize: bufferSize, subs: make(map[*liveSubscriber]struct{})}
+	b.minLevel.Store(noSubscribers)
+	return b
+}
+
+// liveSubscriber is a client of a broadcaster.
+type liveSubscriber struct {
+	level     zapcore.Level
+	component string
+	entries   chan []byte
+
+	// evicted is closed when the subscriber is evicted for not keeping up.
+	evicted   chan struct{}
+	evictOnce sync.Once
+}
+
+// matches reports whether entries of given level and logger name are sent to the subscriber.
+func (s *liveSubscriber) matches(level zapcore.Level, name string) bool {
+	if level < s.level {
+		return false
+	}
+	return s.component == "" || name == s.component || strings.HasPrefix(name, s.component+".")
+}
+
+// enabled reports whether any subscriber requests entries of given level.
+func (b *liveBroadcaster) enabled(level zapcore.Level) bool {
+	return int32(level) >= b.minLevel.Load()
+}
+
+// subscribe adds a subscriber of entries at or above given level of given component.
+func (b *liveBroadcaster) subscribe(level zapcore.Level, component string) *liveSubscriber {
+	sub := &liveSubscriber{
+		level:     level,
+		component: component,
+		entries:   make(chan []byte, b.bufferSize),
+		evicted:   make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[sub] = struct{}{}
+	b.updateMinLevel()
+	return sub
+}
+
+// unsubscribe removes given subscriber.
+func (b *liveBroadcaster) unsubscribe(sub *liveSubscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs, sub)
+	b.updateMinLevel()
+}
+
+// updateMinLevel recomputes the minimum level of subscribers. b.mu must be held.
+func (b *liveBroadcaster) updateMinLevel() {
+	lowest := int32(noSubscribers)
+	for sub := range b.subs {
+		lowest = min(lowest, int32(sub.level))
+	}
+	b.minLevel.Store(lowest)
+}
+
+// broadcast sends given encoded entry to matching subscribers, evicting subscribers whose buffer is full.
+func (b *liveBroadcaster) broadcast(level zapcore.Level, name string, entry []byte) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subs {
+		if !sub.matches(level, name) {
+			continue
+		}
+		select {
+		case sub.entries <- entry:
+		default:
+			droppedEntries.WithLabelValues("stream").Inc()
+			sub.evictOnce.Do(func() { close(sub.evicted) })
+		}
+	}
+}
+
+// liveCore is a zapcore.Core which sends entries to a broadcaster.
+type liveCore struct {
+	enc zapcore.Encoder
+	b   *liveBroadcaster
+}
+
+// newLiveCore creates a core which sends entries to given broadcaster as JSON.
+func newLiveCore(b *liveBroadcaster) *liveCore {
+	return &liveCore{enc: zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), b: b}
+}
+
+// Enabled reports whether any client requests entries of given level.
+func (c *liveCore) Enabled(level zapcore.Level) bool {
+	return c.b.enabled(level)
+}
+
+// With returns a child core with given fields.
+func (c *liveCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	return &liveCore{enc: enc, b: c.b}
+}
+
+// Check adds the core to given checked entry if any client requests its level.
+func (c *liveCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write encodes given entry once and sends it to matching clients.
+func (c *liveCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	entry := append([]byte(nil), trimLineEnding(buf.Bytes())...)
+	buf.Free()
+
+	c.b.broadcast(ent.Level, ent.LoggerName, entry)
+	return nil
+}
+
+// Sync does nothing, because entries are sent without buffering.
+func (c *liveCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// waitSubscribers waits until given broadcaster has given number of subscribers.
+func waitSubscribers(t *testing.T, b *liveBroadcaster, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		b.mu.RLock()
+		count := len(b.subs)
+		b.mu.RUnlock()
+		if count == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expect %d subscribers, but not reached", n)
+}
+
+func TestLiveStreamHandler(t *testing.T) {
+	t.Parallel()
+
+	b := newLiveBroadcaster(liveStreamBufferSize)
+	server := httptest.NewServer(liveStreamHandler(b))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"?level=warn&component=db", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	defer resp.Body.Close()
+	if diff := cmp.Diff("text/event-stream", resp.Header.Get("Content-Type")); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	waitSubscribers(t, b, 1)
+
+	logger := zap.New(newLiveCore(b))
+	logger.Named("db").Info("ignored by level")
+	logger.Named("http").Warn("ignored by component")
+	logger.Named("db").Named("pool").Warn("exhausted", zap.Int("size", 10))
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: ")
+	if !ok {
+		t.Fatalf("expect data line, but received %q", line)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(data), &entry); err != nil {
+		t.Fatalf("expect JSON entry, but received %v", err)
+	}
+	for key, want := range map[string]any{"msg": "exhausted", "logger": "db.pool", "size": float64(10)} {
+		if diff := cmp.Diff(want, entry[key]); diff != "" {
+			t.Errorf("%s: (-want, +got)\n%s", key, diff)
+		}
+	}
+
+	cancel()
+	waitSubscribers(t, b, 0)
+	if b.enabled(zapcore.FatalLevel) {
+		t.Error("expect every level disabled without subscribers, but enabled")
+	}
+}
+
+func TestLiveStreamHandlerBadRequest(t *testing.T) {
+	t.Parallel()
+
+	handler := liveStreamHandler(newLiveBroadcaster(1))
+	cases := []struct {
+		method string
+		target string
+		status int
+	}{
+		{method: http.MethodPost, target: "/", status: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, target: "/?level=loud", status: http.StatusBadRequest},
+	}
+	for _, cs := range cases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(cs.method, cs.target, nil))
+		if diff := cmp.Diff(cs.status, rec.Code); diff != "" {
+			t.Errorf("%s %s: (-want, +got)\n%s", cs.method, cs.target, diff)
+		}
+	}
+}
+
+func TestLiveBroadcasterEvictsSlowClient(t *testing.T) {
+	t.Parallel()
+
+	b := newLiveBroadcaster(2)
+	slow := b.subscribe(zapcore.DebugLevel, "")
+	if !b.enabled(zapcore.DebugLevel) {
+		t.Fatal("expect debug level enabled, but disabled")
+	}
+
+	for i := 0; i < 3; i++ {
+		b.broadcast(zapcore.InfoLevel, "", []byte("{}"))
+	}
+
+	select {
+	case <-slow.evicted:
+	default:
+		t.Error("expect the slow client to be evicted, but not evicted")
+	}
+	if diff := cmp.Diff(2, len(slow.entries)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,242 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// SyslogFacility is a facility of syslog messages defined in RFC 5424.
+type SyslogFacility int
+
+// Facilities of syslog messages.
+const (
+	SyslogUser   SyslogFacility = 1
+	SyslogMail   SyslogFacility = 2
+	SyslogDaemon SyslogFacility = 3
+	SyslogAuth   SyslogFacility = 4
+	SyslogLocal0 SyslogFacility = 16
+	SyslogLocal1 SyslogFacility = 17
+	SyslogLocal2 SyslogFacility = 18
+	SyslogLocal3 SyslogFacility = 19
+	SyslogLocal4 SyslogFacility = 20
+	SyslogLocal5 SyslogFacility = 21
+	SyslogLocal6 SyslogFacility = 22
+	SyslogLocal7 SyslogFacility = 23
+)
+
+// syslogSockets is a list of sockets of local syslog daemon.
+var syslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogConfig is a configuration of syslog destination.
+type SyslogConfig struct {
+	// Network is a network of remote syslog server such as "udp", "tcp", or "unix".
+	// If empty, local syslog daemon is used.
+	Network string
+
+	// Address is an address of remote syslog server such as "localhost:514".
+	Address string
+
+	// Facility is a facility of messages. Zero value means SyslogUser.
+	Facility SyslogFacility
+
+	// Tag is an APP-NAME of messages. If empty, the name of the executable is used.
+	Tag string
+
+	// Hostname is a HOSTNAME of messages. If empty, the hostname of the machine is used.
+	Hostname string
+}
+
+// WithSyslog ships entries to syslog in RFC 5424 format, in addition to the logger's own output.
+// Entries are filtered at the logger's level and component levels.
+func WithSyslog(config SyslogConfig) Option {
+	return func(o *options) {
+		o.leveledCores = append(o.leveledCores, NewSyslogCore(config))
+	}
+}
+
+// syslogCore is a zapcore.Core which writes entries to syslog.
+type syslogCore struct {
+	enc zapcore.Encoder
+	w   *syslogWriter
+}
+
+// NewSyslogCore creates a core which writes every entry to syslog in RFC 5424 format.
+// The priority of messages is derived from the level of entries,
+// and the message and fields are written as JSON in MSG part.
+// The connection is established lazily, and it is reestablished with backoff if writing fails.
+// Connecting and writing time out in a second, and messages failed to write are dropped
+// and counted as log_entries_dropped_total{sink="syslog"}.
+func NewSyslogCore(config SyslogConfig) zapcore.Core {
+	if config.Facility == 0 {
+		config.Facility = SyslogUser
+	}
+	if config.Tag == "" {
+		config.Tag = filepath.Base(os.Args[0])
+	}
+	if config.Hostname == "" {
+		config.Hostname, _ = os.Hostname()
+	}
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		MessageKey:     "msg",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		StacktraceKey:  "stacktrace",
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	})
+	w := &syslogWriter{config: config, pid: os.Getpid()}
+	w.conn = newSinkConn(w.dial)
+	return &syslogCore{enc: enc, w: w}
+}
+
+// Enabled reports true for every level.
+func (c *syslogCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With returns a child core with given fields.
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	return &syslogCore{enc: enc, w: c.w}
+}
+
+// Check adds the core to given checked entry.
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+// Write encodes given entry and writes it to syslog.
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	msg, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer msg.Free()
+	return c.w.write(ent, msg.Bytes())
+}
+
+// Sync does nothing, because messages are written without buffering.
+func (c *syslogCore) Sync() error {
+	return nil
+}
+
+// syslogPool is a pool of buffers used to format syslog messages.
+var syslogPool = buffer.NewPool()
+
+// syslogWriter formats and writes messages to a syslog connection.
+type syslogWriter struct {
+	config SyslogConfig
+	pid    int
+	conn   *sinkConn
+}
+
+// write formats given message of the entry and writes it.
+// If writing fails, the message is dropped and the connection is reestablished on next write.
+func (w *syslogWriter) write(ent zapcore.Entry, msg []byte) error {
+	buf := syslogPool.Get()
+	defer buf.Free()
+	w.format(buf, ent, msg)
+
+	if err := w.conn.write(w.frame(buf.Bytes())); err != nil {
+		droppedEntries.WithLabelValues("syslog").Inc()
+		return fmt.Errorf("logging: failed to write to syslog: %w", err)
+	}
+	return nil
+}
+
+// format writes an RFC 5424 message, such as "<14>1 2006-01-02T15:04:05.000000Z host app 42 - - msg".
+func (w *syslogWriter) format(buf *buffer.Buffer, ent zapcore.Entry, msg []byte) {
+	buf.AppendByte('<')
+	buf.AppendInt(int64(w.config.Facility)*8 + int64(syslogSeverity(ent.Level)))
+	buf.AppendString(">1 ")
+	buf.AppendTime(ent.Time.UTC(), "2006-01-02T15:04:05.000000Z07:00")
+	buf.AppendByte(' ')
+	buf.AppendString(syslogHeaderValue(w.config.Hostname))
+	buf.AppendByte(' ')
+	buf.AppendString(syslogHeaderValue(w.config.Tag))
+	buf.AppendByte(' ')
+	buf.AppendString(strconv.Itoa(w.pid))
+	buf.AppendString(" - - ")
+	_, _ = buf.Write(trimLineEnding(msg))
+}
+
+// frame returns given message framed for the network.
+// Stream connections use octet counting defined in RFC 6587, and others send a message per datagram.
+func (w *syslogWriter) frame(msg []byte) []byte {
+	switch w.config.Network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	default:
+		return msg
+	}
+}
+
+// dial connects to the configured syslog server, or the local syslog daemon if no network is configured.
+func (w *syslogWriter) dial() (net.Conn, error) {
+	if w.config.Network != "" {
+		conn, err := net.DialTimeout(w.config.Network, w.config.Address, sinkDialTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("logging: failed to connect to syslog: %w", err)
+		}
+		return conn, nil
+	}
+	for _, path := range syslogSockets {
+		if conn, err := net.Dial("unixgram", path); err == nil {
+			return conn, nil
+		}
+	}
+	return nil, errors.New("logging: local syslog daemon is not available")
+}
+
+// syslogSeverity converts given zap level to syslog severity.
+func syslogSeverity(l zapcore.Level) int {
+	switch l {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel:
+		return 2
+	case zapcore.PanicLevel:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// syslogHeaderValue returns given value as a header field, "-" if it is empty.
+// Characters not allowed in header fields are replaced with underscores.
+func syslogHeaderValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	b := []byte(s)
+	for i, c := range b {
+		if c <= ' ' || c > '~' {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// trimLineEnding trims trailing line endings of given bytes.
+func trimLineEnding(b []byte) []byte {
+	for len(b) > 0 && (b[len(b)-1] == '\n' || b[len(b)-1] == '\r') {
+		b = b[:len(b)-1]
+	}
+	return b
+}
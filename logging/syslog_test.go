@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"bufio"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithSyslog(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	defer conn.Close()
+
+	logger := NewLogger(
+		WithWriteSyncer(&zaptest.Buffer{}),
+		WithSyslog(SyslogConfig{Network: "udp", Address: conn.LocalAddr().String(), Facility: SyslogLocal0, Tag: "app", Hostname: "host"}),
+	)
+	logger.Debug("ignored")
+	logger.Warnw("slow", "elapsed", 3)
+
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	buf := make([]byte, 4096)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	// LOCAL0 (16) * 8 + WARNING (4) = 132
+	pattern := regexp.MustCompile(`^<132>1 \d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{6}Z host app \d+ - - \{"caller":"logging/syslog_test.go:\d+","msg":"slow","elapsed":3\}$`)
+	if msg := string(buf[:n]); !pattern.MatchString(msg) {
+		t.Errorf("expect RFC 5424 message, but received %q", msg)
+	}
+}
+
+func TestSyslogTCPFraming(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('}')
+		received <- line
+	}()
+
+	core := NewSyslogCore(SyslogConfig{Network: "tcp", Address: ln.Addr().String(), Tag: "app", Hostname: "host"})
+	if err := core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Time: time.Now(), Message: "failed"}, nil); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		length, rest, _ := strings.Cut(msg, " ")
+		if diff := cmp.Diff(length, strconv.Itoa(len(rest))); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+		// USER (1) * 8 + ERROR (3) = 11
+		if !strings.HasPrefix(rest, "<11>1 ") {
+			t.Errorf("expect priority 11, but received %q", rest)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expect a message, but timed out")
+	}
+}
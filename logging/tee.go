@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SinkConfig is a configuration of an additional destination written via WithTee.
+type SinkConfig struct {
+	// Encoding is a name of encoder such as "json", "console", or "logfmt".
+	// If empty, the encoding of the logger is used.
+	Encoding string
+
+	// Level is a level enabler of the sink, such as zapcore.Level or zap.AtomicLevel.
+	// If nil, the level of the logger is used.
+	Level zapcore.LevelEnabler
+
+	// EncoderConfig is an encoder configuration of the sink.
+	// If nil, the encoder configuration of the logger is used.
+	EncoderConfig *zapcore.EncoderConfig
+
+	// Color reports whether levels are colored. It is useful with console encoding.
+	Color bool
+
+	// OutputPaths is a list of paths to write logs.
+	OutputPaths []string
+
+	// Writers is a list of writers to write logs.
+	Writers []zapcore.WriteSyncer
+}
+
+// WithTee adds destinations written simultaneously with the logger's own output.
+// Each sink has its own encoding and level, e.g. JSON to a file at info and colored console at debug.
+func WithTee(sinks ...SinkConfig) Option {
+	return func(o *options) {
+		o.sinks = append(o.sinks, sinks...)
+	}
+}
+
+// newSinkCore creates a core from given sink configuration.
+// Empty values of the sink are filled with the logger's encoding and level.
+// Component levels are applied only if the sink uses the logger's level.
+// It also returns a function to close opened paths, or nil if there is nothing to close.
+func newSinkCore(sink SinkConfig, config zapcore.EncoderConfig, encoding string, level zapcore.LevelEnabler, components *componentLevels) (zapcore.Core, closeFunc, error) {
+	if sink.Encoding != "" {
+		encoding = sink.Encoding
+	}
+	if sink.EncoderConfig != nil {
+		config = *sink.EncoderConfig
+	}
+	if sink.Color {
+		config.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+	enc, err := newEncoder(encoding, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	writers := append([]zapcore.WriteSyncer(nil), sink.Writers...)
+	var closeSink closeFunc
+	if len(sink.OutputPaths) > 0 {
+		ws, closePaths, err := openPaths(sink.OutputPaths)
+		if err != nil {
+			return nil, nil, err
+		}
+		writers = append(writers, ws)
+		closeSink = closePaths
+	}
+
+	ws := zap.CombineWriteSyncers(writers...)
+	if sink.Level != nil {
+		return zapcore.NewCore(enc, ws, sink.Level), closeSink, nil
+	}
+	return newLeveledCore(enc, ws, level, zapcore.DebugLevel, components), closeSink, nil
+}
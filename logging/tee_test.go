@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+func TestWithTee(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "app.json")
+	consolePath := filepath.Join(dir, "app.txt")
+
+	logger := NewLogger(
+		WithLevel("info"),
+		WithOutputPaths(jsonPath),
+		WithTee(SinkConfig{
+			Encoding:    "console",
+			Level:       zap.DebugLevel,
+			OutputPaths: []string{consolePath},
+		}),
+	)
+	logger.Debug("debug message")
+	logger.Info("info message")
+	_ = logger.Sync()
+
+	entries := readEntries(t, jsonPath)
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("info message", entries[0]["msg"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	data, err := os.ReadFile(consolePath)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expect 2 lines, but received %d", len(lines))
+	}
+	if !strings.HasPrefix(strings.SplitN(lines[0], "\t", 2)[1], "debug\t") || !strings.HasSuffix(lines[0], "\tdebug message") {
+		t.Errorf("expect console encoded debug line, but received %q", lines[0])
+	}
+}
+
+func TestWithTeeDefaults(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tee.json")
+	logger := NewLogger(WithLevel("warn"), WithOutputPaths(os.DevNull), WithTee(SinkConfig{OutputPaths: []string{path}}))
+	logger.Info("ignored")
+	logger.Warn("written")
+	_ = logger.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, but received %d", len(entries))
+	}
+	if diff := cmp.Diff("warn", entries[0]["level"]); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// traceIDKey is a field key of OpenTelemetry trace ID.
+	traceIDKey = "trace_id"
+
+	// spanIDKey is a field key of OpenTelemetry span ID.
+	spanIDKey = "span_id"
+)
+
+// traceFieldsEnabled reports whether FromContext adds trace fields to the logger.
+// It is disabled by default.
+var traceFieldsEnabled atomic.Bool
+
+// EnableTraceFields switches whether FromContext adds trace_id and span_id fields
+// when given context carries a valid OpenTelemetry span.
+// It is disabled by default.
+func EnableTraceFields(enabled bool) {
+	traceFieldsEnabled.Store(enabled)
+}
+
+// traceFields returns trace_id and span_id fields from the span stored in given context.
+// If trace fields are disabled and correlated is false, or the context has no valid span, it will return nil.
+// correlated reports whether the context is created by ExtractCorrelation.
+func traceFields(ctx context.Context, correlated bool) []zap.Field {
+	if !traceFieldsEnabled.Load() && !correlated {
+		return nil
+	}
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+	return []zap.Field{
+		zap.String(traceIDKey, spanContext.TraceID().String()),
+		zap.String(spanIDKey, spanContext.SpanID().String()),
+	}
+}
@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestTraceFields(t *testing.T) {
+	t.Cleanup(func() {
+		EnableTraceFields(false)
+	})
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := WithLogger(context.Background(), zap.New(core).Sugar())
+	ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	FromContext(ctx).Info("disabled")
+
+	EnableTraceFields(true)
+	FromContext(ctx).Info("enabled")
+	StructuredFromContext(ctx).Info("structured")
+	if fields := traceFields(context.Background(), false); fields != nil {
+		t.Errorf("expect no fields without span, but received %v", fields)
+	}
+
+	wants := []map[string]any{
+		{},
+		{"trace_id": "01000000000000000000000000000000", "span_id": "0200000000000000"},
+		{"trace_id": "01000000000000000000000000000000", "span_id": "0200000000000000"},
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != len(wants) {
+		t.Fatalf("expect %d entries, but received %d", len(wants), len(entries))
+	}
+	for i, want := range wants {
+		if diff := cmp.Diff(want, entries[i].ContextMap()); diff != "" {
+			t.Errorf("entry %d (-want, +got)\n%s", i, diff)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Writer returns a writer which logs each line written to it at given level, using the logger from given context.
+// It is intended for integration points which accept only an io.Writer, such as log.New for http.Server.ErrorLog
+// and the stderr of exec.Cmd. Line endings are trimmed, and entries are not annotated with caller,
+// because the caller is not the code which produced the line.
+// Close the returned writer to log the last line which does not end with a newline.
+func Writer(ctx context.Context, level zapcore.Level) io.WriteCloser {
+	return &lineWriter{
+		logger: StructuredFromContext(ctx).WithOptions(zap.WithCaller(false)),
+		level:  level,
+	}
+}
+
+// lineWriter is an io.WriteCloser which logs each line.
+type lineWriter struct {
+	logger *zap.Logger
+	level  zapcore.Level
+
+	// mu guards buf.
+	mu sync.Mutex
+
+	// buf holds the incomplete last line.
+	buf []byte
+}
+
+// Write logs complete lines in given bytes, and keeps the incomplete last line until a newline is written.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.log(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Close logs the incomplete last line if any.
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.log(w.buf)
+		w.buf = nil
+	}
+	return nil
+}
+
+// log logs given line without its line ending. w.mu must be held.
+func (w *lineWriter) log(line []byte) {
+	if ce := w.logger.Check(w.level, string(bytes.TrimSuffix(line, []byte("\r")))); ce != nil {
+		ce.Write()
+	}
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	ctx := WithFields(WithStructuredLogger(context.Background(), zap.New(core)), "command", "make")
+
+	w := Writer(ctx, zapcore.WarnLevel)
+	fmt.Fprint(w, "first line\nsecond ")
+	fmt.Fprint(w, "line\r\nlast line")
+	if err := w.Close(); err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+
+	var messages []string
+	for _, entry := range logs.AllUntimed() {
+		if entry.Level != zapcore.WarnLevel {
+			t.Errorf("expect warn level, but received %v", entry.Level)
+		}
+		if diff := cmp.Diff(map[string]any{"command": "make"}, entry.ContextMap()); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+		messages = append(messages, entry.Message)
+	}
+	if diff := cmp.Diff([]string{"first line", "second line", "last line"}, messages); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
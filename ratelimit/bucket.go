@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a limiter which holds up to burst tokens and refills them at rate tokens per second.
+// Each event takes a token. It is safe for concurrent use.
+type TokenBucket struct {
+	rate  float64
+	burst float64
+
+	// mu guards fields below.
+	mu     sync.Mutex
+	tokens float64
+
+	// last is when tokens are refilled last.
+	last time.Time
+
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+var _ Limiter = (*TokenBucket)(nil)
+
+// NewTokenBucket creates a full bucket which refills given rate of tokens per second and holds up to given burst.
+// If the burst is less than one, one is used. If the rate is not positive, tokens are never refilled.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	burst = max(burst, 1)
+	return &TokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now(), now: time.Now}
+}
+
+// Allow takes a token if one is available, and reports whether it is taken.
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(b.now())
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Wait takes a token, waiting until one is refilled if needed.
+// Waiters reserve tokens in order, and a reserved token is returned if the context is done before it.
+// If the context deadline comes before the token, Wait returns an error wrapping context.DeadlineExceeded without waiting.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	now := b.now()
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		b.mu.Unlock()
+		return nil
+	}
+	if b.rate <= 0 {
+		b.mu.Unlock()
+		<-ctx.Done()
+		return context.Cause(ctx)
+	}
+	wait := time.Duration(math.Ceil((1 - b.tokens) / b.rate * float64(time.Second)))
+	if err := checkDeadline(ctx, wait, now); err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	b.tokens--
+	b.mu.Unlock()
+
+	if err := sleep(ctx, wait); err != nil {
+		b.mu.Lock()
+		b.tokens = min(b.tokens+1, b.burst)
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Tokens returns the number of available tokens. It is negative while waiters have reserved tokens.
+func (b *TokenBucket) Tokens() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(b.now())
+	return b.tokens
+}
+
+// refill adds tokens refilled since the last refill. b.mu must be held.
+func (b *TokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 && b.rate > 0 {
+		b.tokens = min(b.tokens+elapsed.Seconds()*b.rate, b.burst)
+	}
+	b.last = now
+}
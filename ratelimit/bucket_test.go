@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeClock is a clock advanced manually.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1_700_000_000, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestTokenBucket creates a bucket using a fake clock.
+func newTestTokenBucket(rate float64, burst int) (*TokenBucket, *fakeClock) {
+	clock := newFakeClock()
+	b := NewTokenBucket(rate, burst)
+	b.now, b.last = clock.Now, clock.Now()
+	return b, clock
+}
+
+func TestTokenBucketAllow(t *testing.T) {
+	t.Parallel()
+
+	b, clock := newTestTokenBucket(2, 3)
+
+	var got []bool
+	for range 4 {
+		got = append(got, b.Allow())
+	}
+	if diff := cmp.Diff([]bool{true, true, true, false}, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if !b.Allow() {
+		t.Error("expect a refilled token to be allowed")
+	}
+	if b.Allow() {
+		t.Error("expect no token to be left")
+	}
+
+	clock.Advance(time.Hour)
+	if diff := cmp.Diff(3.0, b.Tokens()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	t.Parallel()
+
+	b := NewTokenBucket(100, 1)
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 3 {
+		if err := b.Wait(ctx); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("expect waiting for refills, but received %s", elapsed)
+	}
+}
+
+func TestTokenBucketWaitDeadline(t *testing.T) {
+	t.Parallel()
+
+	b, _ := newTestTokenBucket(1, 1)
+	b.Allow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect %v, but received %v", context.DeadlineExceeded, err)
+	}
+	if diff := cmp.Diff(0.0, b.Tokens()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestTokenBucketWaitCanceled(t *testing.T) {
+	t.Parallel()
+
+	b, _ := newTestTokenBucket(0.1, 1)
+	b.Allow()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- b.Wait(ctx) }()
+	for b.Tokens() >= 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expect %v, but received %v", context.Canceled, err)
+	}
+	if diff := cmp.Diff(0.0, b.Tokens()); diff != "" {
+		t.Errorf("expect the reserved token to be returned (-want, +got)\n%s", diff)
+	}
+}
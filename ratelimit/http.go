@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/aqyuki/util/logging"
+	"go.uber.org/zap"
+)
+
+// MiddlewareConfig is a configuration of Middleware.
+type MiddlewareConfig struct {
+	// Limiter holds limiters of each key.
+	Limiter *Keyed
+
+	// Key returns the key of given request, such as an API key or a tenant ID.
+	// If nil, the host of the remote address is used.
+	Key func(r *http.Request) string
+
+	// Wait reports whether requests over the limit wait for it, until the request context is done.
+	// If false, they are rejected immediately.
+	Wait bool
+}
+
+// Middleware returns a middleware which limits requests of each key, and responds 429 Too Many Requests
+// to requests over the limit. Rejections are logged at debug level via the logger of the request context.
+func Middleware(config MiddlewareConfig) func(http.Handler) http.Handler {
+	keyOf := config.Key
+	if keyOf == nil {
+		keyOf = remoteHost
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := keyOf(r)
+			var allowed bool
+			if config.Wait {
+				allowed = config.Limiter.Wait(r.Context(), key) == nil
+			} else {
+				allowed = config.Limiter.Allow(key)
+			}
+			if !allowed {
+				logging.StructuredFromContext(r.Context()).Debug("ratelimit: request rejected", zap.String("key", key))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// remoteHost returns the host of the remote address of given request.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Transport returns a http.RoundTripper which waits for given limiter before each request,
+// for client-side throttling. If base is nil, http.DefaultTransport is used.
+func Transport(base http.RoundTripper, limiter Limiter) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base, limiter: limiter}
+}
+
+// transport is a http.RoundTripper returned by Transport.
+type transport struct {
+	base    http.RoundTripper
+	limiter Limiter
+}
+
+// RoundTrip waits for the limiter until the request context is done, and sends given request.
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(r.Context()); err != nil {
+		if r.Body != nil {
+			_ = r.Body.Close()
+		}
+		return nil, err
+	}
+	return t.base.RoundTrip(r)
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewKeyed(KeyedConfig{New: func() Limiter { return NewTokenBucket(0, 1) }})
+	handler := Middleware(MiddlewareConfig{Limiter: limiter})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	var got []int
+	for _, addr := range []string{"192.0.2.1:1234", "192.0.2.1:5678", "192.0.2.2:1234"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		got = append(got, rec.Code)
+	}
+	want := []int{http.StatusNoContent, http.StatusTooManyRequests, http.StatusNoContent}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+// roundTripFunc is a http.RoundTripper implemented by a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	sent := 0
+	client := &http.Client{Transport: Transport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		sent++
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+	}), NewTokenBucket(0, 1))}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	_ = resp.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/", nil)
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect %v, but received %v", context.DeadlineExceeded, err)
+	}
+	if diff := cmp.Diff(1, sent); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultMaxKeys is a default maximum number of keys tracked by Keyed.
+const defaultMaxKeys = 10_000
+
+// KeyedConfig is a configuration of Keyed.
+type KeyedConfig struct {
+	// New creates a limiter of a new key, such as func() Limiter { return NewTokenBucket(10, 20) }.
+	New func() Limiter
+
+	// IdleTimeout is a duration after which limiters of unused keys are evicted. If zero, they are not evicted by time.
+	// It should be longer than the time the limiter takes to recover, or an evicted client gets a fresh limit.
+	IdleTimeout time.Duration
+
+	// MaxKeys is a maximum number of keys. The least recently used key is evicted beyond it. If zero, 10000 is used.
+	MaxKeys int
+}
+
+// Keyed holds a limiter for each key, such as a client IP or a tenant ID. It is safe for concurrent use.
+type Keyed struct {
+	config KeyedConfig
+
+	// mu guards fields below.
+	mu sync.Mutex
+
+	// entries holds keyed entries from the most recently used one.
+	entries *list.List
+	index   map[string]*list.Element
+
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+// keyedEntry is a limiter of a key.
+type keyedEntry struct {
+	key      string
+	limiter  Limiter
+	lastUsed time.Time
+}
+
+// NewKeyed creates an empty limiter map with given configuration.
+func NewKeyed(config KeyedConfig) *Keyed {
+	if config.MaxKeys <= 0 {
+		config.MaxKeys = defaultMaxKeys
+	}
+	return &Keyed{config: config, entries: list.New(), index: make(map[string]*list.Element), now: time.Now}
+}
+
+// Get returns the limiter of given key, creating it if needed.
+func (k *Keyed) Get(key string) Limiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := k.now()
+	k.evictIdle(now)
+	if elem, ok := k.index[key]; ok {
+		entry := elem.Value.(*keyedEntry)
+		entry.lastUsed = now
+		k.entries.MoveToFront(elem)
+		return entry.limiter
+	}
+
+	entry := &keyedEntry{key: key, limiter: k.config.New(), lastUsed: now}
+	k.index[key] = k.entries.PushFront(entry)
+	for k.entries.Len() > k.config.MaxKeys {
+		k.remove(k.entries.Back())
+	}
+	return entry.limiter
+}
+
+// Allow reports whether an event of given key may happen now, as same as Limiter.Allow.
+func (k *Keyed) Allow(key string) bool {
+	return k.Get(key).Allow()
+}
+
+// Wait blocks until an event of given key may happen, as same as Limiter.Wait.
+func (k *Keyed) Wait(ctx context.Context, key string) error {
+	return k.Get(key).Wait(ctx)
+}
+
+// Len returns the number of tracked keys.
+func (k *Keyed) Len() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.evictIdle(k.now())
+	return k.entries.Len()
+}
+
+// evictIdle removes entries unused for the idle timeout. k.mu must be held.
+func (k *Keyed) evictIdle(now time.Time) {
+	if k.config.IdleTimeout <= 0 {
+		return
+	}
+	for elem := k.entries.Back(); elem != nil; elem = k.entries.Back() {
+		if now.Sub(elem.Value.(*keyedEntry).lastUsed) < k.config.IdleTimeout {
+			return
+		}
+		k.remove(elem)
+	}
+}
+
+// remove removes given entry. k.mu must be held.
+func (k *Keyed) remove(elem *list.Element) {
+	k.entries.Remove(elem)
+	delete(k.index, elem.Value.(*keyedEntry).key)
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestKeyed creates a limiter map of buckets allowing one event, using a fake clock.
+func newTestKeyed(config KeyedConfig) (*Keyed, *fakeClock) {
+	clock := newFakeClock()
+	config.New = func() Limiter { return NewTokenBucket(0, 1) }
+	k := NewKeyed(config)
+	k.now = clock.Now
+	return k, clock
+}
+
+func TestKeyed(t *testing.T) {
+	t.Parallel()
+
+	k, _ := newTestKeyed(KeyedConfig{})
+	got := []bool{k.Allow("a"), k.Allow("a"), k.Allow("b")}
+	if diff := cmp.Diff([]bool{true, false, true}, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if k.Get("a") != k.Get("a") {
+		t.Error("expect the same limiter for the same key")
+	}
+}
+
+func TestKeyedMaxKeys(t *testing.T) {
+	t.Parallel()
+
+	k, _ := newTestKeyed(KeyedConfig{MaxKeys: 2})
+	k.Allow("a")
+	k.Allow("b")
+	k.Get("a")
+	k.Allow("c")
+
+	if diff := cmp.Diff(2, k.Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if k.Allow("a") {
+		t.Error("expect the recently used key to be kept")
+	}
+	if !k.Allow("b") {
+		t.Error("expect the least recently used key to be evicted")
+	}
+}
+
+func TestKeyedIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	k, clock := newTestKeyed(KeyedConfig{IdleTimeout: time.Minute})
+	k.Allow("a")
+	clock.Advance(30 * time.Second)
+	k.Allow("b")
+	clock.Advance(30 * time.Second)
+
+	if diff := cmp.Diff(1, k.Len()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if !k.Allow("a") {
+		t.Error("expect the idle key to be evicted")
+	}
+	if k.Allow("b") {
+		t.Error("expect the recently used key to be kept")
+	}
+}
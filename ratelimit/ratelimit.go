@@ -0,0 +1,42 @@
+// Package ratelimit provides rate limiters for client-side throttling and server middleware.
+//
+// TokenBucket allows bursts up to its capacity and refills at a steady rate, and SlidingWindow allows a number of
+// events in a rolling window of time. Both implement Limiter, so that they can be combined with Keyed to limit
+// each client separately, with Transport to throttle outgoing requests, or with Middleware to reject incoming requests.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Limiter limits a rate of events. It is implemented by *TokenBucket and *SlidingWindow.
+type Limiter interface {
+	// Allow reports whether an event may happen now. If true, the event is counted.
+	Allow() bool
+
+	// Wait blocks until an event may happen and counts it, or returns an error when given context is done
+	// or its deadline is too close to wait.
+	Wait(ctx context.Context) error
+}
+
+// sleep waits for given duration, or returns the error of given context when it is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// checkDeadline returns an error if given context is done before waiting given duration.
+func checkDeadline(ctx context.Context, wait time.Duration, now time.Time) error {
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(wait)) {
+		return fmt.Errorf("ratelimit: wait of %s exceeds context deadline: %w", wait, context.DeadlineExceeded)
+	}
+	return nil
+}
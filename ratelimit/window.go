@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SlidingWindow is a limiter which allows up to limit events in any window of time.
+// It approximates the rolling count from counts of the current and previous fixed windows,
+// weighting the previous one by its overlap with the rolling window, so that it needs constant memory.
+// It is safe for concurrent use.
+type SlidingWindow struct {
+	limit  int
+	window time.Duration
+
+	// mu guards fields below.
+	mu sync.Mutex
+
+	// start is when the current fixed window starts.
+	start    time.Time
+	current  int
+	previous int
+
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+var _ Limiter = (*SlidingWindow)(nil)
+
+// NewSlidingWindow creates a limiter which allows up to given limit of events in given window of time.
+func NewSlidingWindow(limit int, window time.Duration) *SlidingWindow {
+	return &SlidingWindow{limit: limit, window: max(window, 1), now: time.Now}
+}
+
+// Allow counts an event if the limit allows it, and reports whether it is counted.
+func (w *SlidingWindow) Allow() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.take(w.now()) == 0
+}
+
+// Wait counts an event, waiting until the limit allows it if needed.
+// If the context deadline comes before it, Wait returns an error wrapping context.DeadlineExceeded without waiting.
+func (w *SlidingWindow) Wait(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		w.mu.Lock()
+		now := w.now()
+		wait := w.take(now)
+		w.mu.Unlock()
+		if wait == 0 {
+			return nil
+		}
+		if err := checkDeadline(ctx, wait, now); err != nil {
+			return err
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Count returns the approximate number of events in the rolling window.
+func (w *SlidingWindow) Count() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.now()
+	w.advance(now)
+	return w.estimate(now)
+}
+
+// take counts an event and returns zero if the limit allows it,
+// or returns the duration until it may be allowed otherwise. w.mu must be held.
+func (w *SlidingWindow) take(now time.Time) time.Duration {
+	w.advance(now)
+	if w.estimate(now)+1 <= float64(w.limit) {
+		w.current++
+		return 0
+	}
+
+	elapsed := now.Sub(w.start)
+	if w.current+1 > w.limit || w.previous == 0 {
+		return w.window - elapsed
+	}
+	// The weight of the previous window decreases linearly until the count has room for an event.
+	excess := float64(w.previous+w.current+1-w.limit) / float64(w.previous)
+	return max(time.Duration(excess*float64(w.window))-elapsed, time.Millisecond)
+}
+
+// advance moves the fixed windows to the one containing given time. w.mu must be held.
+func (w *SlidingWindow) advance(now time.Time) {
+	if w.start.IsZero() {
+		w.start = now
+		return
+	}
+	switch elapsed := now.Sub(w.start); {
+	case elapsed < w.window:
+	case elapsed < 2*w.window:
+		w.previous, w.current = w.current, 0
+		w.start = w.start.Add(w.window)
+	default:
+		w.previous, w.current = 0, 0
+		w.start = now
+	}
+}
+
+// estimate returns the approximate count of the rolling window ending at given time. w.mu must be held.
+func (w *SlidingWindow) estimate(now time.Time) float64 {
+	weight := 1 - float64(now.Sub(w.start))/float64(w.window)
+	return float64(w.previous)*weight + float64(w.current)
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestSlidingWindow creates a limiter using a fake clock.
+func newTestSlidingWindow(limit int, window time.Duration) (*SlidingWindow, *fakeClock) {
+	clock := newFakeClock()
+	w := NewSlidingWindow(limit, window)
+	w.now = clock.Now
+	return w, clock
+}
+
+func TestSlidingWindowAllow(t *testing.T) {
+	t.Parallel()
+
+	w, clock := newTestSlidingWindow(4, time.Minute)
+
+	var got []bool
+	for range 5 {
+		got = append(got, w.Allow())
+	}
+	if diff := cmp.Diff([]bool{true, true, true, true, false}, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	// A quarter into the next window, three quarters of the previous count remain.
+	clock.Advance(75 * time.Second)
+	if diff := cmp.Diff(3.0, w.Count()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if !w.Allow() {
+		t.Error("expect an event to be allowed")
+	}
+	if w.Allow() {
+		t.Error("expect an event to be rejected")
+	}
+
+	clock.Advance(2 * time.Minute)
+	if diff := cmp.Diff(0.0, w.Count()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestSlidingWindowWait(t *testing.T) {
+	t.Parallel()
+
+	w := NewSlidingWindow(2, 20*time.Millisecond)
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 3 {
+		if err := w.Wait(ctx); err != nil {
+			t.Fatalf("expect no error, but received %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("expect waiting for the window, but received %s", elapsed)
+	}
+}
+
+func TestSlidingWindowWaitDeadline(t *testing.T) {
+	t.Parallel()
+
+	w, _ := newTestSlidingWindow(1, time.Minute)
+	w.Allow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := w.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect %v, but received %v", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,56 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrThrottled is joined to the error returned by Do when a retry is skipped because the budget is exhausted.
+var ErrThrottled = errors.New("retry: throttled by retry budget")
+
+// Budget limits retries shared across call sites, as same as retry throttling of gRPC.
+// It holds tokens up to a maximum: each failed attempt takes a token, and each successful attempt returns a fraction of a token.
+// Retries are allowed only while more than half of the maximum tokens remain,
+// so that retries stop when most calls to a dependency fail, instead of multiplying its load. It is safe for concurrent use.
+type Budget struct {
+	max   float64
+	ratio float64
+
+	// mu guards tokens.
+	mu     sync.Mutex
+	tokens float64
+}
+
+// NewBudget creates a budget with given maximum tokens, such as 10, and given tokens returned by each success, such as 0.1.
+func NewBudget(maxTokens, tokenRatio float64) *Budget {
+	return &Budget{max: maxTokens, ratio: tokenRatio, tokens: maxTokens}
+}
+
+// WithBudget shares given budget among calls of Do. The default is no budget.
+func WithBudget(b *Budget) Option {
+	return func(o *options) {
+		o.budget = b
+	}
+}
+
+// Tokens returns the number of remaining tokens.
+func (b *Budget) Tokens() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens
+}
+
+// success returns tokens for a successful attempt.
+func (b *Budget) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.tokens+b.ratio, b.max)
+}
+
+// failure takes a token for a failed attempt, and reports whether a retry is allowed.
+func (b *Budget) failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = max(b.tokens-1, 0)
+	return b.tokens > b.max/2
+}
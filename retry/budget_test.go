@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBudget(t *testing.T) {
+	t.Parallel()
+
+	budget := NewBudget(4, 0.5)
+	failing := func(context.Context) error { return errors.New("unavailable") }
+	opts := []Option{WithBudget(budget), MaxAttempts(0), ExponentialBackoff(time.Millisecond, time.Millisecond)}
+
+	// Retries are allowed while more than 2 tokens remain: 4 -> 3 -> 2 stops.
+	attempts := 0
+	err := Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return failing(ctx)
+	}, opts...)
+	if !errors.Is(err, ErrThrottled) {
+		t.Errorf("expect %v, but received %v", ErrThrottled, err)
+	}
+	if diff := cmp.Diff(2, attempts); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	// The budget is shared, so another call is throttled after its first attempt.
+	attempts = 0
+	_ = Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return failing(ctx)
+	}, opts...)
+	if diff := cmp.Diff(1, attempts); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	// Successes refill the budget.
+	for i := 0; i < 10; i++ {
+		_ = Do(context.Background(), func(context.Context) error { return nil }, opts...)
+	}
+	if diff := cmp.Diff(4.0, budget.Tokens()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestDoDeadline(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	attempts := 0
+	start := time.Now()
+	want := errors.New("unavailable")
+	err := Do(ctx, func(context.Context) error {
+		attempts++
+		return want
+	}, MaxAttempts(0), ExponentialBackoff(time.Second, time.Second))
+	if !errors.Is(err, want) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect %v and %v, but received %v", want, context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("expect no wait for backoff exceeding deadline, but waited %v", elapsed)
+	}
+	if diff := cmp.Diff(1, attempts); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aqyuki/util/logging"
+	"go.uber.org/zap"
+)
+
+// hedgedResult is a result of an attempt of Hedged.
+type hedgedResult[T any] struct {
+	value T
+	err   error
+}
+
+// Hedged calls given function, and calls it again concurrently if the first attempt has not completed within given delay,
+// to cut tail latency. It returns the result of the first successful attempt, and cancels the context of the other one.
+// If the first attempt fails before the delay, its error is returned without a second attempt.
+// If both attempts fail, their errors are joined. The delay should be around a high percentile of the latency,
+// such as p95, so that only slow calls are hedged. The function must be safe to call twice.
+func Hedged[T any](ctx context.Context, delay time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// results is buffered for both attempts, so that the loser does not block after returning.
+	results := make(chan hedgedResult[T], 2)
+	attempt := func() {
+		v, err := fn(ctx)
+		results <- hedgedResult[T]{value: v, err: err}
+	}
+	go attempt()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var zero T
+	select {
+	case r := <-results:
+		return r.value, r.err
+	case <-timer.C:
+		logging.StructuredFromContext(ctx).Debug("retry: hedged attempt started", zap.Duration("delay", delay))
+		go attempt()
+	case <-ctx.Done():
+		return zero, context.Cause(ctx)
+	}
+
+	var errs []error
+	for range 2 {
+		select {
+		case r := <-results:
+			if r.err == nil {
+				return r.value, nil
+			}
+			errs = append(errs, r.err)
+		case <-ctx.Done():
+			return zero, errors.Join(append(errs, context.Cause(ctx))...)
+		}
+	}
+	return zero, errors.Join(errs...)
+}
@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHedged(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	canceled := make(chan struct{})
+	got, err := Hedged(context.Background(), 10*time.Millisecond, func(ctx context.Context) (int, error) {
+		n := attempts.Add(1)
+		if n == 1 {
+			<-ctx.Done()
+			close(canceled)
+			return 0, ctx.Err()
+		}
+		return int(n), nil
+	})
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff(2, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("expect the slow attempt to be canceled")
+	}
+}
+
+func TestHedgedFast(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	want := errors.New("invalid")
+	_, err := Hedged(context.Background(), 50*time.Millisecond, func(context.Context) (int, error) {
+		attempts.Add(1)
+		return 0, want
+	})
+	if !errors.Is(err, want) {
+		t.Errorf("expect %v, but received %v", want, err)
+	}
+	if diff := cmp.Diff(int32(1), attempts.Load()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestHedgedBothFail(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	first, second := errors.New("first"), errors.New("second")
+	_, err := Hedged(context.Background(), time.Millisecond, func(context.Context) (int, error) {
+		if attempts.Add(1) == 1 {
+			time.Sleep(20 * time.Millisecond)
+			return 0, first
+		}
+		return 0, second
+	})
+	if !errors.Is(err, first) || !errors.Is(err, second) {
+		t.Errorf("expect both errors, but received %v", err)
+	}
+}
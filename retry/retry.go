@@ -0,0 +1,170 @@
+// Package retry provides retries of operations with exponential backoff and jitter.
+//
+//	err := retry.Do(ctx, func(ctx context.Context) error {
+//		return call(ctx)
+//	}, retry.MaxAttempts(5), retry.ExponentialBackoff(100*time.Millisecond, 10*time.Second), retry.Jitter(0.2))
+//
+// Errors are retried unless they are wrapped by Permanent, or the context is done.
+// Each failed attempt is logged at debug level with the logger of the context.
+// Hedged calls an operation twice concurrently when the first call is slow, instead of retrying it on failure.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/aqyuki/util/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultMaxAttempts is a default maximum number of attempts.
+	defaultMaxAttempts = 3
+
+	// defaultInitialBackoff and defaultMaxBackoff are default bounds of exponential backoff.
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+)
+
+// Option is an option of Do.
+type Option func(*options)
+
+// options is a set of options of Do.
+type options struct {
+	maxAttempts int
+	initial     time.Duration
+	max         time.Duration
+	jitter      float64
+	retryable   func(error) bool
+
+	// budget limits retries if not nil.
+	budget *Budget
+}
+
+// MaxAttempts sets the maximum number of attempts including the first one. The default is 3.
+// Zero or a negative number means no limit, and retries continue until the context is done.
+func MaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// ExponentialBackoff sets the duration to wait before the first retry, doubled on each retry up to maxBackoff.
+// The default is 100 milliseconds up to 10 seconds.
+func ExponentialBackoff(initial, maxBackoff time.Duration) Option {
+	return func(o *options) {
+		o.initial, o.max = initial, maxBackoff
+	}
+}
+
+// Jitter randomizes each backoff by up to given fraction of it, such as 0.2 for ±20%,
+// so that clients failed at once do not retry at once. The default is no jitter.
+func Jitter(fraction float64) Option {
+	return func(o *options) {
+		o.jitter = min(max(fraction, 0), 1)
+	}
+}
+
+// RetryIf sets a function classifying errors returned by attempts as retryable.
+// Errors wrapped by Permanent are never retried regardless of it. The default retries every error.
+func RetryIf(retryable func(error) bool) Option {
+	return func(o *options) {
+		o.retryable = retryable
+	}
+}
+
+// permanentError is an error which is not retried.
+type permanentError struct {
+	err error
+}
+
+// Error returns the message of the wrapped error.
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps given error, so that Do returns it without retrying. It returns nil if given error is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether given error is wrapped by Permanent.
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Do calls given function until it succeeds, it returns a permanent error, attempts are exhausted, or the context is done.
+// It returns nil on success, or the error of the last attempt. An error wrapped by Permanent is returned unwrapped.
+// If the context is done while waiting, the error of the last attempt is returned joined with the error of the context.
+// If the backoff would exceed the deadline of the context, Do returns without waiting, joined with context.DeadlineExceeded.
+func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option) error {
+	o := &options{maxAttempts: defaultMaxAttempts, initial: defaultInitialBackoff, max: defaultMaxBackoff}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	logger := logging.StructuredFromContext(ctx)
+	backoff := o.initial
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			if o.budget != nil {
+				o.budget.success()
+			}
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if o.retryable != nil && !o.retryable(err) {
+			return err
+		}
+		if o.budget != nil && !o.budget.failure() {
+			logger.Debug("retry: throttled", zap.Int("attempt", attempt), logging.Err(err))
+			return errors.Join(err, ErrThrottled)
+		}
+		if o.maxAttempts > 0 && attempt >= o.maxAttempts {
+			logger.Debug("retry: attempts exhausted", zap.Int("attempt", attempt), logging.Err(err))
+			return err
+		}
+		if ctx.Err() != nil {
+			return errors.Join(err, context.Cause(ctx))
+		}
+
+		wait := o.withJitter(backoff)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			logger.Debug("retry: no time left for backoff", zap.Int("attempt", attempt), zap.Duration("backoff", wait), logging.Err(err))
+			return errors.Join(err, context.DeadlineExceeded)
+		}
+		logger.Debug("retry: attempt failed", zap.Int("attempt", attempt), zap.Duration("backoff", wait), logging.Err(err))
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, context.Cause(ctx))
+		}
+		backoff = min(backoff*2, o.max)
+	}
+}
+
+// withJitter randomizes given backoff by the jitter.
+func (o *options) withJitter(backoff time.Duration) time.Duration {
+	if o.jitter == 0 {
+		return backoff
+	}
+	delta := o.jitter * float64(backoff)
+	return time.Duration(float64(backoff) - delta + rand.Float64()*2*delta)
+}
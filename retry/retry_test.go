@@ -0,0 +1,128 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aqyuki/util/logging"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.DebugLevel)
+	ctx := logging.WithStructuredLogger(context.Background(), zap.New(core))
+
+	attempts := 0
+	err := Do(ctx, func(context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("unavailable")
+		}
+		return nil
+	}, MaxAttempts(5), ExponentialBackoff(time.Millisecond, 2*time.Millisecond), Jitter(0.5))
+	if err != nil {
+		t.Fatalf("expect no error, but received %v", err)
+	}
+	if diff := cmp.Diff(3, attempts); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	var got []any
+	for _, entry := range logs.FilterMessage("retry: attempt failed").AllUntimed() {
+		got = append(got, entry.ContextMap()["attempt"])
+	}
+	if diff := cmp.Diff([]any{int64(1), int64(2)}, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestDoExhausted(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	want := errors.New("unavailable")
+	err := Do(context.Background(), func(context.Context) error {
+		attempts++
+		return want
+	}, MaxAttempts(3), ExponentialBackoff(time.Millisecond, time.Millisecond))
+	if !errors.Is(err, want) {
+		t.Errorf("expect %v, but received %v", want, err)
+	}
+	if diff := cmp.Diff(3, attempts); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestDoPermanent(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	want := errors.New("invalid argument")
+	err := Do(context.Background(), func(context.Context) error {
+		attempts++
+		return Permanent(want)
+	})
+	if err != want {
+		t.Errorf("expect %v unwrapped, but received %v", want, err)
+	}
+	if diff := cmp.Diff(1, attempts); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if Permanent(nil) != nil {
+		t.Error("expect nil, but received an error")
+	}
+	if !IsPermanent(Permanent(want)) || IsPermanent(want) {
+		t.Error("expect only wrapped errors to be permanent")
+	}
+}
+
+func TestDoRetryIf(t *testing.T) {
+	t.Parallel()
+
+	retryable := errors.New("retryable")
+	attempts := 0
+	err := Do(context.Background(), func(context.Context) error {
+		attempts++
+		if attempts == 1 {
+			return retryable
+		}
+		return errors.New("fatal")
+	}, RetryIf(func(err error) bool { return errors.Is(err, retryable) }), ExponentialBackoff(time.Millisecond, time.Millisecond))
+	if err == nil || err.Error() != "fatal" {
+		t.Errorf("expect fatal, but received %v", err)
+	}
+	if diff := cmp.Diff(2, attempts); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestDoCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	want := errors.New("unavailable")
+	err := Do(ctx, func(context.Context) error {
+		cancel()
+		return want
+	}, MaxAttempts(0), ExponentialBackoff(time.Hour, time.Hour))
+	if !errors.Is(err, want) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expect %v and %v, but received %v", want, context.Canceled, err)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	t.Parallel()
+
+	o := &options{jitter: 0.2}
+	for i := 0; i < 100; i++ {
+		if d := o.withJitter(time.Second); d < 800*time.Millisecond || d > 1200*time.Millisecond {
+			t.Fatalf("expect backoff within 20%%, but received %v", d)
+		}
+	}
+}